@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/backup"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/flags"
+	"github.com/faysal0x1/Go-Learn/internal/seed"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+)
+
+// loadConfig loads the configuration and rejects values the server would
+// fail on at runtime, such as zero intervals.
+func loadConfig(configPath string) (*config.Config, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return cfg, nil
+}
+
+// openStorage loads the configuration and opens the database with an up to
+// date schema.
+func openStorage(configPath string) (*sqlite.Sqlite, error) {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlite.New(cfg)
+}
+
+// tenantFlag adds -tenant to commands that read or write one tenant's data.
+func tenantFlag(fs *flag.FlagSet) *string {
+	return fs.String("tenant", tenant.Default, "Tenant whose data to work on")
+}
+
+func runMigrate(args []string) error {
+	fs, configPath := newFlagSet("migrate")
+	fs.Parse(args)
+
+	storage, err := openStorage(*configPath)
+	if err != nil {
+		return err
+	}
+	defer storage.Db.Close()
+
+	fmt.Println("schema is up to date")
+	return nil
+}
+
+func runSeed(args []string) error {
+	defaults := seed.DefaultOptions()
+
+	fs, configPath := newFlagSet("seed")
+	tenantId := tenantFlag(fs)
+	fixture := fs.String("fixture", "", "Load a named fixture ("+strings.Join(seed.Fixtures(), ", ")+") or a .yaml file instead of generating data")
+	seedValue := fs.Uint64("seed", defaults.Seed, "Seed for the generator; the same seed always produces the same data")
+	students := fs.Int("students", defaults.Students, "Number of students to generate")
+	courses := fs.Int("courses", defaults.Courses, "Number of courses to generate")
+	maxEnrollments := fs.Int("enrollments", defaults.MaxEnrollments, "Most courses a generated student takes")
+	graded := fs.Float64("graded", defaults.GradedRatio, "Share of generated enrollments that have a grade (0-1)")
+	fs.Parse(args)
+
+	var dataset seed.Dataset
+
+	switch {
+	case strings.HasSuffix(*fixture, ".yaml") || strings.HasSuffix(*fixture, ".yml"):
+		var err error
+		if dataset, err = seed.LoadFile(*fixture); err != nil {
+			return err
+		}
+	case *fixture != "":
+		var err error
+		if dataset, err = seed.Fixture(*fixture); err != nil {
+			return err
+		}
+	default:
+		dataset = seed.Generate(seed.Options{
+			Seed:           *seedValue,
+			Students:       *students,
+			Courses:        *courses,
+			MaxEnrollments: *maxEnrollments,
+			GradedRatio:    *graded,
+		})
+	}
+
+	db, err := openStorage(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Db.Close()
+
+	result, err := db.ForTenant(*tenantId).Seed(dataset)
+	if err != nil {
+		return fmt.Errorf("seed %s: %w", dataset.Name, err)
+	}
+
+	fmt.Printf("seeded %s: students %d new, %d existing; courses %d new, %d existing; enrollments %d new, %d existing\n",
+		dataset.Name,
+		result.StudentsCreated, result.StudentsExisting,
+		result.CoursesCreated, result.CoursesExisting,
+		result.EnrollmentsCreated, result.EnrollmentsExisting)
+	return nil
+}
+
+func runExport(args []string) error {
+	fs, configPath := newFlagSet("export")
+	tenantId := tenantFlag(fs)
+	out := fs.String("out", "", "File to write to (default stdout)")
+	fs.Parse(args)
+
+	db, err := openStorage(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Db.Close()
+
+	dump, err := db.ForTenant(*tenantId).Export()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(dump)
+}
+
+func runImport(args []string) error {
+	fs, configPath := newFlagSet("import")
+	tenantId := tenantFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: students_api import [-config path] [-tenant id] <file|->")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("import: expected exactly one file")
+	}
+
+	var r io.Reader = os.Stdin
+
+	if name := fs.Arg(0); name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var dump types.Dump
+
+	if err := json.NewDecoder(r).Decode(&dump); err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	if err := validateDump(dump); err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	db, err := openStorage(*configPath)
+	if err != nil {
+		return err
+	}
+	defer db.Db.Close()
+
+	if err := db.ForTenant(*tenantId).Import(dump); err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	fmt.Printf("imported %d students, %d courses, %d enrollments\n", len(dump.Students), len(dump.Courses), len(dump.Enrollments))
+	return nil
+}
+
+// validateDump applies the API's validation rules so an import cannot
+// store rows the API would have rejected.
+func validateDump(dump types.Dump) error {
+	for _, student := range dump.Students {
+		if err := validate.Struct(student); err != nil {
+			return fmt.Errorf("student %d: %w", student.Id, err)
+		}
+		if !student.Status.IsValid() {
+			return fmt.Errorf("student %d: unknown status %q", student.Id, student.Status)
+		}
+	}
+
+	for _, course := range dump.Courses {
+		if err := validate.Struct(course); err != nil {
+			return fmt.Errorf("course %d: %w", course.Id, err)
+		}
+	}
+
+	for _, enrollment := range dump.Enrollments {
+		if grade := enrollment.Grade; grade != nil && (*grade < 0 || *grade > 4) {
+			return fmt.Errorf("enrollment %d: grade must be between 0 and 4", enrollment.Id)
+		}
+	}
+
+	return nil
+}
+
+func runCreateAPIKey(args []string) error {
+	fs, configPath := newFlagSet("create-api-key")
+	name := fs.String("name", "", "Who or what the key is for (required)")
+	tenantId := tenantFlag(fs)
+	fs.Parse(args)
+
+	if *name == "" {
+		fs.Usage()
+		return errors.New("create-api-key: -name is required")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	// A key for a tenant the server does not know would never open a
+	// request. Default keys belong to the operator and open the admin
+	// routes, so they can be issued whether or not that tenant serves data.
+	if *tenantId != tenant.Default && !slices.Contains(tenant.NewResolver(cfg.Tenancy).Ids(), *tenantId) {
+		return fmt.Errorf("create-api-key: unknown tenant %q", *tenantId)
+	}
+
+	storage, err := sqlite.New(cfg)
+	if err != nil {
+		return err
+	}
+	defer storage.Db.Close()
+
+	key, prefix, hash := apikey.Generate()
+
+	id, err := storage.ForTenant(*tenantId).CreateAPIKey(*name, prefix, hash)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "created api key %d (%s) for %q in tenant %s; it is not shown again\n", id, prefix, *name, *tenantId)
+	fmt.Println(key)
+	return nil
+}
+
+func runCheckConfig(args []string) error {
+	fs, configPath := newFlagSet("check-config")
+	fs.Parse(args)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	if err := checkJobs(cfg); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	featureFlags, err := flags.New(cfg.Flags)
+	if err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	fmt.Printf("env:          %s\n", cfg.Env)
+	fmt.Printf("storage_path: %s\n", cfg.StoragePath)
+	fmt.Printf("http_server:  %s\n", cfg.Addr)
+	fmt.Printf("grpc_server:  %s\n", cfg.GRPCServer.Addr)
+	fmt.Printf("api keys:     required=%t\n", cfg.Auth.RequireAPIKey)
+	fmt.Printf("tenancy:      enabled=%t tenants=%d\n", cfg.Tenancy.Enabled, len(cfg.Tenancy.Tenants))
+	fmt.Printf("scheduler:    enabled=%t jobs=%s\n", cfg.Scheduler.Enabled, strings.Join(jobNames(cfg), ","))
+	fmt.Printf("logging:      level=%s format=%s output=%s\n", cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.Output)
+	fmt.Printf("versioning:   default=v%d deprecated=%d\n", cfg.Versioning.Default, len(cfg.Versioning.Deprecations))
+	fmt.Printf("flags:        path=%s flags=%s\n", cfg.Flags.Path, strings.Join(featureFlags.Names(), ","))
+	fmt.Println("configuration OK")
+	return nil
+}
+
+func runBackup(args []string) error {
+	fs, configPath := newFlagSet("backup")
+	list := fs.Bool("list", false, "List existing backups instead of creating one")
+	fs.Parse(args)
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		backups, err := backup.NewManager(nil, cfg.Backup).List()
+		if err != nil {
+			return err
+		}
+
+		for _, b := range backups {
+			fmt.Printf("%s  %10d  %s\n", b.CreatedAt.Format(time.RFC3339), b.Size, b.Path)
+		}
+		return nil
+	}
+
+	storage, err := sqlite.New(cfg)
+	if err != nil {
+		return err
+	}
+	defer storage.Db.Close()
+
+	b, err := backup.NewManager(storage, cfg.Backup).Rotate(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created %s (%d bytes, sha256 %s)\n", b.Path, b.Size, b.SHA256)
+	return nil
+}
+
+func runRestore(args []string) error {
+	fs, configPath := newFlagSet("restore")
+	skipBackup := fs.Bool("no-backup", false, "Do not back up the current database before replacing it")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: students_api restore [-config path] [-no-backup] <backup file>")
+		fmt.Fprintln(fs.Output(), "Stop the server first; the database is replaced on disk.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("restore: expected exactly one backup file")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	if err := backup.Verify(fs.Arg(0)); err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+
+	if _, err := os.Stat(cfg.StoragePath); err == nil && !*skipBackup {
+		storage, err := sqlite.Open(cfg)
+		if err != nil {
+			return err
+		}
+
+		// Not Rotate: pruning could delete the backup being restored.
+		b, err := backup.NewManager(storage, cfg.Backup).Create(context.Background())
+		storage.Db.Close()
+		if err != nil {
+			return fmt.Errorf("restore: backing up current database: %w", err)
+		}
+
+		fmt.Printf("backed up current database to %s\n", b.Path)
+	}
+
+	if err := backup.Restore(fs.Arg(0), cfg.StoragePath); err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+
+	fmt.Printf("restored %s from %s\n", cfg.StoragePath, fs.Arg(0))
+	return nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/backup"
+	"github.com/faysal0x1/Go-Learn/internal/blob"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/flags"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/featureflags"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/jobs"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/mailsink"
+	"github.com/faysal0x1/Go-Learn/internal/http/middleware"
+	"github.com/faysal0x1/Go-Learn/internal/mail"
+	"github.com/faysal0x1/Go-Learn/internal/report"
+	"github.com/faysal0x1/Go-Learn/internal/scheduler"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// maintenanceJobs are the jobs scheduler.jobs may name.
+func maintenanceJobs(cfg *config.Config, db *sqlite.Sqlite, backups *backup.Manager, blobs *blob.Store) map[string]scheduler.Func {
+	return map[string]scheduler.Func{
+		"backup": func(ctx context.Context) error {
+			b, err := backups.Rotate(ctx)
+			if err != nil {
+				return err
+			}
+
+			slog.Info("backup created", slog.String("path", b.Path), slog.Int64("size", b.Size))
+			return nil
+		},
+		"purge-deliveries": func(ctx context.Context) error {
+			n, err := db.PruneDeliveries(ctx, time.Now().UTC().Add(-cfg.Webhooks.Retention))
+			if err != nil {
+				return err
+			}
+
+			slog.Info("webhook deliveries purged", slog.Int64("deleted", n))
+			return nil
+		},
+		"purge-mail": func(ctx context.Context) error {
+			n, err := db.PruneMail(ctx, time.Now().UTC().Add(-cfg.Mail.Retention))
+			if err != nil {
+				return err
+			}
+
+			slog.Info("mail queue purged", slog.Int64("deleted", n))
+			return nil
+		},
+		"purge-blobs": func(ctx context.Context) error {
+			n, err := blobs.Sweep(ctx, time.Now().Add(-blob.Grace), db.BlobReferenced)
+			if err != nil {
+				return err
+			}
+
+			slog.Info("unreferenced blobs purged", slog.Int("deleted", n))
+			return nil
+		},
+		"course-rosters": func(ctx context.Context) error {
+			for _, id := range tenant.NewResolver(cfg.Tenancy).Ids() {
+				n, err := writeRosters(ctx, cfg, db.ForTenant(id), id)
+				if err != nil {
+					return fmt.Errorf("tenant %s: %w", id, err)
+				}
+
+				slog.Info("course rosters written", slog.String("tenant", id), slog.Int("courses", n))
+			}
+			return nil
+		},
+	}
+}
+
+// writeRosters renders the roster of every course of one tenant into
+// reports.dir/<tenant>/roster-<code>.pdf, replacing the previous run's
+// files. The whole tenant is read in three queries.
+func writeRosters(ctx context.Context, cfg *config.Config, db *sqlite.Sqlite, tenantId string) (int, error) {
+	courses, err := db.GetCourses()
+	if err != nil {
+		return 0, err
+	}
+
+	courseIds := make([]int64, len(courses))
+	for i, course := range courses {
+		courseIds[i] = course.Id
+	}
+
+	enrollments, err := db.GetEnrollmentsByCourseIds(courseIds)
+	if err != nil {
+		return 0, err
+	}
+
+	studentIds := make([]int64, len(enrollments))
+	for i, enrollment := range enrollments {
+		studentIds[i] = enrollment.StudentId
+	}
+
+	students, err := db.GetStudentsByIds(studentIds)
+	if err != nil {
+		return 0, err
+	}
+
+	byId := make(map[int64]types.Student, len(students))
+	for _, student := range students {
+		byId[student.Id] = student
+	}
+
+	entries := make(map[int64][]report.RosterEntry, len(courses))
+	for _, enrollment := range enrollments {
+		entries[enrollment.CourseId] = append(entries[enrollment.CourseId], report.RosterEntry{Student: byId[enrollment.StudentId], Enrollment: enrollment})
+	}
+
+	dir := filepath.Join(cfg.Reports.Dir, tenantId)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, err
+	}
+
+	school := cmp.Or(cfg.Tenancy.Tenants[tenantId].Name, cfg.Reports.Institution)
+	issued := time.Now()
+
+	for i, course := range courses {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+
+		doc := report.Roster(report.PageSize(cfg.Reports.PageSize), school, course, entries[course.Id], issued)
+
+		// Readers never see a half-written file.
+		path := filepath.Join(dir, fmt.Sprintf("roster-%s.pdf", course.Code))
+		if err := os.WriteFile(path+".tmp", doc.Bytes(), 0o644); err != nil {
+			return i, err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return i, err
+		}
+	}
+
+	return len(courses), nil
+}
+
+// newScheduler registers the jobs configured in cfg.Scheduler.
+func newScheduler(cfg *config.Config, db *sqlite.Sqlite, backups *backup.Manager, blobs *blob.Store) (*scheduler.Scheduler, error) {
+	loc, err := time.LoadLocation(cfg.Scheduler.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	s := scheduler.New(loc)
+
+	if !cfg.Scheduler.Enabled {
+		return s, nil
+	}
+
+	available := maintenanceJobs(cfg, db, backups, blobs)
+
+	for name, job := range cfg.Scheduler.Jobs {
+		run, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("scheduler: unknown job %q", name)
+		}
+
+		err := s.Add(scheduler.Job{
+			Name:     name,
+			Schedule: job.Schedule,
+			Timeout:  job.Timeout,
+			Jitter:   job.Jitter,
+			Run:      run,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// checkJobs reports configured jobs that do not exist or whose schedule
+// does not parse.
+func checkJobs(cfg *config.Config) error {
+	_, err := newScheduler(cfg, nil, nil, nil)
+	return err
+}
+
+// jobNames lists the configured jobs for check-config.
+func jobNames(cfg *config.Config) []string {
+	var names []string
+	for name := range cfg.Scheduler.Jobs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// adminRoutes serves the server-wide admin endpoints ahead of the tenant
+// routers, since they are not about any one school. They are for the
+// operator, so a school's API key does not open them. The captured mail is
+// only listed when the mail sink runs.
+func adminRoutes(s *scheduler.Scheduler, sink *mail.Sink, set *flags.Set, resolver *tenant.Resolver, next http.Handler) http.Handler {
+	admin := http.NewServeMux()
+	admin.Handle("GET /api/admin/jobs", jobs.List(s))
+	admin.Handle("GET /api/admin/flags", featureflags.List(set, resolver))
+
+	if sink != nil {
+		admin.Handle("GET /api/admin/mail", mailsink.List(sink))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := admin.Handler(r); pattern == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := apikey.CheckTenant(r.Context(), tenant.Default); err != nil {
+			response.WriteJson(w, http.StatusForbidden, response.GeneralError(err))
+			return
+		}
+
+		admin.ServeHTTP(w, r)
+		middleware.Route(r)
+	})
+}
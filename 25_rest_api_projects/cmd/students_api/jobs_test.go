@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+)
+
+func newJobsConfig(t *testing.T) (*config.Config, *sqlite.Sqlite) {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := &config.Config{
+		StoragePath: filepath.Join(dir, "test.db"),
+		Reports:     config.Reports{Institution: "Students API", PageSize: "a4", Dir: filepath.Join(dir, "reports")},
+		Tenancy: config.Tenancy{
+			Enabled: true,
+			Sources: []string{"header"},
+			Header:  "X-Tenant-ID",
+			Tenants: map[string]config.Tenant{"north": {Name: "North High"}, "south": {}},
+		},
+	}
+
+	db, err := sqlite.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Db.Close() })
+
+	return cfg, db
+}
+
+func TestCourseRostersJobWritesEveryTenantsCourses(t *testing.T) {
+	cfg, db := newJobsConfig(t)
+	north := db.ForTenant("north")
+
+	student, err := north.CreateStudent("Ada", "ada@north.edu", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := north.CreateCourse("CS101", "Programming", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := north.Enroll(student, course); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ForTenant("south").CreateCourse("BIO1", "Biology", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := maintenanceJobs(cfg, db, nil, nil)["course-rosters"](context.Background()); err != nil {
+		t.Fatalf("course-rosters: %v", err)
+	}
+
+	for _, path := range []string{"north/roster-CS101.pdf", "south/roster-BIO1.pdf"} {
+		data, err := os.ReadFile(filepath.Join(cfg.Reports.Dir, path))
+		if err != nil || !bytes.HasPrefix(data, []byte("%PDF-")) {
+			t.Errorf("%s: %v, want a PDF", path, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(cfg.Reports.Dir, "north", "roster-BIO1.pdf")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("south's course was written into north's reports: %v", err)
+	}
+}
+
+// Jobs run under the context the scheduler cancels at their timeout, so
+// an expired context must stop them rather than be ignored.
+func TestJobsStopWhenTheirContextIsDone(t *testing.T) {
+	cfg, db := newJobsConfig(t)
+	if _, err := db.ForTenant("north").CreateCourse("CS101", "Programming", 4); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, name := range []string{"purge-deliveries", "purge-mail", "course-rosters"} {
+		if err := maintenanceJobs(cfg, db, nil, nil)[name](ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s with a cancelled context = %v, want context.Canceled", name, err)
+		}
+	}
+}
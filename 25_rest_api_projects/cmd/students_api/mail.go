@@ -0,0 +1,56 @@
+package main
+
+import (
+	"cmp"
+	"net"
+	"strconv"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/mail"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// mailDirectory looks records up in the tenant's view of the store.
+type mailDirectory struct {
+	db  *sqlite.Sqlite
+	cfg *config.Config
+}
+
+func (d mailDirectory) Student(tenant string, id int64) (types.Student, error) {
+	return d.db.ForTenant(tenant).GetStudentById(id)
+}
+
+func (d mailDirectory) Course(tenant string, id int64) (types.Course, error) {
+	return d.db.ForTenant(tenant).GetCourseById(id)
+}
+
+func (d mailDirectory) School(tenant string) string {
+	return cmp.Or(d.cfg.Tenancy.Tenants[tenant].Name, d.cfg.Reports.Institution)
+}
+
+// newMailer builds the configured transport. The sink transport starts an
+// in-process SMTP server and delivers to it over SMTP like a real server
+// would be; it is returned so its messages can be listed and it can be
+// closed.
+func newMailer(cfg config.Mail) (mail.Mailer, *mail.Sink, error) {
+	switch cfg.Transport {
+	case "file":
+		mailer, err := mail.NewFile(cfg.Dir)
+		return mailer, nil, err
+	case "sink":
+		sink, err := mail.NewSink(cfg.SinkAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		host, port, _ := net.SplitHostPort(sink.Addr())
+		smtp := cfg.SMTP
+		smtp.Host, smtp.TLS, smtp.Username = host, "none", ""
+		smtp.Port, _ = strconv.Atoi(port)
+
+		return mail.NewSMTP(smtp), sink, nil
+	default:
+		return mail.NewSMTP(cfg.SMTP), nil, nil
+	}
+}
@@ -1,73 +1,77 @@
+// Command students_api runs the Students API server and its admin tasks:
+//
+//	students_api [command] [-config path] [flags]
+//
+// Without a command it serves, so `students_api -config config/local.yaml`
+// keeps working.
 package main
 
 import (
-	"context"
-	"log"
+	"flag"
+	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/faysal0x1/Go-Learn/internal/config"
+	"strings"
 )
 
-func main() {
-	// Initialize the configuration
-
-	cfg := config.MustLoad()
-
-	// Database connection setup
-
-	// Setup router
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
 
-	router := http.NewServeMux()
-	router.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+var commands = []command{
+	{"serve", "run the HTTP and gRPC servers", runServe},
+	{"migrate", "create or upgrade the database schema", runMigrate},
+	{"seed", "insert generated or fixture data", runSeed},
+	{"export", "write students, courses and enrollments as JSON", runExport},
+	{"import", "load a file written by export", runImport},
+	{"backup", "snapshot the database into the backup directory", runBackup},
+	{"restore", "replace the database with a backup", runRestore},
+	{"create-api-key", "issue a new API key", runCreateAPIKey},
+	{"check-config", "load and validate the configuration", runCheckConfig},
+}
 
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("Welcome to the Students API!"))
-	})
+func main() {
+	args := os.Args[1:]
+	name := "serve"
 
-	server := http.Server{
-		Addr:    cfg.Addr,
-		Handler: router,
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
 
-	slog.Info("Starting Students API server", cfg.Addr, slog.String("env", cfg.Env), slog.String("storage_path", cfg.StoragePath))
-
-	// fmt.Println("Starting Students API server on", cfg.Addr)
-
-	done := make(chan os.Signal, 1)
-
-	signal.Notify(done, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-
-	// Handle graceful shutdown
-	go func() {
-		err := server.ListenAndServe()
+	if name == "help" {
+		usage()
+		return
+	}
 
-		if err != nil {
-			log.Fatal("Error starting server: ", err)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				slog.Error("Command failed", slog.String("command", name), slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+			return
 		}
-	}()
-
-	<-done
-
-	slog.Info("Shutting down server...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-
-	defer cancel()
-
-	err := server.Shutdown(ctx)
-
-	if err != nil {
-		slog.Error("Error shutting down server: ", slog.String("error", err.Error()))
 	}
 
-	slog.Info("Server gracefully stopped")
-	// Setup Server
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
 
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [command] [-config path] [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command.\n", os.Args[0])
 }
 
-// NewStudentsAPI initializes a new instance of the Students API.
+// newFlagSet returns the flag set for a command with the -config flag every
+// command shares. The configuration path falls back to CONFIG_PATH.
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to the configuration file (default $CONFIG_PATH)")
+	return fs, configPath
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/backup"
+	"github.com/faysal0x1/Go-Learn/internal/blob"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/flags"
+	"github.com/faysal0x1/Go-Learn/internal/http/middleware"
+	"github.com/faysal0x1/Go-Learn/internal/logging"
+	"github.com/faysal0x1/Go-Learn/internal/mail"
+	"github.com/faysal0x1/Go-Learn/internal/outbox"
+	"github.com/faysal0x1/Go-Learn/internal/rpc"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+	"github.com/faysal0x1/Go-Learn/internal/webhook"
+)
+
+func runServe(args []string) error {
+	fs, configPath := newFlagSet("serve")
+	fs.Parse(args)
+
+	// Initialize the configuration
+
+	cfg, err := loadConfig(*configPath)
+
+	if err != nil {
+		return err
+	}
+
+	// Log lines written with a request context carry its request id and
+	// trace ids. Once the server stops, logging goes back to stderr so the
+	// error main reports is not written to a closed log file.
+
+	logger, logFile, err := logging.New(cfg.Logging)
+
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(logger)
+
+	defer func() {
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+		logFile.Close()
+	}()
+
+	tracer, err := trace.New(cfg.Tracing)
+
+	if err != nil {
+		return err
+	}
+
+	tracer.Start()
+
+	// Database connection setup
+
+	storage, err := sqlite.New(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	defer storage.Db.Close()
+
+	slog.Info("Storage initialized", slog.String("env", cfg.Env))
+
+	// Student changes are written to the outbox with the change itself and
+	// relayed from there to the bus and any other configured sinks. On the
+	// bus, the webhook dispatcher queues a delivery for each matching
+	// subscription of the event's tenant, and the tenant registry hands the
+	// event to that tenant's live event stream and roster hub.
+
+	resolver := tenant.NewResolver(cfg.Tenancy)
+	limiter := tenant.NewLimiter()
+
+	bus := events.NewBus()
+
+	dispatcher := webhook.NewDispatcher(storage, cfg.Webhooks)
+	dispatcher.SetTracer(tracer)
+	bus.SubscribeQueue(dispatcher)
+	dispatcher.Start()
+
+	sinks, err := outbox.Sinks(cfg.Outbox, bus)
+
+	if err != nil {
+		return err
+	}
+
+	// Students are emailed when they enrol or change status. Messages are
+	// rendered into a queue in the database and sent from there.
+
+	var mailDispatcher *mail.Dispatcher
+	var mailSink *mail.Sink
+
+	if cfg.Mail.Enabled {
+		templates, err := mail.LoadTemplates(cfg.Mail.Templates)
+
+		if err != nil {
+			return err
+		}
+
+		transport, sink, err := newMailer(cfg.Mail)
+
+		if err != nil {
+			return err
+		}
+
+		mailSink = sink
+		mailDispatcher = mail.NewDispatcher(storage, mailDirectory{db: storage, cfg: cfg}, templates, transport, cfg.Mail)
+		bus.SubscribeQueue(mailDispatcher)
+		mailDispatcher.Start()
+
+		slog.Info("Mail enabled", slog.String("transport", cfg.Mail.Transport))
+	}
+
+	relay := outbox.NewRelay(storage, cfg.Outbox, sinks)
+	storage.SetOutboxNotifier(relay.Wake)
+	relay.Start()
+
+	backups := backup.NewManager(storage, cfg.Backup)
+	backups.Start()
+
+	blobs, err := blob.NewStore(cfg.Uploads.Dir)
+
+	if err != nil {
+		return err
+	}
+
+	// Maintenance jobs run on the schedules in cfg.Scheduler until
+	// shutdown.
+
+	jobs, err := newScheduler(cfg, storage, backups, blobs)
+
+	if err != nil {
+		return err
+	}
+
+	jobs.Start()
+
+	// Feature flags are reloaded when their file changes.
+
+	featureFlags, err := flags.New(cfg.Flags)
+
+	if err != nil {
+		return err
+	}
+
+	featureFlags.Start()
+
+	// Setup routers, one per tenant
+
+	registry, err := newTenants(cfg, storage, blobs, tracer, resolver.Ids())
+
+	if err != nil {
+		return err
+	}
+
+	bus.Subscribe(registry)
+
+	var handler http.Handler = registry
+
+	handler = middleware.Version(cfg.Versioning)(handler)
+	handler = middleware.Tenant(resolver, limiter)(handler)
+	handler = adminRoutes(jobs, mailSink, featureFlags, resolver, handler)
+	handler = middleware.Flags(featureFlags, cfg.Flags)(handler)
+
+	handler = middleware.APIKey(storage, keyedPrefixes(cfg.Auth)...)(handler)
+
+	// CORS goes outside authentication so preflights, which carry no
+	// credentials, are answered.
+	handler = middleware.CORS(cfg.CORS)(handler)
+	handler = middleware.Compress(cfg.Compression)(handler)
+	handler = middleware.SecurityHeaders(cfg.Security)(handler)
+	handler = middleware.Trace(tracer)(handler)
+
+	server := http.Server{
+		Addr:    cfg.Addr,
+		Handler: handler,
+	}
+
+	// Event streams never go idle and hijacked WebSocket connections are
+	// not tracked by the server, so end both when shutdown begins.
+	server.RegisterOnShutdown(registry.Close)
+
+	slog.Info("Starting Students API server", slog.String("address", cfg.Addr), slog.String("env", cfg.Env), slog.String("storage_path", cfg.StoragePath))
+
+	done := make(chan os.Signal, 1)
+
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+
+	// The gRPC server checks API keys and resolves tenants like the HTTP
+	// server, shares each tenant's storage and event broadcaster with it
+	// and is stopped in the same shutdown sequence.
+
+	var grpcKeys middleware.KeyStore
+
+	if cfg.Auth.RequireAPIKey {
+		grpcKeys = storage
+	}
+
+	grpcServer := rpc.NewServer(registry.backend, resolver, limiter, grpcKeys, tracer)
+
+	listener, err := net.Listen("tcp", cfg.GRPCServer.Addr)
+
+	if err != nil {
+		return fmt.Errorf("error listening for gRPC: %w", err)
+	}
+
+	slog.Info("Starting Students gRPC server", slog.String("address", cfg.GRPCServer.Addr))
+
+	// A server that fails to start or stops serving takes the other one
+	// down through the same graceful shutdown as a signal.
+
+	failed := make(chan error, 2)
+
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			failed <- fmt.Errorf("gRPC server: %w", err)
+		}
+	}()
+
+	go func() {
+		err := server.ListenAndServe()
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			failed <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+
+	var serveErr error
+
+	select {
+	case <-done:
+	case serveErr = <-failed:
+		slog.Error("Server failed", slog.String("error", serveErr.Error()))
+	}
+
+	slog.Info("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	defer cancel()
+
+	err = server.Shutdown(ctx)
+
+	if err != nil {
+		slog.Error("Error shutting down server", slog.String("error", err.Error()))
+	}
+
+	stopped := make(chan struct{})
+
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	jobs.Stop()
+	featureFlags.Stop()
+	relay.Stop()
+	dispatcher.Stop()
+
+	if mailDispatcher != nil {
+		mailDispatcher.Stop()
+	}
+
+	if mailSink != nil {
+		mailSink.Close()
+	}
+
+	backups.Stop()
+	tracer.Stop()
+
+	if serveErr != nil {
+		return serveErr
+	}
+
+	slog.Info("Server gracefully stopped")
+
+	return nil
+}
+
+// keyedPrefixes lists the paths that need an API key. Admin routes act on
+// every tenant, so they need one even when auth.require_api_key leaves the
+// rest of the API open.
+func keyedPrefixes(auth config.Auth) []string {
+	prefixes := []string{"/api/admin/"}
+	if auth.RequireAPIKey {
+		prefixes = append(prefixes, "/api/", "/v1/", "/v2/", "/graphql")
+	}
+	return prefixes
+}
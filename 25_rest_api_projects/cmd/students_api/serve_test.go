@@ -0,0 +1,21 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+func TestAdminRoutesAlwaysNeedAKey(t *testing.T) {
+	for _, required := range []bool{false, true} {
+		prefixes := keyedPrefixes(config.Auth{RequireAPIKey: required})
+
+		if !slices.Contains(prefixes, "/api/admin/") {
+			t.Errorf("require_api_key %v: prefixes = %v, want /api/admin/ keyed", required, prefixes)
+		}
+		if slices.Contains(prefixes, "/graphql") != required {
+			t.Errorf("require_api_key %v: prefixes = %v", required, prefixes)
+		}
+	}
+}
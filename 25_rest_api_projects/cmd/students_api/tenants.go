@@ -0,0 +1,232 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/blob"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/gql"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/batch"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/course"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/enrollment"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/files"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/roster"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/stream"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/student"
+	webhookhandler "github.com/faysal0x1/Go-Learn/internal/http/handlers/webhook"
+	"github.com/faysal0x1/Go-Learn/internal/http/middleware"
+	"github.com/faysal0x1/Go-Learn/internal/http/versioning"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/storage/cached"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/storage/traced"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// tenantServices is everything a request needs once its tenant is known:
+// storage confined to the tenant, and the event stream and roster hub that
+// only see the tenant's events.
+type tenantServices struct {
+	store       storage.Storage
+	broadcaster *events.Broadcaster
+	hub         *roster.Hub
+	router      http.Handler
+}
+
+// tenants serves each request from the services of the tenant the tenant
+// middleware put in its context. Services are built for every configured
+// tenant at startup, so event streams replay from the start.
+type tenants struct {
+	byId map[string]*tenantServices
+}
+
+func newTenants(cfg *config.Config, db *sqlite.Sqlite, blobs *blob.Store, tracer *trace.Tracer, ids []string) (*tenants, error) {
+	t := &tenants{byId: map[string]*tenantServices{}}
+
+	for _, id := range ids {
+		scoped := db.ForTenant(id)
+
+		services := &tenantServices{
+			store:       traced.Wrap(cached.Wrap(scoped, cfg.Cache), tracer),
+			broadcaster: events.NewBroadcaster(cfg.Events.ReplayBuffer, cfg.Events.ClientBuffer),
+			hub:         roster.NewHub(),
+		}
+
+		router, err := newRouter(cfg, services, scoped, blobs)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", id, err)
+		}
+
+		services.router = router
+		t.byId[id] = services
+	}
+
+	return t, nil
+}
+
+func (t *tenants) lookup(ctx context.Context) (*tenantServices, error) {
+	id := tenant.FromContext(ctx).Id
+
+	services, ok := t.byId[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", tenant.ErrUnknown, id)
+	}
+
+	return services, nil
+}
+
+func (t *tenants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	services, err := t.lookup(r.Context())
+	if err != nil {
+		response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+		return
+	}
+
+	services.router.ServeHTTP(w, r)
+	middleware.Route(r)
+}
+
+// backend implements rpc.Backend.
+func (t *tenants) backend(ctx context.Context) (storage.Storage, *events.Broadcaster, error) {
+	services, err := t.lookup(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return storage.WithContext(ctx, services.store), services.broadcaster, nil
+}
+
+// Publish implements events.Publisher, routing each event to the stream
+// and hub of the tenant it belongs to.
+func (t *tenants) Publish(event events.Event) {
+	services, ok := t.byId[cmp.Or(event.Tenant, tenant.Default)]
+	if !ok {
+		return
+	}
+
+	services.broadcaster.Publish(event)
+	services.hub.Publish(event)
+}
+
+// Close ends the event streams and WebSocket connections of all tenants.
+func (t *tenants) Close() {
+	for _, services := range t.byId {
+		services.broadcaster.Close()
+		services.hub.Close()
+	}
+}
+
+func newRouter(cfg *config.Config, services *tenantServices, db *sqlite.Sqlite, blobs *blob.Store) (http.Handler, error) {
+	store := services.store
+
+	schema, err := gql.NewSchema(store)
+
+	if err != nil {
+		return nil, err
+	}
+
+	router := http.NewServeMux()
+
+	// Every route gets the body limit configured for its pattern, or
+	// limit when none is. Routes under /api are served as every API
+	// version; transformers reshape the response for older ones.
+	handleVersioned := func(pattern string, limit int64, handler http.HandlerFunc, transformers versioning.Transformers) {
+		if routeLimit, ok := cfg.BodyLimits.Routes[pattern]; ok {
+			limit = routeLimit
+		}
+
+		versioning.Handle(router, pattern, middleware.MaxBody(limit)(handler), transformers)
+	}
+
+	handleLimited := func(pattern string, limit int64, handler http.HandlerFunc) {
+		handleVersioned(pattern, limit, handler, nil)
+	}
+
+	handle := func(pattern string, handler http.HandlerFunc) {
+		handleLimited(pattern, cfg.BodyLimits.MaxSize, handler)
+	}
+
+	// Version 2 groups a student's status fields; v1 keeps them flat.
+	handleStudent := func(pattern string, handler http.HandlerFunc) {
+		handleVersioned(pattern, cfg.BodyLimits.MaxSize, handler, versioning.Transformers{versioning.V1: student.V1})
+	}
+
+	handle("GET /", func(w http.ResponseWriter, r *http.Request) {
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Welcome to the Students API!"))
+	})
+
+	handle("POST /api/students", bind(store, student.New))
+	handleStudent("GET /api/students", bind(store, student.GetList))
+	handle("GET /api/students/events", stream.Students(services.broadcaster, cfg.Events.Heartbeat))
+	handleStudent("GET /api/students/{id}", bind(store, student.GetById))
+	handleStudent("PUT /api/students/{id}", bind(store, student.Update))
+	handle("DELETE /api/students/{id}", bind(store, student.Delete))
+	handle("GET /api/students/{id}/enrollments", bind(store, student.Enrollments))
+	handle("GET /api/students/{id}/gpa", bind(store, student.GPA))
+	handle("POST /api/students/{id}/transitions", bind(store, student.Transition))
+	handle("GET /api/students/{id}/transitions", bind(store, student.Transitions))
+	handle("GET /api/students/{id}/transcript.pdf", bind(store, func(s storage.Storage) http.HandlerFunc {
+		return student.Transcript(s, cfg.Reports)
+	}))
+
+	handleLimited("POST /api/batch", cfg.Batch.MaxSize, bind(store, func(s storage.Storage) http.HandlerFunc {
+		return batch.Run(s, cfg.Batch)
+	}))
+
+	// Uploads may be as large as the upload limit plus room for the
+	// multipart framing and form fields.
+	handleLimited("POST /api/students/{id}/files", cfg.Uploads.MaxSize+64<<10, files.Upload(db, blobs, cfg.Uploads))
+	handle("GET /api/students/{id}/files", files.GetList(db))
+	handle("GET /api/students/{id}/files/{file_id}", files.Download(db, blobs))
+	handle("GET /api/students/{id}/files/{file_id}/thumbnail", files.Thumbnail(db, blobs))
+	handle("DELETE /api/students/{id}/files/{file_id}", files.Delete(db, blobs))
+
+	handle("POST /api/courses", bind(store, course.New))
+	handle("GET /api/courses", bind(store, course.GetList))
+	handle("GET /api/courses/{id}", bind(store, course.GetById))
+	handle("GET /api/courses/{id}/stats", bind(store, course.Stats))
+	handle("GET /api/courses/{id}/roster.pdf", bind(store, func(s storage.Storage) http.HandlerFunc {
+		return course.Roster(s, cfg.Reports)
+	}))
+	handle("POST /api/courses/{id}/enrollments", bind(store, enrollment.Enroll))
+	handle("DELETE /api/courses/{id}/enrollments/{student_id}", bind(store, enrollment.Unenroll))
+	handle("PUT /api/courses/{id}/enrollments/{student_id}/grade", bind(store, enrollment.RecordGrade))
+
+	handle("GET /api/rosters/ws", func(w http.ResponseWriter, r *http.Request) {
+		roster.Serve(services.hub, storage.WithContext(r.Context(), store))(w, r)
+	})
+
+	graphqlHandler := gql.Handler(schema, store, gql.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	})
+
+	handle("GET /graphql", graphqlHandler)
+	handle("POST /graphql", graphqlHandler)
+
+	handle("POST /api/webhooks", webhookhandler.New(db))
+	handle("GET /api/webhooks", webhookhandler.GetList(db))
+	handle("GET /api/webhooks/{id}", webhookhandler.GetById(db))
+	handle("DELETE /api/webhooks/{id}", webhookhandler.Delete(db))
+	handle("POST /api/webhooks/{id}/enable", webhookhandler.SetEnabled(db, true))
+	handle("POST /api/webhooks/{id}/disable", webhookhandler.SetEnabled(db, false))
+	handle("GET /api/webhooks/{id}/deliveries", webhookhandler.Deliveries(db))
+
+	return router, nil
+}
+
+// bind builds the handler for each request over store bound to the
+// request context, so storage spans nest under the request's span.
+func bind(store storage.Storage, handler func(storage.Storage) http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler(storage.WithContext(r.Context(), store))(w, r)
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/blob"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/http/middleware"
+	"github.com/faysal0x1/Go-Learn/internal/rpc"
+	"github.com/faysal0x1/Go-Learn/internal/scheduler"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	studentsv1 "github.com/faysal0x1/Go-Learn/pkg/pb/students/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const schoolDomain = "schools.test"
+
+// isolation is a server hosting the north and south schools, told apart by
+// subdomain, with one student seeded in each. API keys are required and
+// each school has one.
+type isolation struct {
+	db      *sqlite.Sqlite
+	handler http.Handler
+	grpc    *grpc.Server
+
+	ours, theirs       int64
+	theirFile          int64
+	northKey, southKey string
+}
+
+func newIsolation(t *testing.T) *isolation {
+	t.Helper()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte(fmt.Sprintf(`
+env: test
+storage_path: %q
+http_server:
+  address: "localhost:0"
+uploads:
+  dir: %q
+`, filepath.Join(dir, "test.db"), filepath.Join(dir, "blobs"))), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Auth.RequireAPIKey = true
+	cfg.Tenancy = config.Tenancy{
+		Enabled: true,
+		Sources: []string{"subdomain"},
+		Domain:  schoolDomain,
+		Tenants: map[string]config.Tenant{"north": {}, "south": {}},
+	}
+
+	db, err := sqlite.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Db.Close() })
+
+	blobs, err := blob.NewStore(cfg.Uploads.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := tenant.NewResolver(cfg.Tenancy)
+
+	registry, err := newTenants(cfg, db, blobs, nil, resolver.Ids())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(registry.Close)
+
+	handler := middleware.Version(cfg.Versioning)(registry)
+	handler = middleware.Tenant(resolver, tenant.NewLimiter())(handler)
+	handler = middleware.APIKey(db, keyedPrefixes(cfg.Auth)...)(handler)
+
+	s := &isolation{
+		db:      db,
+		handler: handler,
+		grpc:    rpc.NewServer(registry.backend, resolver, tenant.NewLimiter(), db, nil),
+	}
+
+	for school, key := range map[string]*string{"north": &s.northKey, "south": &s.southKey} {
+		var prefix, hash string
+		*key, prefix, hash = apikey.Generate()
+		if _, err := db.ForTenant(school).CreateAPIKey(school, prefix, hash); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s.ours, err = db.ForTenant("north").CreateStudent("Ada", "ada@north.edu", 20); err != nil {
+		t.Fatal(err)
+	}
+	if s.theirs, err = db.ForTenant("south").CreateStudent("Grace", "grace@south.edu", 21); err != nil {
+		t.Fatal(err)
+	}
+
+	s.theirFile, err = db.ForTenant("south").CreateStudentFile(types.StudentFile{
+		StudentId: s.theirs, Kind: "document", Filename: "id.pdf", ContentType: "application/pdf", Size: 1, SHA256: "abc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// do sends a request to the north school with north's key.
+func (s *isolation) do(method, path, body string) *httptest.ResponseRecorder {
+	return s.doWithKey(s.northKey, method, path, body)
+}
+
+// doWithKey sends a request to the north school with the given key.
+func (s *isolation) doWithKey(key, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = "north." + schoolDomain
+	req.Header.Set("X-API-Key", key)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// southIsUntouched checks south's student still looks as seeded.
+func (s *isolation) southIsUntouched(t *testing.T) {
+	t.Helper()
+
+	south := s.db.ForTenant("south")
+
+	student, err := south.GetStudentById(s.theirs)
+	if err != nil || student.Name != "Grace" || student.Status != "applicant" {
+		t.Errorf("south's student = %+v, %v, want it unchanged", student, err)
+	}
+
+	if files, _ := south.GetStudentFiles(s.theirs); len(files) != 1 {
+		t.Errorf("south's files = %+v, want the file kept", files)
+	}
+}
+
+func TestHTTPRequestsStayInTheirTenant(t *testing.T) {
+	s := newIsolation(t)
+
+	theirs := fmt.Sprintf("/api/students/%d", s.theirs)
+
+	tests := []struct {
+		method, path, body string
+	}{
+		{http.MethodGet, theirs, ""},
+		{http.MethodPut, theirs, `{"name":"Mallory","email":"mallory@north.edu","age":30}`},
+		{http.MethodDelete, theirs, ""},
+		{http.MethodPost, theirs + "/transitions", `{"event":"enroll"}`},
+		{http.MethodGet, theirs + "/enrollments", ""},
+		{http.MethodGet, fmt.Sprintf("%s/files/%d", theirs, s.theirFile), ""},
+		{http.MethodDelete, fmt.Sprintf("%s/files/%d", theirs, s.theirFile), ""},
+	}
+
+	for _, tt := range tests {
+		if rec := s.do(tt.method, tt.path, tt.body); rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s = %d %s, want 404", tt.method, tt.path, rec.Code, rec.Body)
+		}
+	}
+
+	lists := []string{"/api/students", theirs + "/transitions", theirs + "/files"}
+
+	for _, path := range lists {
+		rec := s.do(http.MethodGet, path, "")
+		if strings.Contains(rec.Body.String(), "grace@south.edu") || strings.Contains(rec.Body.String(), "id.pdf") {
+			t.Errorf("GET %s leaked south's rows: %s", path, rec.Body)
+		}
+	}
+
+	if rec := s.do(http.MethodGet, "/api/students", ""); !strings.Contains(rec.Body.String(), "ada@north.edu") {
+		t.Errorf("GET /api/students = %s, want north's own student", rec.Body)
+	}
+
+	s.southIsUntouched(t)
+}
+
+func TestGraphQLStaysInItsTenant(t *testing.T) {
+	s := newIsolation(t)
+
+	graphql := func(query string) map[string]json.RawMessage {
+		t.Helper()
+
+		body, _ := json.Marshal(map[string]string{"query": query})
+		rec := s.do(http.MethodPost, "/graphql", string(body))
+
+		var res struct {
+			Data map[string]json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+		return res.Data
+	}
+
+	if data := graphql(fmt.Sprintf(`{ student(id: "%d") { name } }`, s.theirs)); string(data["student"]) != "null" {
+		t.Errorf("student across tenants = %s, want null", data["student"])
+	}
+
+	if data := graphql(`{ students { edges { node { email } } } }`); strings.Contains(string(data["students"]), "south") {
+		t.Errorf("students leaked south's rows: %s", data["students"])
+	}
+
+	graphql(fmt.Sprintf(`mutation { updateStudent(id: "%d", input: {name: "Mallory", email: "m@north.edu", age: 30}) { id } }`, s.theirs))
+	graphql(fmt.Sprintf(`mutation { transitionStudent(id: "%d", event: "enroll") { id } }`, s.theirs))
+	graphql(fmt.Sprintf(`mutation { deleteStudent(id: "%d") }`, s.theirs))
+
+	s.southIsUntouched(t)
+}
+
+// dialNorth serves the gRPC API and returns a client addressing north.
+func (s *isolation) dialNorth(t *testing.T) studentsv1.StudentServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	go s.grpc.Serve(listener)
+	t.Cleanup(s.grpc.Stop)
+
+	// The client addresses north's subdomain, which becomes the
+	// :authority the resolver reads.
+	conn, err := grpc.NewClient("passthrough:///north."+schoolDomain,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return studentsv1.NewStudentServiceClient(conn)
+}
+
+func TestGRPCStaysInItsTenant(t *testing.T) {
+	s := newIsolation(t)
+
+	client := s.dialNorth(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", s.northKey)
+
+	_, err := client.GetStudent(ctx, &studentsv1.GetStudentRequest{Id: s.theirs})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetStudent across tenants = %v, want NotFound", err)
+	}
+
+	_, err = client.UpdateStudent(ctx, &studentsv1.UpdateStudentRequest{Id: s.theirs, Name: "Mallory", Email: "m@north.edu", Age: 30})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("UpdateStudent across tenants = %v, want NotFound", err)
+	}
+
+	_, err = client.DeleteStudent(ctx, &studentsv1.DeleteStudentRequest{Id: s.theirs})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("DeleteStudent across tenants = %v, want NotFound", err)
+	}
+
+	list, err := client.ListStudents(ctx, &studentsv1.ListStudentsRequest{})
+	if err != nil || len(list.GetStudents()) != 1 || list.GetStudents()[0].GetId() != s.ours {
+		t.Errorf("ListStudents = %v, %v, want north's student only", list, err)
+	}
+
+	s.southIsUntouched(t)
+}
+
+// The subdomain is chosen by the client, so south's key must not open
+// north's data just by addressing north's host.
+func TestAPIKeysAreBoundToTheirTenant(t *testing.T) {
+	s := newIsolation(t)
+
+	for _, path := range []string{"/api/students", fmt.Sprintf("/api/students/%d", s.ours), "/v2/students"} {
+		if rec := s.doWithKey(s.southKey, http.MethodGet, path, ""); rec.Code != http.StatusForbidden {
+			t.Errorf("GET %s with south's key = %d, want 403", path, rec.Code)
+		}
+	}
+
+	if rec := s.doWithKey(s.northKey, http.MethodGet, "/api/students", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /api/students with north's key = %d, want 200", rec.Code)
+	}
+
+	client := s.dialNorth(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	south := metadata.AppendToOutgoingContext(ctx, "x-api-key", s.southKey)
+	if _, err := client.ListStudents(south, &studentsv1.ListStudentsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListStudents with south's key = %v, want PermissionDenied", err)
+	}
+
+	stream, err := client.WatchStudents(south, &studentsv1.WatchStudentsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("WatchStudents with south's key = %v, want PermissionDenied", err)
+	}
+
+	north := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", s.northKey)
+	if _, err := client.ListStudents(north, &studentsv1.ListStudentsRequest{}); err != nil {
+		t.Errorf("ListStudents with north's key: %v", err)
+	}
+}
+
+func TestAdminRoutesNeedAnOperatorKey(t *testing.T) {
+	s := newIsolation(t)
+
+	key, prefix, hash := apikey.Generate()
+	if _, err := s.db.CreateAPIKey("operator", prefix, hash); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := middleware.APIKey(s.db, "/api/admin/")(adminRoutes(scheduler.New(time.UTC), nil, nil, nil, http.NotFoundHandler()))
+
+	for _, tt := range []struct {
+		key  string
+		want int
+	}{
+		{s.northKey, http.StatusForbidden},
+		{key, http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/jobs", nil)
+		req.Header.Set("X-API-Key", tt.key)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("GET /api/admin/jobs = %d, want %d", rec.Code, tt.want)
+		}
+	}
+}
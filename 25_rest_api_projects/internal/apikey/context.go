@@ -0,0 +1,35 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// ErrWrongTenant rejects a request made with a key issued for another
+// tenant than the one the request resolved to.
+var ErrWrongTenant = errors.New("api key belongs to another tenant")
+
+type contextKey struct{}
+
+// WithKey records the key a request was authenticated with.
+func WithKey(ctx context.Context, key types.APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the key recorded by WithKey, if any.
+func FromContext(ctx context.Context) (types.APIKey, bool) {
+	key, ok := ctx.Value(contextKey{}).(types.APIKey)
+	return key, ok
+}
+
+// CheckTenant returns ErrWrongTenant when ctx carries a key of a tenant
+// other than tenantId. Requests without a key are left to the routes that
+// do not need one.
+func CheckTenant(ctx context.Context, tenantId string) error {
+	if key, ok := FromContext(ctx); ok && key.TenantId != tenantId {
+		return ErrWrongTenant
+	}
+	return nil
+}
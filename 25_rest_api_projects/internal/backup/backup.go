@@ -0,0 +1,406 @@
+// Package backup takes consistent snapshots of the SQLite store while it
+// serves traffic, keeps them gzip-compressed next to a SHA-256 checksum,
+// prunes old ones and restores them.
+//
+// A backup named students-20261019T085200.000Z.db.gz is accompanied by
+// students-20261019T085200.000Z.db.gz.sha256 in sha256sum format, so it can
+// also be checked with `sha256sum -c`.
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	prefix     = "students-"
+	suffix     = ".db.gz"
+	timeFormat = "20060102T150405.000Z"
+)
+
+var ErrChecksumMismatch = errors.New("backup checksum mismatch")
+
+// Snapshotter writes a transactionally consistent copy of the live
+// database to path, giving up when ctx is done.
+type Snapshotter interface {
+	Snapshot(ctx context.Context, path string) error
+}
+
+type Backup struct {
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256"`
+}
+
+type Manager struct {
+	source Snapshotter
+	cfg    config.Backup
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewManager(source Snapshotter, cfg config.Backup) *Manager {
+	return &Manager{source: source, cfg: cfg}
+}
+
+// Start takes a backup every cfg.Interval until Stop is called. It does
+// nothing when the interval is zero.
+func (m *Manager) Start() {
+	if m.cfg.Interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Stop waits for a backup in progress rather than
+				// cancelling it.
+				b, err := m.Rotate(context.WithoutCancel(ctx))
+				if err != nil {
+					slog.Error("backup failed", slog.String("error", err.Error()))
+					continue
+				}
+				slog.Info("backup created", slog.String("path", b.Path), slog.Int64("size", b.Size))
+			}
+		}
+	}()
+}
+
+// Stop ends the backup loop, waiting for a backup in progress to finish.
+func (m *Manager) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+}
+
+// Rotate creates a backup and then applies the retention policy.
+func (m *Manager) Rotate(ctx context.Context) (Backup, error) {
+	b, err := m.Create(ctx)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	if _, err := m.Prune(); err != nil {
+		return b, fmt.Errorf("prune: %w", err)
+	}
+
+	return b, nil
+}
+
+// Create snapshots the database and compresses the snapshot into the
+// backup directory. A cancelled ctx stops the snapshot and leaves no
+// backup behind.
+func (m *Manager) Create(ctx context.Context) (Backup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.cfg.Dir, 0o755); err != nil {
+		return Backup{}, err
+	}
+
+	now := time.Now().UTC()
+	name := prefix + now.Format(timeFormat) + suffix
+
+	// VACUUM INTO refuses to overwrite, so the snapshot goes to a fresh
+	// temporary name.
+	snapshot := filepath.Join(m.cfg.Dir, "."+name+".snapshot")
+	defer os.Remove(snapshot)
+
+	if err := m.source.Snapshot(ctx, snapshot); err != nil {
+		return Backup{}, fmt.Errorf("snapshot: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Backup{}, err
+	}
+
+	b, err := compress(snapshot, filepath.Join(m.cfg.Dir, name))
+	if err != nil {
+		return Backup{}, err
+	}
+	b.CreatedAt = now
+
+	return b, nil
+}
+
+// compress gzips src into dst, hashing the compressed bytes as they are
+// written, then records the checksum. dst only appears once complete.
+func compress(src, dst string) (Backup, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return Backup{}, err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+
+	out, err := os.Create(tmp)
+	if err != nil {
+		return Backup{}, err
+	}
+	defer os.Remove(tmp)
+
+	hasher := sha256.New()
+	counter := &countingWriter{}
+
+	gzipWriter := gzip.NewWriter(io.MultiWriter(out, hasher, counter))
+
+	if _, err := io.Copy(gzipWriter, in); err != nil {
+		out.Close()
+		return Backup{}, err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		out.Close()
+		return Backup{}, err
+	}
+
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return Backup{}, err
+	}
+
+	if err := out.Close(); err != nil {
+		return Backup{}, err
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+
+	if err := os.WriteFile(dst+".sha256", []byte(sum+"  "+filepath.Base(dst)+"\n"), 0o644); err != nil {
+		return Backup{}, err
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		return Backup{}, err
+	}
+
+	return Backup{Path: dst, Size: counter.n, SHA256: sum}, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// List returns the backups in the backup directory, newest first.
+func (m *Manager) List() ([]Backup, error) {
+	matches, err := filepath.Glob(filepath.Join(m.cfg.Dir, prefix+"*"+suffix))
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []Backup{}
+
+	for _, path := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), suffix)
+
+		createdAt, err := time.Parse(timeFormat, stamp)
+		if err != nil {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		sum, _ := readChecksum(path)
+
+		backups = append(backups, Backup{Path: path, CreatedAt: createdAt, Size: info.Size(), SHA256: sum})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// Prune deletes backups beyond the newest KeepCount and those older than
+// MaxAge. The newest backup is always kept.
+func (m *Manager) Prune() ([]Backup, error) {
+	backups, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []Backup
+
+	for i, b := range backups {
+		if i == 0 {
+			continue
+		}
+
+		tooMany := m.cfg.KeepCount > 0 && i >= m.cfg.KeepCount
+		tooOld := m.cfg.MaxAge > 0 && time.Since(b.CreatedAt) > m.cfg.MaxAge
+
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		if err := os.Remove(b.Path); err != nil {
+			return removed, err
+		}
+		os.Remove(b.Path + ".sha256")
+
+		removed = append(removed, b)
+	}
+
+	return removed, nil
+}
+
+// Verify checks path against its recorded checksum and that it
+// decompresses cleanly.
+func Verify(path string) error {
+	want, err := readChecksum(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+
+	if _, err := io.Copy(hasher, file); err != nil {
+		return err
+	}
+
+	if got := hex.EncodeToString(hasher.Sum(nil)); got != want {
+		return fmt.Errorf("%w: %s: got %s, want %s", ErrChecksumMismatch, filepath.Base(path), got, want)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	_, err = io.Copy(io.Discard, gzipReader)
+	return err
+}
+
+func readChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path + ".sha256")
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s.sha256: empty checksum file", filepath.Base(path))
+	}
+
+	return fields[0], nil
+}
+
+// Restore verifies the backup at path and replaces the database at
+// storagePath with it. The server must not be running.
+func Restore(path, storagePath string) error {
+	if err := Verify(path); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	tmp := storagePath + ".restore"
+
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	if _, err := io.Copy(out, gzipReader); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if err := integrityCheck(tmp); err != nil {
+		return err
+	}
+
+	// Leftover journal files belong to the database being replaced.
+	for _, ext := range []string{"-wal", "-shm", "-journal"} {
+		os.Remove(storagePath + ext)
+	}
+
+	return os.Rename(tmp, storagePath)
+}
+
+func integrityCheck(path string) error {
+	db, err := sql.Open("sqlite3", path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+
+	if result != "ok" {
+		return fmt.Errorf("integrity check: %s", result)
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+)
+
+func newStore(t *testing.T, path string) *sqlite.Sqlite {
+	t.Helper()
+
+	s, err := sqlite.New(&config.Config{StoragePath: path})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { s.Db.Close() })
+
+	return s
+}
+
+func TestCreateVerifyAndRestore(t *testing.T) {
+	dir := t.TempDir()
+
+	store := newStore(t, filepath.Join(dir, "live.db"))
+	if _, err := store.CreateStudent("Ada", "ada@example.edu", 20); err != nil {
+		t.Fatalf("CreateStudent: %v", err)
+	}
+
+	m := NewManager(store, config.Backup{Dir: filepath.Join(dir, "backups")})
+
+	b, err := m.Create(context.Background())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := Verify(b.Path); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	listed, err := m.List()
+	if err != nil || len(listed) != 1 || listed[0].SHA256 != b.SHA256 {
+		t.Fatalf("List = %+v, %v, want the new backup", listed, err)
+	}
+
+	restored := filepath.Join(dir, "restored.db")
+	if err := Restore(b.Path, restored); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	students, err := newStore(t, restored).GetStudents()
+	if err != nil || len(students) != 1 || students[0].Email != "ada@example.edu" {
+		t.Errorf("restored students = %+v, %v", students, err)
+	}
+}
+
+func TestCreateGivesUpWhenCancelled(t *testing.T) {
+	dir := t.TempDir()
+
+	m := NewManager(newStore(t, filepath.Join(dir, "live.db")), config.Backup{Dir: filepath.Join(dir, "backups")})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := m.Create(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Create with a cancelled context = %v, want context.Canceled", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "backups"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("backup dir = %v, %v, want it empty", entries, err)
+	}
+}
+
+func TestVerifyDetectsCorruption(t *testing.T) {
+	dir := t.TempDir()
+
+	m := NewManager(newStore(t, filepath.Join(dir, "live.db")), config.Backup{Dir: dir})
+
+	b, err := m.Create(context.Background())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)/2] ^= 0xff
+	if err := os.WriteFile(b.Path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Verify(b.Path); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Verify = %v, want ErrChecksumMismatch", err)
+	}
+
+	target := filepath.Join(dir, "target.db")
+	if err := Restore(b.Path, target); err == nil {
+		t.Error("Restore accepted a corrupted backup")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("a failed restore left %s behind", target)
+	}
+}
+
+// fakeBackup writes an empty backup file stamped at createdAt.
+func fakeBackup(t *testing.T, dir string, createdAt time.Time) string {
+	t.Helper()
+
+	path := filepath.Join(dir, prefix+createdAt.UTC().Format(timeFormat)+suffix)
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".sha256", []byte("0  x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestPruneAppliesRetention(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		cfg  config.Backup
+		ages []time.Duration
+		kept int
+	}{
+		{"keep count", config.Backup{KeepCount: 2}, []time.Duration{0, time.Hour, 2 * time.Hour, 3 * time.Hour}, 2},
+		{"max age", config.Backup{MaxAge: 90 * time.Minute}, []time.Duration{0, time.Hour, 2 * time.Hour, 3 * time.Hour}, 2},
+		{"newest is kept", config.Backup{MaxAge: time.Minute}, []time.Duration{time.Hour, 2 * time.Hour}, 1},
+		{"unlimited", config.Backup{}, []time.Duration{0, time.Hour, 2 * time.Hour}, 3},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		tt.cfg.Dir = dir
+
+		for _, age := range tt.ages {
+			fakeBackup(t, dir, now.Add(-age))
+		}
+
+		m := NewManager(nil, tt.cfg)
+
+		removed, err := m.Prune()
+		if err != nil {
+			t.Fatalf("%s: Prune: %v", tt.name, err)
+		}
+
+		left, _ := m.List()
+		if len(left) != tt.kept || len(removed) != len(tt.ages)-tt.kept {
+			t.Errorf("%s: kept %d and removed %d, want %d kept", tt.name, len(left), len(removed), tt.kept)
+		}
+
+		for _, b := range removed {
+			if _, err := os.Stat(b.Path + ".sha256"); !os.IsNotExist(err) {
+				t.Errorf("%s: checksum of %s was not removed", tt.name, b.Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package blob keeps uploaded files in a local directory, addressed by the
+// SHA-256 of their content like `sha256sum` would print it. Identical
+// uploads share one file; blobs are written to a temporary file first and
+// renamed into place, so a reader never sees a partial blob.
+//
+// A blob with hash 9f86d0... lives at <dir>/9f/86/9f86d0....
+package blob
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+)
+
+var (
+	ErrTooLarge    = errors.New("file is too large")
+	ErrEmpty       = errors.New("file is empty")
+	ErrTypeDenied  = errors.New("file type is not allowed")
+	ErrNotFound    = errors.New("blob not found")
+	ErrInvalidHash = errors.New("invalid blob hash")
+)
+
+// Grace is how long a blob is kept after it was last written before it may
+// be deleted as unreferenced, so an upload that is still being recorded,
+// or that just reused the blob, does not lose it.
+const Grace = time.Hour
+
+var validHash = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type Store struct {
+	dir string
+}
+
+// Blob describes stored content. ContentType is what the content sniffed
+// as, never what the client claimed.
+type Blob struct {
+	Hash        string
+	Size        int64
+	ContentType string
+}
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0o755); err != nil {
+		return nil, fmt.Errorf("blob: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Put streams r into the store. The type is sniffed from the first 512
+// bytes and checked against allowed before anything is written, and reading
+// stops with ErrTooLarge as soon as more than maxSize bytes arrive.
+func (s *Store) Put(r io.Reader, maxSize int64, allowed []string) (Blob, error) {
+	buffered := bufio.NewReaderSize(io.LimitReader(r, maxSize+1), 512)
+
+	head, err := buffered.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Blob{}, err
+	}
+
+	if len(head) == 0 {
+		return Blob{}, ErrEmpty
+	}
+
+	contentType := mediaType(http.DetectContentType(head))
+	if !slices.Contains(allowed, contentType) {
+		return Blob{}, fmt.Errorf("%w: %s", ErrTypeDenied, contentType)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), "upload-*")
+	if err != nil {
+		return Blob{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(tmp, hash), buffered)
+	if err != nil {
+		return Blob{}, err
+	}
+
+	if size > maxSize {
+		return Blob{}, fmt.Errorf("%w: limit is %d bytes", ErrTooLarge, maxSize)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return Blob{}, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return Blob{}, err
+	}
+
+	blob := Blob{Hash: hex.EncodeToString(hash.Sum(nil)), Size: size, ContentType: contentType}
+
+	path := s.path(blob.Hash)
+
+	// Touching an existing blob restarts its grace period, so Delete and
+	// Sweep leave it alone until this upload has been recorded. If it
+	// vanished in between, it is written again below.
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err == nil {
+		return blob, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return Blob{}, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Blob{}, err
+	}
+
+	return blob, nil
+}
+
+// Open returns the blob for reading; *os.File supports the seeking that
+// ranged downloads need.
+func (s *Store) Open(hash string) (*os.File, error) {
+	if !validHash.MatchString(hash) {
+		return nil, ErrInvalidHash
+	}
+
+	file, err := os.Open(s.path(hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, hash)
+	}
+
+	return file, err
+}
+
+// Delete removes a blob last written before cutoff. Callers must make sure
+// nothing references it any more; a missing blob is not an error, and a
+// newer one is kept for Sweep to collect later.
+func (s *Store) Delete(hash string, cutoff time.Time) error {
+	if !validHash.MatchString(hash) {
+		return ErrInvalidHash
+	}
+
+	path := s.path(hash)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil || !info.ModTime().Before(cutoff) {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// Sweep deletes blobs last written before cutoff that inUse says nothing
+// references, and temporary files of uploads abandoned before cutoff. The
+// cutoff gives an upload time to be recorded before its blob counts as
+// unreferenced. It returns how many blobs were deleted.
+func (s *Store) Sweep(ctx context.Context, cutoff time.Time, inUse func(hash string) (bool, error)) (int, error) {
+	removed := 0
+
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().Before(cutoff) {
+			return nil
+		}
+
+		if filepath.Base(filepath.Dir(path)) == "tmp" {
+			os.Remove(path)
+			return nil
+		}
+
+		hash := entry.Name()
+		if !validHash.MatchString(hash) || path != s.path(hash) {
+			return nil
+		}
+
+		used, err := inUse(hash)
+		if err != nil || used {
+			return err
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+
+	return removed, err
+}
+
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash[2:4], hash)
+}
+
+func mediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return contentType
+}
@@ -0,0 +1,177 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const pdf = "%PDF-1.4\n1 0 obj << >> endobj\n%%EOF\n"
+
+var pdfOnly = []string{"application/pdf"}
+
+func newStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// age makes the blob look last written d ago.
+func age(t *testing.T, s *Store, hash string, d time.Duration) {
+	t.Helper()
+
+	then := time.Now().Add(-d)
+	if err := os.Chtimes(s.path(hash), then, then); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(s *Store, hash string) bool {
+	_, err := os.Stat(s.path(hash))
+	return err == nil
+}
+
+func TestPutStoresContentByHash(t *testing.T) {
+	s := newStore(t)
+
+	b, err := s.Put(strings.NewReader(pdf), 1<<10, pdfOnly)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if b.ContentType != "application/pdf" || b.Size != int64(len(pdf)) || !validHash.MatchString(b.Hash) {
+		t.Errorf("blob = %+v", b)
+	}
+
+	file, err := s.Open(b.Hash)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer file.Close()
+
+	if content, _ := io.ReadAll(file); string(content) != pdf {
+		t.Errorf("content = %q", content)
+	}
+}
+
+func TestPutRejectsBadUploads(t *testing.T) {
+	s := newStore(t)
+
+	tests := []struct {
+		name, content string
+		maxSize       int64
+		want          error
+	}{
+		{"empty", "", 1 << 10, ErrEmpty},
+		{"too large", pdf, 8, ErrTooLarge},
+		{"wrong type", "just some text", 1 << 10, ErrTypeDenied},
+	}
+
+	for _, tt := range tests {
+		if _, err := s.Put(strings.NewReader(tt.content), tt.maxSize, pdfOnly); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if entries, _ := os.ReadDir(filepath.Join(s.dir, "tmp")); len(entries) != 0 {
+		t.Errorf("rejected uploads left %d temporary files", len(entries))
+	}
+}
+
+func TestOpenMissingBlob(t *testing.T) {
+	s := newStore(t)
+
+	if _, err := s.Open(strings.Repeat("ab", 32)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing blob error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Open("../../etc/passwd"); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("path as hash error = %v, want ErrInvalidHash", err)
+	}
+}
+
+func TestDeleteKeepsRecentBlobs(t *testing.T) {
+	s := newStore(t)
+
+	b, err := s.Put(strings.NewReader(pdf), 1<<10, pdfOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Delete(b.Hash, time.Now().Add(-Grace)); err != nil || !exists(s, b.Hash) {
+		t.Fatalf("Delete of a fresh blob = %v, exists %v, want it kept", err, exists(s, b.Hash))
+	}
+
+	age(t, s, b.Hash, 2*Grace)
+
+	if err := s.Delete(b.Hash, time.Now().Add(-Grace)); err != nil || exists(s, b.Hash) {
+		t.Fatalf("Delete of an old blob = %v, exists %v, want it removed", err, exists(s, b.Hash))
+	}
+
+	if err := s.Delete(b.Hash, time.Now()); err != nil {
+		t.Errorf("Delete of a missing blob: %v", err)
+	}
+}
+
+// A second upload of the same content must protect the shared blob from a
+// release racing with it, before the upload is recorded.
+func TestDuplicatePutRestartsTheGracePeriod(t *testing.T) {
+	s := newStore(t)
+
+	first, err := s.Put(strings.NewReader(pdf), 1<<10, pdfOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	age(t, s, first.Hash, 2*Grace)
+
+	second, err := s.Put(strings.NewReader(pdf), 1<<10, pdfOnly)
+	if err != nil || second != first {
+		t.Fatalf("duplicate Put = %+v, %v, want %+v", second, err, first)
+	}
+
+	if err := s.Delete(first.Hash, time.Now().Add(-Grace)); err != nil || !exists(s, first.Hash) {
+		t.Errorf("Delete after a duplicate upload = %v, exists %v, want it kept", err, exists(s, first.Hash))
+	}
+
+	n, err := s.Sweep(context.Background(), time.Now().Add(-Grace), func(string) (bool, error) { return false, nil })
+	if err != nil || n != 0 || !exists(s, first.Hash) {
+		t.Errorf("Sweep after a duplicate upload = %d, %v, want the blob kept", n, err)
+	}
+}
+
+func TestSweepRemovesOnlyOldUnreferencedBlobs(t *testing.T) {
+	s := newStore(t)
+
+	var hashes []string
+	for _, content := range []string{pdf, pdf + "%used\n", pdf + "%fresh\n"} {
+		b, err := s.Put(strings.NewReader(content), 1<<10, pdfOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, b.Hash)
+	}
+	unused, used, fresh := hashes[0], hashes[1], hashes[2]
+
+	age(t, s, unused, 2*Grace)
+	age(t, s, used, 2*Grace)
+
+	n, err := s.Sweep(context.Background(), time.Now().Add(-Grace), func(hash string) (bool, error) {
+		return hash == used, nil
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("Sweep = %d, %v, want one blob removed", n, err)
+	}
+
+	if exists(s, unused) || !exists(s, used) || !exists(s, fresh) {
+		t.Errorf("after Sweep: unused %v, used %v, fresh %v", exists(s, unused), exists(s, used), exists(s, fresh))
+	}
+}
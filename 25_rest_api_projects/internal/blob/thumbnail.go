@@ -0,0 +1,107 @@
+package blob
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+)
+
+// maxPixels bounds the images Thumbnail decodes, so a small file claiming
+// huge dimensions cannot exhaust memory.
+const maxPixels = 40_000_000
+
+var ErrNotImage = errors.New("not a decodable image")
+
+// Thumbnail stores a PNG of the image blob scaled to fit within size×size,
+// keeping its aspect ratio. Images already that small are only re-encoded.
+func (s *Store) Thumbnail(hash string, size int) (Blob, error) {
+	file, err := s.Open(hash)
+	if err != nil {
+		return Blob{}, err
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return Blob{}, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	if config.Width*config.Height > maxPixels {
+		return Blob{}, fmt.Errorf("%w: %dx%d is too large", ErrNotImage, config.Width, config.Height)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return Blob{}, err
+	}
+
+	src, _, err := image.Decode(file)
+	if err != nil {
+		return Blob{}, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, scale(src, size)); err != nil {
+		return Blob{}, err
+	}
+
+	return s.Put(&buf, int64(buf.Len()), []string{"image/png"})
+}
+
+// scale shrinks src by averaging the source pixels that fall into each
+// destination pixel, which avoids the aliasing of nearest-neighbour.
+func scale(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	if w <= size && h <= size {
+		return src
+	}
+
+	dw, dh := size, size
+	if w > h {
+		dh = max(1, h*size/w)
+	} else {
+		dw = max(1, w*size/h)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		y0 := bounds.Min.Y + y*h/dh
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*h/dh)
+
+		for x := 0; x < dw; x++ {
+			x0 := bounds.Min.X + x*w/dw
+			x1 := max(x0+1, bounds.Min.X+(x+1)*w/dw)
+
+			var r, g, b, a, n uint64
+
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(src.At(sx, sy)).(color.NRGBA64)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,205 @@
+// Package cache is an in-memory read-through cache. It grows the Cache[K,V]
+// from 19_Generics and the RWMutex Cache from 22_mutex into something safe
+// to put in front of storage: entries expire after a TTL, the least
+// recently used entry is evicted once the cache is full, and concurrent
+// misses for the same key share a single load.
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLoadPanicked is returned to callers waiting on a load that panicked.
+// The caller that ran the load gets the panic itself.
+var ErrLoadPanicked = errors.New("cache: load panicked")
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// call is a load in flight. Callers that miss while it runs wait on done
+// and share its result.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	items      map[K]*list.Element
+	order      *list.List // front is most recently used
+	// inflight holds the running load of each key. Invalidation drops a
+	// key from it, so a load that started before then neither stores its
+	// already stale value nor serves callers that arrive afterwards.
+	inflight map[K]*call[V]
+	stats    Stats
+}
+
+// New returns a cache whose entries live for ttl and which holds at most
+// maxEntries. A maxEntries of zero means unbounded.
+func New[K comparable, V any](ttl time.Duration, maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		items:      make(map[K]*list.Element),
+		order:      list.New(),
+		inflight:   make(map[K]*call[V]),
+	}
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key)
+}
+
+func (c *Cache[K, V]) get(key K) (V, bool) {
+	element, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	e := element.Value.(*entry[K, V])
+	if time.Now().After(e.expiresAt) {
+		c.remove(element)
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+// Set stores value for the default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, ttl)
+}
+
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl)
+
+	if element, ok := c.items[key]; ok {
+		e := element.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// GetOrLoad returns the cached value for key or calls load to fill it.
+// Concurrent callers missing on the same key wait for one load instead of
+// each calling it. Errors are returned to every waiter but not cached.
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	c.mu.Lock()
+
+	if value, ok := c.get(key); ok {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return value, nil
+	}
+
+	c.stats.Misses++
+
+	if inflight, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-inflight.done
+		return inflight.value, inflight.err
+	}
+
+	current := &call[V]{done: make(chan struct{})}
+	c.inflight[key] = current
+	c.mu.Unlock()
+
+	// The cleanup is deferred so a panicking load still releases its
+	// waiters instead of blocking them forever.
+	completed := false
+	defer func() {
+		if !completed {
+			current.err = ErrLoadPanicked
+		}
+
+		c.mu.Lock()
+		if c.inflight[key] == current {
+			if current.err == nil {
+				c.set(key, current.value, c.ttl)
+			}
+			delete(c.inflight, key)
+		}
+		c.mu.Unlock()
+
+		close(current.done)
+	}()
+
+	current.value, current.err = load()
+	completed = true
+
+	return current.value, current.err
+}
+
+// Delete invalidates key, including a load of it that is still running.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+
+	delete(c.inflight, key)
+}
+
+// Clear invalidates every entry.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+	c.inflight = make(map[K]*call[V])
+}
+
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+func (c *Cache[K, V]) remove(element *list.Element) {
+	e := c.order.Remove(element).(*entry[K, V])
+	delete(c.items, e.key)
+}
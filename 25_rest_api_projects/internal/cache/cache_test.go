@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEntriesExpire(t *testing.T) {
+	c := New[string, int](time.Hour, 0)
+
+	c.Set("long", 1)
+	c.SetWithTTL("short", 2, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if v, ok := c.Get("long"); !ok || v != 1 {
+		t.Errorf("Get(long) = %d, %v, want 1", v, ok)
+	}
+
+	if _, ok := c.Get("short"); ok {
+		t.Error("expired entry was returned")
+	}
+
+	if stats := c.Stats(); stats.Entries != 1 {
+		t.Errorf("entries = %d, want the expired one removed", stats.Entries)
+	}
+}
+
+func TestLeastRecentlyUsedIsEvicted(t *testing.T) {
+	c := New[string, int](time.Hour, 2)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry b was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("entry %s was evicted", key)
+		}
+	}
+
+	if stats := c.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("stats = %+v, want one eviction and two entries", stats)
+	}
+}
+
+func TestConcurrentMissesShareOneLoad(t *testing.T) {
+	c := New[string, int](time.Hour, 0)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, err := c.GetOrLoad("key", func() (int, error) {
+				loads.Add(1)
+				<-release
+				return 42, nil
+			})
+			if v != 42 || err != nil {
+				t.Errorf("GetOrLoad = %d, %v, want 42", v, err)
+			}
+		}()
+	}
+
+	// Let every goroutine reach the cache before the load finishes.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("load ran %d times, want once", loads.Load())
+	}
+
+	if v, err := c.GetOrLoad("key", func() (int, error) { return 0, errors.New("not called") }); v != 42 || err != nil {
+		t.Errorf("GetOrLoad after the load = %d, %v, want the cached 42", v, err)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	c := New[string, int](time.Hour, 0)
+
+	if _, err := c.GetOrLoad("key", func() (int, error) { return 0, errors.New("boom") }); err == nil {
+		t.Fatal("GetOrLoad swallowed the load error")
+	}
+
+	if v, err := c.GetOrLoad("key", func() (int, error) { return 7, nil }); v != 7 || err != nil {
+		t.Errorf("GetOrLoad after an error = %d, %v, want a fresh load", v, err)
+	}
+}
+
+// startLoad begins a load of key that returns value once release is closed,
+// and waits until it is in flight.
+func startLoad(c *Cache[string, int], key string, value int, release chan struct{}) <-chan int {
+	started := make(chan struct{})
+	result := make(chan int, 1)
+
+	go func() {
+		v, _ := c.GetOrLoad(key, func() (int, error) {
+			close(started)
+			<-release
+			return value, nil
+		})
+		result <- v
+	}()
+
+	<-started
+	return result
+}
+
+func TestInvalidationDuringALoad(t *testing.T) {
+	for name, invalidate := range map[string]func(c *Cache[string, int]){
+		"Delete": func(c *Cache[string, int]) { c.Delete("key") },
+		"Clear":  func(c *Cache[string, int]) { c.Clear() },
+	} {
+		c := New[string, int](time.Hour, 0)
+
+		release := make(chan struct{})
+		stale := startLoad(c, "key", 1, release)
+
+		invalidate(c)
+
+		// A caller arriving after the invalidation must not join the
+		// stale load.
+		if v, err := c.GetOrLoad("key", func() (int, error) { return 2, nil }); v != 2 || err != nil {
+			t.Errorf("%s: GetOrLoad after invalidation = %d, %v, want a fresh 2", name, v, err)
+		}
+
+		close(release)
+		<-stale
+
+		if v, ok := c.Get("key"); !ok || v != 2 {
+			t.Errorf("%s: cached value = %d, %v, want the stale load not to overwrite 2", name, v, ok)
+		}
+	}
+}
+
+func TestPanickingLoadReleasesWaiters(t *testing.T) {
+	c := New[string, int](time.Hour, 0)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	go func() {
+		defer func() { recover() }()
+
+		c.GetOrLoad("key", func() (int, error) {
+			close(started)
+			<-release
+			panic("loader failed")
+		})
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad("key", func() (int, error) { return 0, nil })
+		waiter <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, ErrLoadPanicked) {
+			t.Errorf("waiter error = %v, want ErrLoadPanicked", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter blocked after the load panicked")
+	}
+
+	if v, err := c.GetOrLoad("key", func() (int, error) { return 3, nil }); v != 3 || err != nil {
+		t.Errorf("GetOrLoad after a panic = %d, %v, want a fresh load", v, err)
+	}
+}
@@ -1,9 +1,16 @@
 package config
 
 import (
-	"flag"
-	"log"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/mail"
 	"os"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -11,40 +18,531 @@ import (
 type HTTPServer struct {
 	Addr string `yaml:"address"`
 }
+
+type GRPCServer struct {
+	Addr string `yaml:"address" env:"GRPC_ADDRESS" env-default:"localhost:9092"`
+}
+
+// Webhooks tunes outbound webhook delivery. Backoff doubles from BaseBackoff
+// up to MaxBackoff between attempts of a single delivery. Finished
+// deliveries older than Retention are removed by the purge-deliveries job.
+type Webhooks struct {
+	MaxAttempts  int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+	DisableAfter int           `yaml:"disable_after" env:"WEBHOOKS_DISABLE_AFTER" env-default:"20"`
+	Timeout      time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT" env-default:"10s"`
+	BaseBackoff  time.Duration `yaml:"base_backoff" env:"WEBHOOKS_BASE_BACKOFF" env-default:"1s"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env:"WEBHOOKS_MAX_BACKOFF" env-default:"1h"`
+	Retention    time.Duration `yaml:"retention" env:"WEBHOOKS_RETENTION" env-default:"720h"`
+}
+
+// Events configures the live student event stream.
+type Events struct {
+	ReplayBuffer int           `yaml:"replay_buffer" env:"EVENTS_REPLAY_BUFFER" env-default:"256"`
+	ClientBuffer int           `yaml:"client_buffer" env:"EVENTS_CLIENT_BUFFER" env-default:"64"`
+	Heartbeat    time.Duration `yaml:"heartbeat" env:"EVENTS_HEARTBEAT" env-default:"15s"`
+}
+
+// GraphQL limits how deep and how expensive a single query may be.
+type GraphQL struct {
+	MaxDepth      int `yaml:"max_depth" env:"GRAPHQL_MAX_DEPTH" env-default:"8"`
+	MaxComplexity int `yaml:"max_complexity" env:"GRAPHQL_MAX_COMPLEXITY" env-default:"5000"`
+}
+
+// Auth controls API key checks on /api and /graphql. /api/admin always
+// needs a key. Keys are issued with `students_api create-api-key`.
+type Auth struct {
+	RequireAPIKey bool `yaml:"require_api_key" env:"AUTH_REQUIRE_API_KEY" env-default:"false"`
+}
+
+// Backup configures database snapshots. Interval zero disables the backup
+// loop; the scheduler's backup job and `students_api backup` still work.
+// KeepCount and MaxAge of zero disable that part of the retention policy.
+type Backup struct {
+	Dir       string        `yaml:"dir" env:"BACKUP_DIR" env-default:"storage/backups"`
+	Interval  time.Duration `yaml:"interval" env:"BACKUP_INTERVAL" env-default:"0"`
+	KeepCount int           `yaml:"keep_count" env:"BACKUP_KEEP_COUNT" env-default:"7"`
+	MaxAge    time.Duration `yaml:"max_age" env:"BACKUP_MAX_AGE" env-default:"720h"`
+}
+
+// Cache configures the read-through cache in front of student reads.
+type Cache struct {
+	Enabled    bool          `yaml:"enabled" env:"CACHE_ENABLED" env-default:"true"`
+	TTL        time.Duration `yaml:"ttl" env:"CACHE_TTL" env-default:"30s"`
+	MaxEntries int           `yaml:"max_entries" env:"CACHE_MAX_ENTRIES" env-default:"1024"`
+}
+
+// Outbox configures how stored events are relayed. Sinks lists where they
+// go: "bus" feeds the in-process subscribers (event stream, roster
+// WebSockets, gRPC watch, webhooks) and should normally stay; "file"
+// appends JSON lines to File; "http" POSTs to URL. Sent messages are kept
+// for Retention.
+type Outbox struct {
+	Sinks        []string      `yaml:"sinks" env:"OUTBOX_SINKS" env-separator:"," env-default:"bus"`
+	File         string        `yaml:"file" env:"OUTBOX_FILE" env-default:"storage/outbox.jsonl"`
+	URL          string        `yaml:"url" env:"OUTBOX_URL"`
+	Timeout      time.Duration `yaml:"timeout" env:"OUTBOX_TIMEOUT" env-default:"10s"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL" env-default:"1s"`
+	BatchSize    int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env:"OUTBOX_MAX_BACKOFF" env-default:"1m"`
+	Retention    time.Duration `yaml:"retention" env:"OUTBOX_RETENTION" env-default:"168h"`
+}
+
+// Uploads stores student photos and documents in Dir. Only content that
+// sniffs as one of AllowedTypes is accepted, up to MaxSize bytes; images get
+// a thumbnail fitting ThumbnailSize pixels.
+type Uploads struct {
+	Dir           string   `yaml:"dir" env:"UPLOADS_DIR" env-default:"storage/blobs"`
+	MaxSize       int64    `yaml:"max_size" env:"UPLOADS_MAX_SIZE" env-default:"10485760"`
+	AllowedTypes  []string `yaml:"allowed_types" env:"UPLOADS_ALLOWED_TYPES" env-separator:"," env-default:"image/jpeg,image/png,image/gif,application/pdf"`
+	ThumbnailSize int      `yaml:"thumbnail_size" env:"UPLOADS_THUMBNAIL_SIZE" env-default:"256"`
+}
+
+// Flags reads feature flags from Path, a YAML or JSON file, and reloads it
+// when it changes, checked every ReloadInterval. Without a path no flag is
+// on. Rollouts hash the user key from UserHeader and role targeting reads
+// the comma separated roles in RoleHeader; like the tenancy "header" source
+// both are trusted as sent.
+type Flags struct {
+	Path           string        `yaml:"path" env:"FLAGS_PATH"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"FLAGS_RELOAD_INTERVAL" env-default:"10s"`
+	UserHeader     string        `yaml:"user_header" env:"FLAGS_USER_HEADER" env-default:"X-User-ID"`
+	RoleHeader     string        `yaml:"role_header" env:"FLAGS_ROLE_HEADER" env-default:"X-User-Roles"`
+}
+
+// LogFile rotates the log file at Path once it would grow past MaxSize
+// bytes and at every Interval boundary (UTC), whichever comes first; zero
+// turns either off. Rotated files are gzipped when Compress is set and only
+// the newest MaxBackups are kept, all of them when it is zero.
+type LogFile struct {
+	Path       string        `yaml:"path" env:"LOG_FILE_PATH" env-default:"storage/logs/students-api.log"`
+	MaxSize    int64         `yaml:"max_size" env:"LOG_FILE_MAX_SIZE" env-default:"104857600"`
+	Interval   time.Duration `yaml:"interval" env:"LOG_FILE_INTERVAL" env-default:"24h"`
+	MaxBackups int           `yaml:"max_backups" env:"LOG_FILE_MAX_BACKUPS" env-default:"14"`
+	Compress   bool          `yaml:"compress" env:"LOG_FILE_COMPRESS" env-default:"true"`
+}
+
+// Logging configures the server log. Level is debug, info, warn or error,
+// Format is "text" or "json" and Output is "stderr", "stdout" or "file".
+// Attributes whose key contains one of Redact, ignoring case, are logged as
+// [REDACTED].
+type Logging struct {
+	Level     string   `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+	Format    string   `yaml:"format" env:"LOG_FORMAT" env-default:"text"`
+	AddSource bool     `yaml:"add_source" env:"LOG_ADD_SOURCE" env-default:"false"`
+	Output    string   `yaml:"output" env:"LOG_OUTPUT" env-default:"stderr"`
+	Redact    []string `yaml:"redact" env:"LOG_REDACT" env-separator:"," env-default:"password,secret,token,authorization,api_key,cookie"`
+	File      LogFile  `yaml:"file"`
+}
+
+// SMTP is the mail server the "smtp" transport delivers through. TLS is
+// "starttls" (upgrade the plain connection, failing if the server cannot),
+// "tls" (implicit TLS, usually port 465) or "none". Username enables PLAIN
+// authentication, which is only sent over TLS or to localhost.
+type SMTP struct {
+	Host     string        `yaml:"host" env:"SMTP_HOST" env-default:"localhost"`
+	Port     int           `yaml:"port" env:"SMTP_PORT" env-default:"587"`
+	Username string        `yaml:"username" env:"SMTP_USERNAME"`
+	Password string        `yaml:"password" env:"SMTP_PASSWORD"`
+	TLS      string        `yaml:"tls" env:"SMTP_TLS" env-default:"starttls"`
+	Timeout  time.Duration `yaml:"timeout" env:"SMTP_TIMEOUT" env-default:"30s"`
+}
+
+// Mail emails students when they enrol and when their status changes.
+// Messages are queued in the database and sent by Transport: "smtp", "file"
+// (one .eml file per message in Dir) or "sink" (an in-process SMTP server
+// on SinkAddress that keeps what it receives, for development and tests).
+// Failed sends are retried with backoff up to MaxAttempts. Templates
+// overrides the built-in templates with the *.tmpl files of a directory.
+type Mail struct {
+	Enabled     bool          `yaml:"enabled" env:"MAIL_ENABLED" env-default:"false"`
+	Transport   string        `yaml:"transport" env:"MAIL_TRANSPORT" env-default:"smtp"`
+	From        string        `yaml:"from" env:"MAIL_FROM" env-default:"Students API <no-reply@localhost>"`
+	SMTP        SMTP          `yaml:"smtp"`
+	Dir         string        `yaml:"dir" env:"MAIL_DIR" env-default:"storage/mail"`
+	SinkAddress string        `yaml:"sink_address" env:"MAIL_SINK_ADDRESS" env-default:"localhost:2525"`
+	Templates   string        `yaml:"templates" env:"MAIL_TEMPLATES"`
+	MaxAttempts int           `yaml:"max_attempts" env:"MAIL_MAX_ATTEMPTS" env-default:"6"`
+	BaseBackoff time.Duration `yaml:"base_backoff" env:"MAIL_BASE_BACKOFF" env-default:"30s"`
+	MaxBackoff  time.Duration `yaml:"max_backoff" env:"MAIL_MAX_BACKOFF" env-default:"1h"`
+	Retention   time.Duration `yaml:"retention" env:"MAIL_RETENTION" env-default:"720h"`
+}
+
+// SchedulerJob runs a maintenance job on Schedule, a 5-field cron
+// expression, a macro like "@daily" or "@every 1h". Runs start up to Jitter
+// late and are cancelled after Timeout; zero means no limit.
+type SchedulerJob struct {
+	Schedule string        `yaml:"schedule"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Jitter   time.Duration `yaml:"jitter"`
+}
+
+// Scheduler runs the maintenance jobs in Jobs, keyed by job name, inside
+// the server. Cron expressions are read in Timezone.
+type Scheduler struct {
+	Enabled  bool                    `yaml:"enabled" env:"SCHEDULER_ENABLED" env-default:"true"`
+	Timezone string                  `yaml:"timezone" env:"SCHEDULER_TIMEZONE" env-default:"UTC"`
+	Jobs     map[string]SchedulerJob `yaml:"jobs"`
+}
+
+// Reports sets how generated transcripts and rosters look. Institution is
+// printed at the top unless the tenant has a Name of its own; PageSize is
+// "a4" or "letter". The course-rosters job writes into Dir.
+type Reports struct {
+	Institution string `yaml:"institution" env:"REPORTS_INSTITUTION" env-default:"Students API"`
+	PageSize    string `yaml:"page_size" env:"REPORTS_PAGE_SIZE" env-default:"a4"`
+	Dir         string `yaml:"dir" env:"REPORTS_DIR" env-default:"storage/reports"`
+}
+
+// CORS lets browser apps on other origins call the API. An empty
+// AllowedOrigins turns CORS off; "*" allows any origin and entries like
+// "https://*.example.com" allow its subdomains. MaxAge is how long browsers
+// may cache a preflight answer.
+type CORS struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" env-separator:","`
+	AllowedMethods   []string      `yaml:"allowed_methods" env:"CORS_ALLOWED_METHODS" env-separator:"," env-default:"GET,POST,PUT,DELETE"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" env-separator:"," env-default:"Authorization,Content-Type,X-API-Key,X-Request-ID,X-Tenant-ID,traceparent"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" env:"CORS_EXPOSED_HEADERS" env-separator:"," env-default:"X-Request-ID,Retry-After"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" env-default:"false"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE" env-default:"10m"`
+}
+
+// Security sets protective headers on every response. Empty values leave
+// the header out; an HSTSMaxAge of zero disables HSTS.
+type Security struct {
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age" env:"SECURITY_HSTS_MAX_AGE" env-default:"4320h"`
+	HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains" env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS" env-default:"false"`
+	ContentSecurityPolicy string        `yaml:"content_security_policy" env:"SECURITY_CSP" env-default:"default-src 'none'; frame-ancestors 'none'"`
+	FrameOptions          string        `yaml:"frame_options" env:"SECURITY_FRAME_OPTIONS" env-default:"DENY"`
+	ReferrerPolicy        string        `yaml:"referrer_policy" env:"SECURITY_REFERRER_POLICY" env-default:"no-referrer"`
+}
+
+// BodyLimits caps request bodies at MaxSize bytes. Routes overrides it for
+// route patterns as they are registered, such as "POST /graphql".
+type BodyLimits struct {
+	MaxSize int64            `yaml:"max_size" env:"BODY_MAX_SIZE" env-default:"1048576"`
+	Routes  map[string]int64 `yaml:"routes"`
+}
+
+// Batch limits POST /api/batch, which applies up to MaxOperations student
+// changes sent in a body of at most MaxSize bytes.
+type Batch struct {
+	MaxOperations int   `yaml:"max_operations" env:"BATCH_MAX_OPERATIONS" env-default:"1000"`
+	MaxSize       int64 `yaml:"max_size" env:"BATCH_MAX_SIZE" env-default:"4194304"`
+}
+
+// Deprecation announces that an API version is going away. Since, and
+// Sunset when the version has an end date, are dates (YYYY-MM-DD) sent in
+// the Deprecation and Sunset headers; Link points clients at the
+// migration notes.
+type Deprecation struct {
+	Since  string `yaml:"since"`
+	Sunset string `yaml:"sunset"`
+	Link   string `yaml:"link"`
+}
+
+// Versioning serves the API under /v1 and /v2. Requests to /api are
+// served the version named in the Accept header, as in
+// "application/vnd.students.v2+json", or Default. Deprecations lists the
+// versions whose responses carry deprecation headers.
+type Versioning struct {
+	Default      int                 `yaml:"default" env:"API_DEFAULT_VERSION" env-default:"1"`
+	Deprecations map[int]Deprecation `yaml:"deprecations"`
+}
+
+// Compression gzips or deflates responses of at least MinSize bytes for
+// clients that accept it, leaving out content types starting with one of
+// SkipTypes since they are compressed already. Level is a compress/flate
+// level. Request bodies sent gzip or deflate encoded are decoded, and
+// rejected with 413 once they inflate past MaxDecodedSize bytes.
+type Compression struct {
+	Enabled        bool     `yaml:"enabled" env:"COMPRESSION_ENABLED" env-default:"true"`
+	Level          int      `yaml:"level" env:"COMPRESSION_LEVEL" env-default:"-1"`
+	MinSize        int      `yaml:"min_size" env:"COMPRESSION_MIN_SIZE" env-default:"1024"`
+	SkipTypes      []string `yaml:"skip_types" env:"COMPRESSION_SKIP_TYPES" env-separator:"," env-default:"image/,video/,audio/,font/woff,application/zip,application/gzip,application/x-gzip,application/zstd,application/x-7z-compressed,application/vnd.openxmlformats-officedocument."`
+	MaxDecodedSize int64    `yaml:"max_decoded_size" env:"COMPRESSION_MAX_DECODED_SIZE" env-default:"10485760"`
+}
+
+// Tracing exports spans for requests and storage calls. Exporter "otlp"
+// POSTs OTLP/HTTP JSON to Endpoint; "file" appends JSON lines to File.
+// SampleRatio applies to new traces only; incoming traceparent headers
+// decide for the traces they continue.
+type Tracing struct {
+	Enabled       bool          `yaml:"enabled" env:"TRACING_ENABLED" env-default:"false"`
+	ServiceName   string        `yaml:"service_name" env:"TRACING_SERVICE_NAME" env-default:"students-api"`
+	Exporter      string        `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"file"`
+	Endpoint      string        `yaml:"endpoint" env:"TRACING_ENDPOINT" env-default:"http://localhost:4318/v1/traces"`
+	File          string        `yaml:"file" env:"TRACING_FILE" env-default:"storage/traces.jsonl"`
+	SampleRatio   float64       `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+	BatchSize     int           `yaml:"batch_size" env:"TRACING_BATCH_SIZE" env-default:"256"`
+	FlushInterval time.Duration `yaml:"flush_interval" env:"TRACING_FLUSH_INTERVAL" env-default:"5s"`
+	Timeout       time.Duration `yaml:"timeout" env:"TRACING_TIMEOUT" env-default:"10s"`
+}
+
+// Tenant holds the settings a school can override. Zero values fall back
+// to the shared defaults in Tenancy. Features turns feature flags on or off
+// for the school regardless of their rollout.
+type Tenant struct {
+	Name      string          `yaml:"name"`
+	RateLimit float64         `yaml:"rate_limit"`
+	Burst     int             `yaml:"burst"`
+	Features  map[string]bool `yaml:"features"`
+}
+
+// JWT verifies HS256 bearer tokens naming the tenant in Claim.
+type JWT struct {
+	Secret string `yaml:"secret" env:"TENANCY_JWT_SECRET"`
+	Claim  string `yaml:"claim" env:"TENANCY_JWT_CLAIM" env-default:"tenant"`
+}
+
+// Tenancy lets one server host several schools. Sources are tried in
+// order and default to "jwt" and "subdomain". "header" trusts whatever the
+// client sends, so any API key could pick any school with it; it is off by
+// default and only belongs behind a trusted gateway that authenticates
+// callers and sets the header itself. Requests naming no tenant go to
+// Default, or are rejected when it is empty. RateLimit is requests per
+// second (zero for no limit), overridable per tenant.
+type Tenancy struct {
+	Enabled   bool              `yaml:"enabled" env:"TENANCY_ENABLED" env-default:"false"`
+	Sources   []string          `yaml:"sources" env:"TENANCY_SOURCES" env-separator:"," env-default:"jwt,subdomain"`
+	Header    string            `yaml:"header" env:"TENANCY_HEADER" env-default:"X-Tenant-ID"`
+	Domain    string            `yaml:"domain" env:"TENANCY_DOMAIN"`
+	Default   string            `yaml:"default" env:"TENANCY_DEFAULT"`
+	RateLimit float64           `yaml:"rate_limit" env:"TENANCY_RATE_LIMIT" env-default:"0"`
+	Burst     int               `yaml:"burst" env:"TENANCY_BURST" env-default:"20"`
+	JWT       JWT               `yaml:"jwt"`
+	Tenants   map[string]Tenant `yaml:"tenants"`
+}
+
 type Config struct {
 	Env         string `yaml:"env" env:"ENV" env-required:"true" env-default:"production"`
 	StoragePath string `yaml:"storage_path" env:"STORAGE_PATH" env-required:"true"`
 	HTTPServer  `yaml:"http_server" env:"HTTP_SERVER" env-required:"true"`
+	GRPCServer  GRPCServer  `yaml:"grpc_server"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+	Events      Events      `yaml:"events"`
+	GraphQL     GraphQL     `yaml:"graphql"`
+	Auth        Auth        `yaml:"auth"`
+	Backup      Backup      `yaml:"backup"`
+	Cache       Cache       `yaml:"cache"`
+	Outbox      Outbox      `yaml:"outbox"`
+	Tenancy     Tenancy     `yaml:"tenancy"`
+	Tracing     Tracing     `yaml:"tracing"`
+	CORS        CORS        `yaml:"cors"`
+	Security    Security    `yaml:"security"`
+	BodyLimits  BodyLimits  `yaml:"body_limits"`
+	Batch       Batch       `yaml:"batch"`
+	Compression Compression `yaml:"compression"`
+	Versioning  Versioning  `yaml:"versioning"`
+	Uploads     Uploads     `yaml:"uploads"`
+	Reports     Reports     `yaml:"reports"`
+	Scheduler   Scheduler   `yaml:"scheduler"`
+	Mail        Mail        `yaml:"mail"`
+	Logging     Logging     `yaml:"logging"`
+	Flags       Flags       `yaml:"flags"`
 }
 
-func MustLoad() *Config {
+// Load reads the configuration file at path, falling back to the
+// CONFIG_PATH environment variable when path is empty. Environment
+// variables override values from the file.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv("CONFIG_PATH")
+	}
+
+	if path == "" {
+		return nil, errors.New("CONFIG_PATH environment variable or -config flag must be set")
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("configuration file does not exist: %s", path)
+	}
+
+	var cfg Config
+
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		return nil, fmt.Errorf("error reading configuration: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks the values cleanenv cannot: that addresses parse, the
+// storage directory exists and numeric settings are in range.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("http_server.address: %w", err))
+	}
+
+	if _, _, err := net.SplitHostPort(c.GRPCServer.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("grpc_server.address: %w", err))
+	}
+
+	if info, err := os.Stat(filepath.Dir(c.StoragePath)); err != nil {
+		errs = append(errs, fmt.Errorf("storage_path: %w", err))
+	} else if !info.IsDir() {
+		errs = append(errs, fmt.Errorf("storage_path: %s is not a directory", filepath.Dir(c.StoragePath)))
+	}
+
+	if c.Webhooks.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("webhooks.max_attempts must be positive"))
+	}
+
+	if c.Webhooks.BaseBackoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.BaseBackoff {
+		errs = append(errs, errors.New("webhooks.base_backoff must be positive and at most webhooks.max_backoff"))
+	}
+
+	if c.Webhooks.Retention <= 0 {
+		errs = append(errs, errors.New("webhooks.retention must be positive"))
+	}
 
-	var configPath string
+	if c.Events.ReplayBuffer < 0 || c.Events.ClientBuffer <= 0 {
+		errs = append(errs, errors.New("events.client_buffer must be positive and events.replay_buffer not negative"))
+	}
 
-	configPath = os.Getenv("CONFIG_PATH")
+	if c.Events.Heartbeat <= 0 {
+		errs = append(errs, errors.New("events.heartbeat must be positive"))
+	}
+
+	if c.Cache.Enabled && (c.Cache.TTL <= 0 || c.Cache.MaxEntries < 0) {
+		errs = append(errs, errors.New("cache.ttl must be positive and cache.max_entries not negative"))
+	}
+
+	if c.Outbox.PollInterval <= 0 || c.Outbox.BatchSize <= 0 {
+		errs = append(errs, errors.New("outbox.poll_interval and outbox.batch_size must be positive"))
+	}
+
+	if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*") {
+		errs = append(errs, errors.New(`cors: allow_credentials cannot be combined with the "*" origin`))
+	}
 
-	if configPath == "" {
-		flags := flag.String("config", "", "Path to the configuration file")
-		flag.Parse()
-		configPath = *flags
+	if c.BodyLimits.MaxSize <= 0 {
+		errs = append(errs, errors.New("body_limits.max_size must be positive"))
+	}
 
-		if configPath == "" {
-			log.Fatal("CONFIG_PATH environment variable or --config flag must be set")
+	for route, size := range c.BodyLimits.Routes {
+		if size <= 0 {
+			errs = append(errs, fmt.Errorf("body_limits.routes[%q] must be positive", route))
 		}
+	}
 
+	if c.Batch.MaxOperations <= 0 || c.Batch.MaxSize <= 0 {
+		errs = append(errs, errors.New("batch.max_operations and batch.max_size must be positive"))
 	}
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		log.Fatalf("Configuration file does not exist: %s", configPath)
+	if c.Uploads.MaxSize <= 0 || c.Uploads.ThumbnailSize <= 0 {
+		errs = append(errs, errors.New("uploads.max_size and uploads.thumbnail_size must be positive"))
 	}
 
-	var cfg Config
+	if c.Reports.PageSize != "a4" && c.Reports.PageSize != "letter" {
+		errs = append(errs, fmt.Errorf("reports.page_size: unknown page size %q", c.Reports.PageSize))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("logging.level: %w", err))
+	}
+
+	if c.Logging.Format != "text" && c.Logging.Format != "json" {
+		errs = append(errs, fmt.Errorf("logging.format: unknown format %q", c.Logging.Format))
+	}
+
+	if !slices.Contains([]string{"stderr", "stdout", "file"}, c.Logging.Output) {
+		errs = append(errs, fmt.Errorf("logging.output: unknown output %q", c.Logging.Output))
+	}
+
+	if c.Logging.File.MaxSize < 0 || c.Logging.File.Interval < 0 || c.Logging.File.MaxBackups < 0 {
+		errs = append(errs, errors.New("logging.file.max_size, logging.file.interval and logging.file.max_backups must not be negative"))
+	}
+
+	if c.Compression.Level < flate.HuffmanOnly || c.Compression.Level > flate.BestCompression {
+		errs = append(errs, fmt.Errorf("compression.level must be between %d and %d", flate.HuffmanOnly, flate.BestCompression))
+	}
+
+	if c.Compression.MinSize < 0 || c.Compression.MaxDecodedSize <= 0 {
+		errs = append(errs, errors.New("compression.min_size must not be negative and compression.max_decoded_size must be positive"))
+	}
+
+	if c.Versioning.Default < 1 || c.Versioning.Default > 2 {
+		errs = append(errs, fmt.Errorf("versioning.default: unknown version %d", c.Versioning.Default))
+	}
 
-	err := cleanenv.ReadConfig(configPath, &cfg)
+	for version, deprecation := range c.Versioning.Deprecations {
+		if version < 1 || version > 2 {
+			errs = append(errs, fmt.Errorf("versioning.deprecations: unknown version %d", version))
+		}
+
+		if _, err := time.Parse(time.DateOnly, deprecation.Since); err != nil {
+			errs = append(errs, fmt.Errorf("versioning.deprecations.%d.since: %w", version, err))
+		}
+
+		if _, err := time.Parse(time.DateOnly, deprecation.Sunset); deprecation.Sunset != "" && err != nil {
+			errs = append(errs, fmt.Errorf("versioning.deprecations.%d.sunset: %w", version, err))
+		}
+	}
+
+	if c.Flags.Path != "" && c.Flags.ReloadInterval <= 0 {
+		errs = append(errs, errors.New("flags.reload_interval must be positive"))
+	}
+
+	if c.Mail.Enabled {
+		if !slices.Contains([]string{"smtp", "file", "sink"}, c.Mail.Transport) {
+			errs = append(errs, fmt.Errorf("mail.transport: unknown transport %q", c.Mail.Transport))
+		}
+
+		if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+			errs = append(errs, fmt.Errorf("mail.from: %w", err))
+		}
+
+		if !slices.Contains([]string{"starttls", "tls", "none"}, c.Mail.SMTP.TLS) {
+			errs = append(errs, fmt.Errorf("mail.smtp.tls: unknown mode %q", c.Mail.SMTP.TLS))
+		}
+
+		if c.Mail.MaxAttempts <= 0 || c.Mail.BaseBackoff <= 0 || c.Mail.MaxBackoff < c.Mail.BaseBackoff {
+			errs = append(errs, errors.New("mail.max_attempts and mail.base_backoff must be positive and mail.base_backoff at most mail.max_backoff"))
+		}
+
+		if c.Mail.SMTP.Timeout <= 0 || c.Mail.Retention <= 0 {
+			errs = append(errs, errors.New("mail.smtp.timeout and mail.retention must be positive"))
+		}
+	}
+
+	if _, err := time.LoadLocation(c.Scheduler.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("scheduler.timezone: %w", err))
+	}
+
+	for name, job := range c.Scheduler.Jobs {
+		if job.Timeout < 0 || job.Jitter < 0 {
+			errs = append(errs, fmt.Errorf("scheduler.jobs[%q]: timeout and jitter must not be negative", name))
+		}
+	}
+
+	if c.Tracing.Exporter != "otlp" && c.Tracing.Exporter != "file" {
+		errs = append(errs, fmt.Errorf("tracing.exporter: unknown exporter %q", c.Tracing.Exporter))
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("tracing.sample_ratio must be between 0 and 1"))
+	}
+
+	if c.Tracing.BatchSize <= 0 || c.Tracing.FlushInterval <= 0 {
+		errs = append(errs, errors.New("tracing.batch_size and tracing.flush_interval must be positive"))
+	}
+
+	for _, source := range c.Tenancy.Sources {
+		if source != "header" && source != "subdomain" && source != "jwt" {
+			errs = append(errs, fmt.Errorf("tenancy.sources: unknown source %q", source))
+		}
+	}
+
+	if c.Tenancy.Enabled && slices.Contains(c.Tenancy.Sources, "jwt") && c.Tenancy.JWT.Secret == "" {
+		errs = append(errs, errors.New("tenancy.jwt.secret is required when the jwt source is enabled"))
+	}
 
-	if err != nil {
-		log.Fatalf("Error reading environment variables: %s", err.Error())
+	if c.Backup.Interval < 0 || c.Backup.KeepCount < 0 || c.Backup.MaxAge < 0 {
+		errs = append(errs, errors.New("backup.interval, backup.keep_count and backup.max_age must not be negative"))
 	}
 
-	return &cfg
+	return errors.Join(errs...)
 }
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// load writes yaml to a temporary file and loads it.
+func load(t *testing.T, yaml string) *Config {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	yaml = strings.ReplaceAll(yaml, "$DIR", dir)
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return cfg
+}
+
+const minimal = `
+env: "test"
+storage_path: "$DIR/storage.db"
+http_server:
+  address: "localhost:8082"
+`
+
+func TestDefaultsAreValid(t *testing.T) {
+	cfg := load(t, minimal)
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if cfg.Reports.PageSize != "a4" || cfg.Logging.Format != "text" {
+		t.Errorf("reports.page_size = %q, logging.format = %q, want the defaults", cfg.Reports.PageSize, cfg.Logging.Format)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := load(t, minimal+`
+reports:
+  page_size: tabloid
+logging:
+  format: xml
+tenancy:
+  sources: [header, cookie]
+`)
+	cfg.StoragePath = filepath.Join(t.TempDir(), "missing", "storage.db")
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate accepted an invalid configuration")
+	}
+
+	for _, want := range []string{"storage_path", "reports.page_size", "logging.format", `unknown source "cookie"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestLoadRequiresAPath(t *testing.T) {
+	t.Setenv("CONFIG_PATH", "")
+
+	if _, err := Load(""); err == nil {
+		t.Error("Load without a path succeeded")
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+}
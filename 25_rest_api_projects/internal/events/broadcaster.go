@@ -0,0 +1,194 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Sequenced is an event numbered by the broadcaster that delivered it. Seq
+// increases by one per event; together with Epoch it is what clients resume
+// from.
+type Sequenced struct {
+	Epoch string
+	Seq   uint64
+	Event Event
+}
+
+// Id formats the position of the event as "<epoch>-<seq>".
+func (s Sequenced) Id() string {
+	return s.Epoch + "-" + strconv.FormatUint(s.Seq, 10)
+}
+
+// ParseId splits an id written by Sequenced.Id. A bare sequence number, as
+// issued before ids carried an epoch, parses with an empty epoch so that it
+// never matches a running broadcaster.
+func ParseId(id string) (epoch string, seq uint64, err error) {
+	epoch, num, found := strings.Cut(id, "-")
+	if !found {
+		epoch, num = "", id
+	}
+
+	seq, err = strconv.ParseUint(num, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid event id: %q", id)
+	}
+
+	return epoch, seq, nil
+}
+
+// Subscription receives live events on C. Done is closed when the
+// subscription ends, either through Unsubscribe, because the listener fell
+// behind, or because the broadcaster was closed.
+type Subscription struct {
+	C    <-chan Sequenced
+	Done <-chan struct{}
+
+	ch          chan Sequenced
+	done        chan struct{}
+	broadcaster *Broadcaster
+	once        sync.Once
+}
+
+func (s *Subscription) Unsubscribe() {
+	s.broadcaster.remove(s)
+}
+
+func (s *Subscription) end() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Broadcaster fans events out to subscribers like the Broadcaster in
+// 21_channels, with two differences: it keeps the last few events so a
+// reconnecting client can resume, and a listener whose buffer is full is
+// cut off instead of silently missing events, so it knows to reconnect.
+// Sequence numbers restart with the process, so every broadcaster draws a
+// random epoch and ids from another epoch are never resumed.
+type Broadcaster struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	replay      []Sequenced
+	replaySize  int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+func NewBroadcaster(replaySize int, bufferSize int) *Broadcaster {
+	epoch := make([]byte, 6)
+	rand.Read(epoch)
+
+	return &Broadcaster{
+		epoch:       hex.EncodeToString(epoch),
+		replaySize:  replaySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Epoch identifies this broadcaster's sequence numbers.
+func (b *Broadcaster) Epoch() string {
+	return b.epoch
+}
+
+// Publish implements Publisher.
+func (b *Broadcaster) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.seq++
+	item := Sequenced{Epoch: b.epoch, Seq: b.seq, Event: event}
+
+	if b.replaySize > 0 {
+		if len(b.replay) == b.replaySize {
+			b.replay = append(b.replay[:0], b.replay[1:]...)
+		}
+		b.replay = append(b.replay, item)
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- item:
+		default:
+			delete(b.subscribers, sub)
+			sub.end()
+		}
+	}
+}
+
+// Subscribe registers a listener. When resume is true, buffered events with
+// a sequence greater than after are returned for replay; complete is false
+// if some of those events have already left the replay buffer or after was
+// numbered in another epoch.
+func (b *Broadcaster) Subscribe(epoch string, after uint64, resume bool) (sub *Subscription, replay []Sequenced, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Sequenced, b.bufferSize)
+	done := make(chan struct{})
+
+	sub = &Subscription{C: ch, Done: done, ch: ch, done: done, broadcaster: b}
+	complete = true
+
+	if b.closed {
+		sub.end()
+		return sub, nil, complete
+	}
+
+	b.subscribers[sub] = struct{}{}
+
+	if !resume {
+		return sub, nil, complete
+	}
+
+	// An id from another epoch was issued before a restart, and one ahead
+	// of ours cannot be trusted either; nothing to replay.
+	if epoch != b.epoch || after > b.seq {
+		return sub, nil, false
+	}
+
+	if after == b.seq {
+		return sub, nil, complete
+	}
+
+	if len(b.replay) == 0 || b.replay[0].Seq > after+1 {
+		complete = false
+	}
+
+	for _, item := range b.replay {
+		if item.Seq > after {
+			replay = append(replay, item)
+		}
+	}
+
+	return sub, replay, complete
+}
+
+func (b *Broadcaster) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, sub)
+	sub.end()
+}
+
+// Close ends every subscription and ignores later events.
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		sub.end()
+	}
+}
@@ -0,0 +1,134 @@
+package events
+
+import "testing"
+
+func publishN(b *Broadcaster, n int) {
+	for i := 0; i < n; i++ {
+		b.Publish(New(StudentCreated, int64(i+1), nil))
+	}
+}
+
+func TestSubscribeReplaysMissedEvents(t *testing.T) {
+	b := NewBroadcaster(4, 8)
+	publishN(b, 3)
+
+	sub, replay, complete := b.Subscribe(b.Epoch(), 1, true)
+	defer sub.Unsubscribe()
+
+	if !complete {
+		t.Error("replay from a buffered id reported incomplete")
+	}
+
+	if len(replay) != 2 || replay[0].Seq != 2 || replay[1].Seq != 3 {
+		t.Errorf("replay = %+v, want events 2 and 3", replay)
+	}
+
+	b.Publish(New(StudentUpdated, 1, nil))
+
+	if item := <-sub.C; item.Seq != 4 || item.Event.Type != StudentUpdated {
+		t.Errorf("live event = %+v, want seq 4", item)
+	}
+}
+
+func TestSubscribeReportsEventsLostFromTheBuffer(t *testing.T) {
+	b := NewBroadcaster(2, 8)
+	publishN(b, 5)
+
+	sub, replay, complete := b.Subscribe(b.Epoch(), 1, true)
+	defer sub.Unsubscribe()
+
+	if complete {
+		t.Error("replay past the buffer reported complete")
+	}
+
+	if len(replay) != 2 || replay[0].Seq != 4 {
+		t.Errorf("replay = %+v, want what is left of the buffer", replay)
+	}
+
+	ahead, replay, complete := b.Subscribe(b.Epoch(), 100, true)
+	defer ahead.Unsubscribe()
+
+	if complete || len(replay) != 0 {
+		t.Errorf("resume from a future id = %v, %+v, want an incomplete empty replay", complete, replay)
+	}
+}
+
+func TestSubscribeResetsIdsFromAnotherEpoch(t *testing.T) {
+	// The process restarted: the old broadcaster's sequence numbers are
+	// still held by clients, and the new one has reached the same numbers.
+	before := NewBroadcaster(8, 8)
+	publishN(before, 3)
+
+	b := NewBroadcaster(8, 8)
+	publishN(b, 3)
+
+	if before.Epoch() == b.Epoch() {
+		t.Fatalf("two broadcasters share epoch %q", b.Epoch())
+	}
+
+	for _, epoch := range []string{before.Epoch(), ""} {
+		sub, replay, complete := b.Subscribe(epoch, 1, true)
+		sub.Unsubscribe()
+
+		if complete || len(replay) != 0 {
+			t.Errorf("resume from epoch %q = %v, %+v, want an incomplete empty replay", epoch, complete, replay)
+		}
+	}
+}
+
+func TestParseIdRoundTrips(t *testing.T) {
+	item := Sequenced{Epoch: "3f2a9c", Seq: 42}
+
+	epoch, seq, err := ParseId(item.Id())
+	if err != nil || epoch != "3f2a9c" || seq != 42 {
+		t.Errorf("ParseId(%q) = %q, %d, %v", item.Id(), epoch, seq, err)
+	}
+
+	// Ids issued before epochs existed parse, but never match one.
+	if epoch, seq, err := ParseId("7"); err != nil || epoch != "" || seq != 7 {
+		t.Errorf(`ParseId("7") = %q, %d, %v`, epoch, seq, err)
+	}
+
+	for _, id := range []string{"abc", "3f2a9c-", "3f2a9c-x"} {
+		if _, _, err := ParseId(id); err == nil {
+			t.Errorf("ParseId(%q) accepted an invalid id", id)
+		}
+	}
+}
+
+func TestSlowSubscriberIsCutOff(t *testing.T) {
+	b := NewBroadcaster(0, 1)
+
+	sub, _, _ := b.Subscribe("", 0, false)
+	publishN(b, 2)
+
+	select {
+	case <-sub.Done:
+	default:
+		t.Fatal("subscriber with a full buffer was not ended")
+	}
+
+	if item := <-sub.C; item.Seq != 1 {
+		t.Errorf("buffered event = %+v, want seq 1", item)
+	}
+}
+
+func TestCloseEndsSubscriptions(t *testing.T) {
+	b := NewBroadcaster(1, 1)
+	sub, _, _ := b.Subscribe("", 0, false)
+
+	b.Close()
+
+	select {
+	case <-sub.Done:
+	default:
+		t.Fatal("Close did not end the subscription")
+	}
+
+	late, _, _ := b.Subscribe("", 0, false)
+	select {
+	case <-late.Done:
+	default:
+		t.Error("subscribing after Close returned a live subscription")
+	}
+}
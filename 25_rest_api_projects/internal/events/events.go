@@ -0,0 +1,191 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+type Type string
+
+const (
+	StudentCreated      Type = "student.created"
+	StudentUpdated      Type = "student.updated"
+	StudentDeleted      Type = "student.deleted"
+	StudentTransitioned Type = "student.transitioned"
+	StudentEnrolled     Type = "enrollment.created"
+	StudentUnenrolled   Type = "enrollment.deleted"
+	GradeRecorded       Type = "enrollment.graded"
+)
+
+func AllTypes() []Type {
+	return []Type{
+		StudentCreated, StudentUpdated, StudentDeleted, StudentTransitioned,
+		StudentEnrolled, StudentUnenrolled, GradeRecorded,
+	}
+}
+
+func (t Type) IsValid() bool {
+	for _, known := range AllTypes() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event describes a change to a student or one of their enrollments. Data
+// holds the resource as it was after the change (nil for deletions).
+// CourseId is only set for enrollment events.
+type Event struct {
+	Id         string    `json:"id"`
+	Type       Type      `json:"type"`
+	Tenant     string    `json:"tenant,omitempty"`
+	StudentId  int64     `json:"student_id"`
+	CourseId   int64     `json:"course_id,omitempty"`
+	Data       any       `json:"data,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func New(eventType Type, studentId int64, data any) Event {
+	return Event{
+		Id:         newId(),
+		Type:       eventType,
+		StudentId:  studentId,
+		Data:       data,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+func NewEnrollment(eventType Type, studentId int64, courseId int64, data any) Event {
+	event := New(eventType, studentId, data)
+	event.CourseId = courseId
+	return event
+}
+
+func newId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type Publisher interface {
+	Publish(event Event)
+}
+
+// PublisherFunc adapts a plain function to the Publisher interface.
+type PublisherFunc func(event Event)
+
+func (f PublisherFunc) Publish(event Event) {
+	f(event)
+}
+
+// Queue is a subscriber that stores each event for work done later, such as
+// the webhook and mail dispatchers. An error means the event was not stored
+// and should be offered again; a queue ignores an event it already holds.
+type Queue interface {
+	Enqueue(event Event) error
+}
+
+// Bus fans every event out to all subscribers: queues first, then
+// publishers, each in the order they subscribed.
+type Bus struct {
+	mu          sync.RWMutex
+	queues      []Queue
+	subscribers []Publisher
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(p Publisher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, p)
+}
+
+func (b *Bus) SubscribeQueue(q Queue) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queues = append(b.queues, q)
+}
+
+// Deliver hands event to every queue and then to every publisher. When a
+// queue fails, the publishers are skipped and the errors returned, so the
+// caller can offer the event again without live subscribers seeing it
+// twice.
+func (b *Bus) Deliver(event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var errs []error
+	for _, queue := range b.queues {
+		if err := queue.Enqueue(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, subscriber := range b.subscribers {
+		subscriber.Publish(event)
+	}
+
+	return nil
+}
+
+// UnmarshalJSON restores Data to the concrete type each event carries, so
+// events read back from the outbox look the same as freshly created ones.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type plain Event
+
+	var raw struct {
+		plain
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*e = Event(raw.plain)
+	e.Data = nil
+
+	if len(raw.Data) == 0 || string(raw.Data) == "null" {
+		return nil
+	}
+
+	var err error
+
+	switch e.Type {
+	case StudentCreated, StudentUpdated:
+		e.Data, err = decode[types.Student](raw.Data)
+	case StudentTransitioned:
+		e.Data, err = decode[types.StudentTransition](raw.Data)
+	case StudentEnrolled, GradeRecorded:
+		e.Data, err = decode[types.Enrollment](raw.Data)
+	default:
+		e.Data, err = decode[any](raw.Data)
+	}
+
+	if err != nil {
+		return fmt.Errorf("event %s data: %w", e.Type, err)
+	}
+
+	return nil
+}
+
+func decode[T any](data json.RawMessage) (any, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
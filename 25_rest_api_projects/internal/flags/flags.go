@@ -0,0 +1,173 @@
+// Package flags decides whether a feature is on for a request, so new
+// endpoints can ship dark and then be opened to some schools, some roles or
+// a share of users before everyone.
+package flags
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+)
+
+// Why a flag evaluated the way it did, as listed by the admin endpoint.
+const (
+	ReasonUnknown    = "unknown flag"
+	ReasonDisabled   = "disabled"
+	ReasonTenant     = "tenant override"
+	ReasonNotTenant  = "tenant not targeted"
+	ReasonNotRole    = "role not targeted"
+	ReasonNoKey      = "no user key for rollout"
+	ReasonOutRollout = "outside rollout"
+	ReasonInRollout  = "inside rollout"
+	ReasonEnabled    = "enabled"
+)
+
+// rolloutResolution is the number of buckets user keys hash into, so
+// rollouts can be set in hundredths of a percent.
+const rolloutResolution = 10000
+
+// Flag is one entry of the flags file. Enabled false turns the flag off
+// everywhere, tenant overrides included. Otherwise a school's
+// config.Tenant.Features entry decides for that school, then the flag is
+// on for requests matching Tenants and Roles, when set, and for Rollout
+// percent of user keys, all of them when it is unset.
+type Flag struct {
+	Description string   `yaml:"description" json:"description"`
+	Enabled     bool     `yaml:"enabled" json:"enabled"`
+	Rollout     *float64 `yaml:"rollout" json:"rollout"`
+	Tenants     []string `yaml:"tenants" json:"tenants"`
+	Roles       []string `yaml:"roles" json:"roles"`
+}
+
+type Evaluation struct {
+	Name        string `json:"name"`
+	Enabled     bool   `json:"enabled"`
+	Reason      string `json:"reason"`
+	Description string `json:"description,omitempty"`
+}
+
+// Subject is who a request is made for: the key rollouts hash and the
+// roles targeting matches.
+type Subject struct {
+	Key   string   `json:"key,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// ParseRoles splits a comma-separated role list such as "admin, staff",
+// dropping blanks.
+func ParseRoles(value string) []string {
+	var roles []string
+	for _, role := range strings.Split(value, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+type subjectKey struct{}
+
+type setKey struct{}
+
+func WithSubject(ctx context.Context, s Subject) context.Context {
+	return context.WithValue(ctx, subjectKey{}, s)
+}
+
+func SubjectFromContext(ctx context.Context) Subject {
+	s, _ := ctx.Value(subjectKey{}).(Subject)
+	return s
+}
+
+// WithSet makes set the one Enabled consults.
+func WithSet(ctx context.Context, set *Set) context.Context {
+	return context.WithValue(ctx, setKey{}, set)
+}
+
+// Enabled reports whether the named flag is on for the tenant and subject
+// of ctx. Without a Set in ctx only tenant overrides turn flags on.
+func Enabled(ctx context.Context, name string) bool {
+	set, _ := ctx.Value(setKey{}).(*Set)
+	return set.Evaluate(ctx, name).Enabled
+}
+
+// Evaluate decides the named flag for the tenant and subject of ctx.
+func (s *Set) Evaluate(ctx context.Context, name string) Evaluation {
+	flag, defined := s.lookup(name)
+	t := tenant.FromContext(ctx)
+
+	e := Evaluation{Name: name, Description: flag.Description}
+
+	if defined && !flag.Enabled {
+		e.Reason = ReasonDisabled
+		return e
+	}
+
+	if on, ok := t.Config.Features[name]; ok {
+		e.Enabled, e.Reason = on, ReasonTenant
+		return e
+	}
+
+	if !defined {
+		e.Reason = ReasonUnknown
+		return e
+	}
+
+	if len(flag.Tenants) > 0 && !slices.Contains(flag.Tenants, t.Id) {
+		e.Reason = ReasonNotTenant
+		return e
+	}
+
+	subject := SubjectFromContext(ctx)
+
+	if len(flag.Roles) > 0 && !slices.ContainsFunc(subject.Roles, func(role string) bool {
+		return slices.Contains(flag.Roles, role)
+	}) {
+		e.Reason = ReasonNotRole
+		return e
+	}
+
+	if flag.Rollout != nil && *flag.Rollout < 100 {
+		switch {
+		case subject.Key == "":
+			e.Reason = ReasonNoKey
+		case float64(bucket(name, subject.Key)) >= *flag.Rollout*rolloutResolution/100:
+			e.Reason = ReasonOutRollout
+		default:
+			e.Enabled, e.Reason = true, ReasonInRollout
+		}
+		return e
+	}
+
+	e.Enabled, e.Reason = true, ReasonEnabled
+	return e
+}
+
+// All evaluates every defined flag and every flag the tenant of ctx
+// overrides, by name.
+func (s *Set) All(ctx context.Context) []Evaluation {
+	names := s.Names()
+	for name := range tenant.FromContext(ctx).Config.Features {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	evaluations := make([]Evaluation, 0, len(names))
+	for _, name := range names {
+		evaluations = append(evaluations, s.Evaluate(ctx, name))
+	}
+	return evaluations
+}
+
+// bucket places key in one of rolloutResolution buckets. The flag name is
+// part of the hash so the same users are not always the first ones in.
+func bucket(name, key string) uint64 {
+	sum := sha256.Sum256([]byte(name + "\x00" + key))
+	return binary.BigEndian.Uint64(sum[:8]) % rolloutResolution
+}
@@ -0,0 +1,131 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+)
+
+func rollout(percent float64) *float64 {
+	return &percent
+}
+
+func newSet(flags map[string]Flag) *Set {
+	s := &Set{}
+	s.flags.Store(&flags)
+	return s
+}
+
+func request(tenantId string, features map[string]bool, subject Subject) context.Context {
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{Id: tenantId, Config: config.Tenant{Features: features}})
+	return WithSubject(ctx, subject)
+}
+
+func TestEvaluate(t *testing.T) {
+	s := newSet(map[string]Flag{
+		"off":      {Enabled: false},
+		"on":       {Enabled: true},
+		"north":    {Enabled: true, Tenants: []string{"north"}},
+		"staff":    {Enabled: true, Roles: []string{"staff", "admin"}},
+		"none":     {Enabled: true, Rollout: rollout(0)},
+		"half":     {Enabled: true, Rollout: rollout(50)},
+		"everyone": {Enabled: true, Rollout: rollout(100)},
+	})
+
+	staff := Subject{Key: "user-1", Roles: []string{"teacher", "staff"}}
+
+	tests := []struct {
+		name     string
+		flag     string
+		tenant   string
+		features map[string]bool
+		subject  Subject
+		enabled  bool
+		reason   string
+	}{
+		{"disabled", "off", "north", nil, staff, false, ReasonDisabled},
+		{"disabled beats the tenant", "off", "north", map[string]bool{"off": true}, staff, false, ReasonDisabled},
+		{"enabled", "on", "north", nil, Subject{}, true, ReasonEnabled},
+		{"tenant turns off", "on", "north", map[string]bool{"on": false}, staff, false, ReasonTenant},
+		{"tenant turns on an unknown flag", "beta", "north", map[string]bool{"beta": true}, Subject{}, true, ReasonTenant},
+		{"unknown", "beta", "north", nil, staff, false, ReasonUnknown},
+		{"targeted tenant", "north", "north", nil, Subject{}, true, ReasonEnabled},
+		{"other tenant", "north", "south", nil, Subject{}, false, ReasonNotTenant},
+		{"targeted role", "staff", "north", nil, staff, true, ReasonEnabled},
+		{"other role", "staff", "north", nil, Subject{Roles: []string{"student"}}, false, ReasonNotRole},
+		{"rollout without a key", "half", "north", nil, Subject{}, false, ReasonNoKey},
+		{"zero rollout", "none", "north", nil, staff, false, ReasonOutRollout},
+		{"full rollout needs no key", "everyone", "north", nil, Subject{}, true, ReasonEnabled},
+	}
+
+	for _, tt := range tests {
+		e := s.Evaluate(request(tt.tenant, tt.features, tt.subject), tt.flag)
+		if e.Enabled != tt.enabled || e.Reason != tt.reason {
+			t.Errorf("%s: evaluation = %+v, want enabled %v (%s)", tt.name, e, tt.enabled, tt.reason)
+		}
+	}
+}
+
+func TestRolloutIsStableAndProportional(t *testing.T) {
+	s := newSet(map[string]Flag{"quarter": {Enabled: true, Rollout: rollout(25)}})
+
+	in := 0
+	for i := range 4000 {
+		ctx := request("north", nil, Subject{Key: fmt.Sprintf("user-%d", i)})
+
+		first := s.Evaluate(ctx, "quarter")
+		if again := s.Evaluate(ctx, "quarter"); again != first {
+			t.Fatalf("user-%d evaluated %+v then %+v", i, first, again)
+		}
+		if first.Enabled {
+			in++
+		}
+	}
+
+	if in < 900 || in > 1100 {
+		t.Errorf("%d of 4000 users are in a 25%% rollout", in)
+	}
+}
+
+func TestEnabledReadsTheSetFromTheContext(t *testing.T) {
+	s := newSet(map[string]Flag{"on": {Enabled: true}})
+	ctx := request("north", map[string]bool{"beta": true}, Subject{})
+
+	if Enabled(ctx, "on") {
+		t.Error("a flag was on without a set in the context")
+	}
+	if !Enabled(ctx, "beta") {
+		t.Error("a tenant override was ignored without a set in the context")
+	}
+	if !Enabled(WithSet(ctx, s), "on") {
+		t.Error("a defined flag was off with the set in the context")
+	}
+}
+
+func TestAllIncludesTenantOverrides(t *testing.T) {
+	s := newSet(map[string]Flag{"b": {Enabled: true}, "a": {Enabled: false}})
+
+	all := s.All(request("north", map[string]bool{"c": true}, Subject{}))
+
+	var names []string
+	for _, e := range all {
+		names = append(names, e.Name)
+	}
+	if fmt.Sprint(names) != "[a b c]" {
+		t.Errorf("names = %v, want the flags and the override sorted", names)
+	}
+}
+
+func TestParseRoles(t *testing.T) {
+	if got := ParseRoles(" admin, ,staff,"); !slices.Equal(got, []string{"admin", "staff"}) {
+		t.Errorf("ParseRoles = %q, want admin and staff", got)
+	}
+
+	if got := ParseRoles(""); got != nil {
+		t.Errorf("ParseRoles of an empty list = %q, want none", got)
+	}
+}
@@ -0,0 +1,203 @@
+package flags
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+// file is the layout of the flags file:
+//
+//	flags:
+//	  new-search:
+//	    description: search students by course
+//	    enabled: true
+//	    rollout: 25
+//	    roles: [staff]
+type file struct {
+	Flags map[string]Flag `yaml:"flags" json:"flags"`
+}
+
+// Set holds the flags read from the flags file. A background loop reloads
+// them when the file changes; a file that no longer parses is reported and
+// the flags read before stay in effect.
+type Set struct {
+	cfg   config.Flags
+	flags atomic.Pointer[map[string]Flag]
+
+	modTime time.Time
+	size    int64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New reads the flags file, if one is configured.
+func New(cfg config.Flags) (*Set, error) {
+	s := &Set{cfg: cfg}
+	s.flags.Store(&map[string]Flag{})
+
+	if cfg.Path == "" {
+		return s, nil
+	}
+
+	if _, err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Load parses a flags file, as JSON when it ends in ".json" and as YAML
+// otherwise. Unknown fields are rejected so a typo does not silently leave
+// a flag at its default.
+func Load(path string) (map[string]Flag, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("flags: %w", err)
+	}
+
+	var f file
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&f)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(&f); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("flags: %s: %w", path, err)
+	}
+
+	var errs []error
+	for name, flag := range f.Flags {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, errors.New("flags: flag name is required"))
+		}
+		if flag.Rollout != nil && (*flag.Rollout < 0 || *flag.Rollout > 100) {
+			errs = append(errs, fmt.Errorf("flags: %s: rollout must be between 0 and 100", name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if f.Flags == nil {
+		f.Flags = map[string]Flag{}
+	}
+
+	return f.Flags, nil
+}
+
+// reload reads the file again when its modification time or size changed
+// and reports whether it did.
+func (s *Set) reload() (bool, error) {
+	info, err := os.Stat(s.cfg.Path)
+	if err != nil {
+		return false, fmt.Errorf("flags: %w", err)
+	}
+
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return false, nil
+	}
+
+	// A file that fails to parse is reported once, not on every check.
+	s.modTime, s.size = info.ModTime(), info.Size()
+
+	flags, err := Load(s.cfg.Path)
+	if err != nil {
+		return false, err
+	}
+
+	s.flags.Store(&flags)
+	return true, nil
+}
+
+func (s *Set) lookup(name string) (Flag, bool) {
+	if s == nil {
+		return Flag{}, false
+	}
+
+	flag, ok := (*s.flags.Load())[name]
+	return flag, ok
+}
+
+// Names lists the defined flags, sorted.
+func (s *Set) Names() []string {
+	if s == nil {
+		return nil
+	}
+
+	var names []string
+	for name := range *s.flags.Load() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start watches the flags file for changes until Stop.
+func (s *Set) Start() {
+	if s.cfg.Path == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx)
+	}()
+}
+
+func (s *Set) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Set) run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := s.reload()
+		if err != nil {
+			slog.Error("flags: reloading, keeping the current flags", slog.String("path", s.cfg.Path), slog.String("error", err.Error()))
+			continue
+		}
+
+		if reloaded {
+			slog.Info("flags reloaded", slog.String("path", s.cfg.Path), slog.Int("flags", len(*s.flags.Load())))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package flags
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name, content string
+		ok            bool
+	}{
+		{"flags.yaml", "flags:\n  search:\n    enabled: true\n    rollout: 25\n    roles: [staff]\n", true},
+		{"flags.json", `{"flags": {"search": {"enabled": true, "rollout": 25, "roles": ["staff"]}}}`, true},
+		{"empty.yaml", "", true},
+		{"typo.yaml", "flags:\n  search:\n    enabeld: true\n", false},
+		{"typo.json", `{"flags": {"search": {"enabeld": true}}}`, false},
+		{"rollout.yaml", "flags:\n  search:\n    enabled: true\n    rollout: 150\n", false},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		write(t, path, tt.content)
+
+		flags, err := Load(path)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: error = %v, want ok %v", tt.name, err, tt.ok)
+			continue
+		}
+
+		if tt.ok && strings.HasPrefix(tt.name, "flags.") {
+			search := flags["search"]
+			if !search.Enabled || search.Rollout == nil || *search.Rollout != 25 || len(search.Roles) != 1 {
+				t.Errorf("%s: search = %+v", tt.name, search)
+			}
+		}
+	}
+}
+
+func TestReloadKeepsFlagsWhenTheFileBreaks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	write(t, path, "flags:\n  search:\n    enabled: true\n")
+
+	s, err := New(config.Flags{Path: path, ReloadInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// Sizes differ between the versions, so no mtime tick is needed.
+	write(t, path, "flags:\n  search:\n    enabled: true\n  import:\n    enabled: true\n")
+
+	if reloaded, err := s.reload(); !reloaded || err != nil || len(s.Names()) != 2 {
+		t.Fatalf("reload = %v, %v, names %v, want both flags", reloaded, err, s.Names())
+	}
+
+	if reloaded, err := s.reload(); reloaded || err != nil {
+		t.Errorf("reload of an unchanged file = %v, %v", reloaded, err)
+	}
+
+	write(t, path, "flags: [")
+
+	if _, err := s.reload(); err == nil {
+		t.Error("reload of a broken file succeeded")
+	}
+	if len(s.Names()) != 2 {
+		t.Errorf("names after a broken reload = %v, want the previous flags", s.Names())
+	}
+}
+
+func TestNewWithoutAPath(t *testing.T) {
+	s, err := New(config.Flags{})
+	if err != nil || len(s.Names()) != 0 {
+		t.Fatalf("New = %v, %v, want an empty set", s.Names(), err)
+	}
+
+	s.Start()
+	s.Stop()
+
+	if _, err := New(config.Flags{Path: filepath.Join(t.TempDir(), "missing.yaml")}); err == nil {
+		t.Error("New with a missing file succeeded")
+	}
+}
@@ -0,0 +1,97 @@
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler serves GraphQL over POST (JSON body) and GET (query parameters,
+// queries only). Every request gets fresh batch loaders over store.
+func Handler(schema graphql.Schema, store storage.Storage, limits Limits) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+
+		if r.Method == http.MethodGet {
+			req.Query = r.URL.Query().Get("query")
+			req.OperationName = r.URL.Query().Get("operationName")
+
+			if raw := r.URL.Query().Get("variables"); raw != "" {
+				if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+					writeErrors(w, http.StatusBadRequest, fmt.Errorf("invalid variables: %w", err))
+					return
+				}
+			}
+		} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrors(w, response.DecodeStatus(err), err)
+			return
+		}
+
+		doc, err := parser.Parse(parser.ParseParams{
+			Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+		})
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := limits.check(doc, req.OperationName, req.Variables); err != nil {
+			writeErrors(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if r.Method == http.MethodGet && hasMutation(doc, req.OperationName) {
+			writeErrors(w, http.StatusMethodNotAllowed, fmt.Errorf("mutations require POST"))
+			return
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        withLoaders(r.Context(), storage.WithContext(r.Context(), store)),
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func hasMutation(doc *ast.Document, operationName string) bool {
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok || op.Operation != ast.OperationTypeMutation {
+			continue
+		}
+
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeErrors(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []gqlerrors.FormattedError{gqlerrors.FormatError(err)},
+	})
+}
@@ -0,0 +1,212 @@
+package gql_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/gql"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+)
+
+type result struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type studentsPage struct {
+	Edges []struct {
+		Cursor string `json:"cursor"`
+		Node   struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"node"`
+	} `json:"edges"`
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+}
+
+func newHandler(t *testing.T, limits gql.Limits) (http.HandlerFunc, *sqlite.Sqlite) {
+	t.Helper()
+
+	store, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { store.Db.Close() })
+
+	schema, err := gql.NewSchema(store)
+	if err != nil {
+		t.Fatalf("building schema: %v", err)
+	}
+
+	return gql.Handler(schema, store, limits), store
+}
+
+func post(t *testing.T, h http.HandlerFunc, query string, variables map[string]interface{}) (int, result) {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+	return rec.Code, decode(t, rec)
+}
+
+func get(t *testing.T, h http.HandlerFunc, query string) (int, result) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(query), nil))
+
+	return rec.Code, decode(t, rec)
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) result {
+	t.Helper()
+
+	var res result
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return res
+}
+
+func TestCreateAndQueryStudent(t *testing.T) {
+	h, _ := newHandler(t, gql.Limits{MaxDepth: 8, MaxComplexity: 5000})
+
+	status, res := post(t, h, `mutation($input: StudentInput!) { createStudent(input: $input) { id name status } }`,
+		map[string]interface{}{"input": map[string]interface{}{"name": "Ada", "email": "ada@example.edu", "age": 20}})
+	if status != http.StatusOK || len(res.Errors) != 0 {
+		t.Fatalf("createStudent = %d, %+v", status, res.Errors)
+	}
+
+	var created struct {
+		Id     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(res.Data["createStudent"], &created); err != nil || created.Name != "Ada" {
+		t.Fatalf("createStudent data = %s, %v", res.Data["createStudent"], err)
+	}
+
+	status, res = get(t, h, fmt.Sprintf(`{ student(id: %q) { name email } }`, created.Id))
+	if status != http.StatusOK || len(res.Errors) != 0 {
+		t.Fatalf("student = %d, %+v", status, res.Errors)
+	}
+	if !strings.Contains(string(res.Data["student"]), `"ada@example.edu"`) {
+		t.Errorf("student data = %s", res.Data["student"])
+	}
+
+	_, res = get(t, h, `{ student(id: "404") { name } }`)
+	if string(res.Data["student"]) != "null" {
+		t.Errorf("missing student = %s, want null", res.Data["student"])
+	}
+}
+
+func TestStudentsArePaginated(t *testing.T) {
+	h, store := newHandler(t, gql.Limits{MaxDepth: 8, MaxComplexity: 5000})
+
+	for i := range 3 {
+		if _, err := store.CreateStudent(fmt.Sprintf("Student %d", i), fmt.Sprintf("s%d@example.edu", i), 20); err != nil {
+			t.Fatalf("CreateStudent: %v", err)
+		}
+	}
+
+	const query = `query($after: String) { students(first: 2, after: $after) { edges { cursor node { id name } } pageInfo { hasNextPage endCursor } } }`
+
+	var first studentsPage
+	_, res := post(t, h, query, nil)
+	if err := json.Unmarshal(res.Data["students"], &first); err != nil {
+		t.Fatalf("students data = %s, %v (%+v)", res.Data["students"], err, res.Errors)
+	}
+	if len(first.Edges) != 2 || !first.PageInfo.HasNextPage {
+		t.Fatalf("first page = %+v, want two students and another page", first)
+	}
+
+	var second studentsPage
+	_, res = post(t, h, query, map[string]interface{}{"after": first.PageInfo.EndCursor})
+	if err := json.Unmarshal(res.Data["students"], &second); err != nil {
+		t.Fatalf("students data = %s, %v (%+v)", res.Data["students"], err, res.Errors)
+	}
+	if len(second.Edges) != 1 || second.PageInfo.HasNextPage || second.Edges[0].Node.Name != "Student 2" {
+		t.Errorf("second page = %+v, want the last student only", second)
+	}
+}
+
+func TestLimitsRejectExpensiveQueries(t *testing.T) {
+	h, _ := newHandler(t, gql.Limits{MaxDepth: 4, MaxComplexity: 50})
+
+	tests := []struct {
+		name, query string
+	}{
+		{"depth", `{ students { edges { node { enrollments { course { code } } } } } }`},
+		{"complexity", `{ students(first: 100) { edges { node { id name email } } } }`},
+	}
+
+	for _, tt := range tests {
+		status, res := post(t, h, tt.query, nil)
+		if status != http.StatusBadRequest || len(res.Errors) == 0 {
+			t.Errorf("%s: status = %d, errors = %+v, want 400", tt.name, status, res.Errors)
+		}
+	}
+
+	if status, res := post(t, h, `{ students(first: 2) { edges { node { id } } } }`, nil); status != http.StatusOK || len(res.Errors) != 0 {
+		t.Errorf("cheap query = %d, %+v, want 200", status, res.Errors)
+	}
+}
+
+func TestMutationsRequirePost(t *testing.T) {
+	h, store := newHandler(t, gql.Limits{MaxDepth: 8, MaxComplexity: 5000})
+
+	id, err := store.CreateStudent("Ada", "ada@example.edu", 20)
+	if err != nil {
+		t.Fatalf("CreateStudent: %v", err)
+	}
+
+	status, _ := get(t, h, fmt.Sprintf(`mutation { deleteStudent(id: "%d") }`, id))
+	if status != http.StatusMethodNotAllowed {
+		t.Errorf("mutation over GET = %d, want 405", status)
+	}
+
+	if status, _ := post(t, h, `{ students(`, nil); status != http.StatusBadRequest {
+		t.Errorf("unparsable query = %d, want 400", status)
+	}
+
+	if _, err := store.GetStudentById(id); err != nil {
+		t.Errorf("student after a rejected GET mutation: %v", err)
+	}
+}
+
+func TestLoaderBatchesQueuedKeys(t *testing.T) {
+	var calls [][]int
+	loader := gql.NewLoader(func(keys []int) (map[int]string, error) {
+		calls = append(calls, keys)
+		out := make(map[int]string, len(keys))
+		for _, k := range keys {
+			out[k] = fmt.Sprint(k * 10)
+		}
+		return out, nil
+	})
+
+	thunks := []func() (string, error){loader.Load(1), loader.Load(2), loader.Load(1)}
+	for i, want := range []string{"10", "20", "10"} {
+		if got, err := thunks[i](); got != want || err != nil {
+			t.Errorf("thunk %d = %q, %v, want %q", i, got, err, want)
+		}
+	}
+
+	if len(calls) != 1 || len(calls[0]) != 2 {
+		t.Errorf("fetch calls = %v, want one call for keys 1 and 2", calls)
+	}
+}
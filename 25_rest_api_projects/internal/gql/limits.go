@@ -0,0 +1,143 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listMultiplier is the assumed size of list fields that take no "first"
+// argument when estimating complexity.
+const listMultiplier = 10
+
+var unpaginatedLists = map[string]bool{
+	"enrollments": true,
+}
+
+// Limits caps how deep and how expensive a single query may be.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+type analysis struct {
+	doc       *ast.Document
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// check measures every operation in doc (or only operationName, when set)
+// and rejects it if it exceeds the limits. Complexity counts one per field,
+// multiplying a field's children by its "first" argument, or by
+// listMultiplier for unpaginated lists.
+func (l Limits) check(doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	a := analysis{
+		doc:       doc,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+	}
+
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			a.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if operationName != "" && (op.Name == nil || op.Name.Value != operationName) {
+			continue
+		}
+
+		depth, complexity := a.measure(op.SelectionSet, map[string]bool{})
+
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+		}
+
+		if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity)
+		}
+	}
+
+	return nil
+}
+
+func (a analysis) measure(set *ast.SelectionSet, visiting map[string]bool) (depth int, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			childDepth, childComplexity := a.measure(selection.SelectionSet, visiting)
+			d = childDepth + 1
+			c = 1 + a.multiplier(selection)*childComplexity
+
+		case *ast.InlineFragment:
+			d, c = a.measure(selection.SelectionSet, visiting)
+
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+
+			visiting[name] = true
+			d, c = a.measure(fragment.SelectionSet, visiting)
+			delete(visiting, name)
+		}
+
+		depth = max(depth, d)
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+func (a analysis) multiplier(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(value.Value); err == nil && n > 0 {
+				return n
+			}
+		case *ast.Variable:
+			switch n := a.variables[value.Name.Value].(type) {
+			case float64:
+				if n > 0 {
+					return int(n)
+				}
+			case int:
+				if n > 0 {
+					return n
+				}
+			}
+		}
+
+		return defaultPageSize
+	}
+
+	if field.Name.Value == "students" || field.Name.Value == "courses" {
+		return defaultPageSize
+	}
+
+	if unpaginatedLists[field.Name.Value] {
+		return listMultiplier
+	}
+
+	return 1
+}
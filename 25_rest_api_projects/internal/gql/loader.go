@@ -0,0 +1,183 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/faysal0x1/Go-Learn/internal/stats"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// Loader batches lookups made while one level of a query is resolved.
+// Load only queues the key and returns a thunk; the first thunk to run
+// fetches every queued key in one call, so a list of N students costs one
+// query per relation instead of N.
+type Loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	results map[K]V
+	errs    map[K]error
+}
+
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		queued:  make(map[K]bool),
+		results: make(map[K]V),
+		errs:    make(map[K]error),
+	}
+}
+
+func (l *Loader[K, V]) Load(key K) func() (V, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+
+			results, err := l.fetch(keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+					continue
+				}
+				l.results[k] = results[k]
+			}
+		}
+
+		return l.results[key], l.errs[key]
+	}
+}
+
+// loaders are created per request so cached results never outlive it.
+type loaders struct {
+	students             *Loader[int64, *types.Student]
+	courses              *Loader[int64, *types.Course]
+	enrollmentsByStudent *Loader[int64, []types.Enrollment]
+	enrollmentsByCourse  *Loader[int64, []types.Enrollment]
+	gpa                  *Loader[int64, float64]
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, store storage.Storage) context.Context {
+	l := &loaders{
+		students: NewLoader(func(ids []int64) (map[int64]*types.Student, error) {
+			students, err := store.GetStudentsByIds(ids)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[int64]*types.Student, len(students))
+			for i := range students {
+				result[students[i].Id] = &students[i]
+			}
+			return result, nil
+		}),
+		courses: NewLoader(func(ids []int64) (map[int64]*types.Course, error) {
+			courses, err := store.GetCoursesByIds(ids)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[int64]*types.Course, len(courses))
+			for i := range courses {
+				result[courses[i].Id] = &courses[i]
+			}
+			return result, nil
+		}),
+		enrollmentsByStudent: NewLoader(func(ids []int64) (map[int64][]types.Enrollment, error) {
+			enrollments, err := store.GetEnrollmentsByStudentIds(ids)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[int64][]types.Enrollment, len(ids))
+			for _, enrollment := range enrollments {
+				result[enrollment.StudentId] = append(result[enrollment.StudentId], enrollment)
+			}
+			return result, nil
+		}),
+		enrollmentsByCourse: NewLoader(func(ids []int64) (map[int64][]types.Enrollment, error) {
+			enrollments, err := store.GetEnrollmentsByCourseIds(ids)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[int64][]types.Enrollment, len(ids))
+			for _, enrollment := range enrollments {
+				result[enrollment.CourseId] = append(result[enrollment.CourseId], enrollment)
+			}
+			return result, nil
+		}),
+		gpa: NewLoader(func(ids []int64) (map[int64]float64, error) {
+			return loadGPAs(store, ids)
+		}),
+	}
+
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// loadGPAs computes credit-weighted GPAs for many students with two queries,
+// matching the REST /gpa endpoint.
+func loadGPAs(store storage.Storage, studentIds []int64) (map[int64]float64, error) {
+	enrollments, err := store.GetEnrollmentsByStudentIds(studentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var courseIds []int64
+	seen := map[int64]bool{}
+
+	for _, enrollment := range enrollments {
+		if enrollment.Grade != nil && !seen[enrollment.CourseId] {
+			seen[enrollment.CourseId] = true
+			courseIds = append(courseIds, enrollment.CourseId)
+		}
+	}
+
+	courses, err := store.GetCoursesByIds(courseIds)
+	if err != nil {
+		return nil, err
+	}
+
+	credits := make(map[int64]float64, len(courses))
+	for _, course := range courses {
+		credits[course.Id] = float64(course.Credits)
+	}
+
+	grades := map[int64][]float64{}
+	weights := map[int64][]float64{}
+
+	for _, enrollment := range enrollments {
+		if enrollment.Grade == nil {
+			continue
+		}
+		grades[enrollment.StudentId] = append(grades[enrollment.StudentId], *enrollment.Grade)
+		weights[enrollment.StudentId] = append(weights[enrollment.StudentId], credits[enrollment.CourseId])
+	}
+
+	result := make(map[int64]float64, len(studentIds))
+	for _, id := range studentIds {
+		result[id] = stats.WeightedAverage(grades[id], weights[id])
+	}
+
+	return result, nil
+}
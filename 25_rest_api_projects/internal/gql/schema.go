@@ -0,0 +1,530 @@
+package gql
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/stats"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// NewSchema builds the GraphQL schema over store. Relations are resolved
+// through the per-request loaders, so store must be the same one passed to
+// Handler.
+func NewSchema(store storage.Storage) (graphql.Schema, error) {
+	var studentType, courseType, enrollmentType *graphql.Object
+
+	courseStatsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CourseStats",
+		Fields: graphql.Fields{
+			"enrolled": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"graded":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"average":  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"min":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"max":      &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	studentType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Student",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":              &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":            &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"email":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"age":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"status":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"statusChangedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"enrollments": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(enrollmentType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						student := p.Source.(*types.Student)
+						thunk := loadersFrom(p.Context).enrollmentsByStudent.Load(student.Id)
+						return func() (interface{}, error) {
+							enrollments, err := thunk()
+							return enrollmentPointers(enrollments), err
+						}, nil
+					},
+				},
+				"gpa": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Float),
+					Description: "Credit-weighted average over graded enrollments.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						student := p.Source.(*types.Student)
+						thunk := loadersFrom(p.Context).gpa.Load(student.Id)
+						return func() (interface{}, error) {
+							return thunk()
+						}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	courseType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Course",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"code":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"title":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"credits": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"enrollments": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(enrollmentType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						course := p.Source.(*types.Course)
+						thunk := loadersFrom(p.Context).enrollmentsByCourse.Load(course.Id)
+						return func() (interface{}, error) {
+							enrollments, err := thunk()
+							return enrollmentPointers(enrollments), err
+						}, nil
+					},
+				},
+				"stats": &graphql.Field{
+					Type: graphql.NewNonNull(courseStatsType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						course := p.Source.(*types.Course)
+						thunk := loadersFrom(p.Context).enrollmentsByCourse.Load(course.Id)
+						return func() (interface{}, error) {
+							enrollments, err := thunk()
+							if err != nil {
+								return nil, err
+							}
+							return courseStats(course.Id, enrollments), nil
+						}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	enrollmentType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Enrollment",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"grade":      &graphql.Field{Type: graphql.Float},
+			"enrolledAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"student": &graphql.Field{
+				Type: studentType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					enrollment := p.Source.(*types.Enrollment)
+					thunk := loadersFrom(p.Context).students.Load(enrollment.StudentId)
+					return func() (interface{}, error) {
+						return nilIfMissing(thunk())
+					}, nil
+				},
+			},
+			"course": &graphql.Field{
+				Type: courseType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					enrollment := p.Source.(*types.Enrollment)
+					thunk := loadersFrom(p.Context).courses.Load(enrollment.CourseId)
+					return func() (interface{}, error) {
+						return nilIfMissing(thunk())
+					}, nil
+				},
+			},
+		},
+	})
+
+	pageArgs := graphql.FieldConfigArgument{
+		"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageSize},
+		"after": &graphql.ArgumentConfig{Type: graphql.String},
+	}
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"student": &graphql.Field{
+				Type: studentType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := argId(p, "id")
+					if err != nil {
+						return nil, err
+					}
+					return nilIfMissing(loadersFrom(p.Context).students.Load(id)())
+				},
+			},
+			"students": &graphql.Field{
+				Type: connectionType("Student", studentType),
+				Args: pageArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return paginate(p, func(after int64, limit int) ([]*types.Student, error) {
+						students, err := storage.WithContext(p.Context, store).GetStudentsPage(after, limit)
+						result := make([]*types.Student, len(students))
+						for i := range students {
+							result[i] = &students[i]
+						}
+						return result, err
+					}, func(s *types.Student) int64 { return s.Id })
+				},
+			},
+			"course": &graphql.Field{
+				Type: courseType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := argId(p, "id")
+					if err != nil {
+						return nil, err
+					}
+					return nilIfMissing(loadersFrom(p.Context).courses.Load(id)())
+				},
+			},
+			"courses": &graphql.Field{
+				Type: connectionType("Course", courseType),
+				Args: pageArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return paginate(p, func(after int64, limit int) ([]*types.Course, error) {
+						courses, err := storage.WithContext(p.Context, store).GetCoursesPage(after, limit)
+						result := make([]*types.Course, len(courses))
+						for i := range courses {
+							result[i] = &courses[i]
+						}
+						return result, err
+					}, func(c *types.Course) int64 { return c.Id })
+				},
+			},
+		},
+	})
+
+	studentInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "StudentInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"email": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"age":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	enrollmentArgs := graphql.FieldConfigArgument{
+		"courseId":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+		"studentId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createStudent": &graphql.Field{
+				Type: graphql.NewNonNull(studentType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(studentInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					student := studentFromInput(p.Args["input"])
+					if err := validate.Struct(student); err != nil {
+						return nil, err
+					}
+
+					id, err := storage.WithContext(p.Context, store).CreateStudent(student.Name, student.Email, student.Age)
+					if err != nil {
+						return nil, err
+					}
+
+					created, err := storage.WithContext(p.Context, store).GetStudentById(id)
+					return &created, err
+				},
+			},
+			"updateStudent": &graphql.Field{
+				Type: graphql.NewNonNull(studentType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(studentInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := argId(p, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					student := studentFromInput(p.Args["input"])
+					if err := validate.Struct(student); err != nil {
+						return nil, err
+					}
+
+					student.Id = id
+					if err := storage.WithContext(p.Context, store).UpdateStudent(student); err != nil {
+						return nil, err
+					}
+
+					updated, err := storage.WithContext(p.Context, store).GetStudentById(id)
+					return &updated, err
+				},
+			},
+			"deleteStudent": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := argId(p, "id")
+					if err != nil {
+						return nil, err
+					}
+					return true, storage.WithContext(p.Context, store).DeleteStudent(id)
+				},
+			},
+			"transitionStudent": &graphql.Field{
+				Type: graphql.NewNonNull(studentType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"event": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := argId(p, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					if _, err := storage.WithContext(p.Context, store).TransitionStudent(id, p.Args["event"].(string)); err != nil {
+						return nil, err
+					}
+
+					student, err := storage.WithContext(p.Context, store).GetStudentById(id)
+					return &student, err
+				},
+			},
+			"enroll": &graphql.Field{
+				Type: graphql.NewNonNull(enrollmentType),
+				Args: enrollmentArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					courseId, studentId, err := enrollmentIds(p)
+					if err != nil {
+						return nil, err
+					}
+
+					if _, err := storage.WithContext(p.Context, store).Enroll(studentId, courseId); err != nil {
+						return nil, err
+					}
+
+					return findEnrollment(storage.WithContext(p.Context, store), studentId, courseId)
+				},
+			},
+			"unenroll": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: enrollmentArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					courseId, studentId, err := enrollmentIds(p)
+					if err != nil {
+						return nil, err
+					}
+					return true, storage.WithContext(p.Context, store).Unenroll(studentId, courseId)
+				},
+			},
+			"recordGrade": &graphql.Field{
+				Type: graphql.NewNonNull(enrollmentType),
+				Args: graphql.FieldConfigArgument{
+					"courseId":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"studentId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"grade":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Float)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					courseId, studentId, err := enrollmentIds(p)
+					if err != nil {
+						return nil, err
+					}
+
+					grade := p.Args["grade"].(float64)
+					if err := validate.Struct(types.GradeRequest{Grade: &grade}); err != nil {
+						return nil, err
+					}
+
+					if err := storage.WithContext(p.Context, store).RecordGrade(studentId, courseId, grade); err != nil {
+						return nil, err
+					}
+
+					return findEnrollment(storage.WithContext(p.Context, store), studentId, courseId)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// connectionType builds a Relay-style connection for node.
+func connectionType(name string, node *graphql.Object) *graphql.Object {
+	edge := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Edge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(node)},
+		},
+	})
+
+	pageInfo := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"endCursor":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Connection",
+		Fields: graphql.Fields{
+			"edges":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edge)))},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfo)},
+		},
+	})
+}
+
+// paginate fetches one row past the page to learn whether another follows.
+func paginate[T any](p graphql.ResolveParams, fetch func(after int64, limit int) ([]T, error), id func(T) int64) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	if first <= 0 || first > maxPageSize {
+		return nil, fmt.Errorf("first must be between 1 and %d", maxPageSize)
+	}
+
+	var after int64
+	if cursor, ok := p.Args["after"].(string); ok && cursor != "" {
+		var err error
+		if after, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := fetch(after, first+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := len(items) > first
+	if hasNext {
+		items = items[:first]
+	}
+
+	edges := make([]map[string]interface{}, len(items))
+	var endCursor interface{}
+
+	for i, item := range items {
+		cursor := encodeCursor(id(item))
+		edges[i] = map[string]interface{}{"cursor": cursor, "node": item}
+		endCursor = cursor
+	}
+
+	return map[string]interface{}{
+		"edges": edges,
+		"pageInfo": map[string]interface{}{
+			"hasNextPage": hasNext,
+			"endCursor":   endCursor,
+		},
+	}, nil
+}
+
+func encodeCursor(id int64) string {
+	return base64.URLEncoding.EncodeToString([]byte("cursor:" + strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(string(raw), "cursor:"), 10, 64)
+	if err != nil || !strings.HasPrefix(string(raw), "cursor:") {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	return id, nil
+}
+
+func argId(p graphql.ResolveParams, name string) (int64, error) {
+	raw, _ := p.Args[name].(string)
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q", name, raw)
+	}
+
+	return id, nil
+}
+
+func enrollmentIds(p graphql.ResolveParams) (courseId int64, studentId int64, err error) {
+	if courseId, err = argId(p, "courseId"); err != nil {
+		return 0, 0, err
+	}
+	if studentId, err = argId(p, "studentId"); err != nil {
+		return 0, 0, err
+	}
+	return courseId, studentId, nil
+}
+
+func findEnrollment(store storage.Storage, studentId int64, courseId int64) (*types.Enrollment, error) {
+	enrollments, err := store.GetEnrollmentsByStudent(studentId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range enrollments {
+		if enrollments[i].CourseId == courseId {
+			return &enrollments[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("enrollment of student %d in course %d: %w", studentId, courseId, storage.ErrNotFound)
+}
+
+func studentFromInput(input interface{}) types.Student {
+	fields, _ := input.(map[string]interface{})
+
+	name, _ := fields["name"].(string)
+	email, _ := fields["email"].(string)
+	age, _ := fields["age"].(int)
+
+	return types.Student{Name: name, Email: email, Age: age}
+}
+
+func enrollmentPointers(enrollments []types.Enrollment) []*types.Enrollment {
+	result := make([]*types.Enrollment, len(enrollments))
+	for i := range enrollments {
+		result[i] = &enrollments[i]
+	}
+	return result
+}
+
+func courseStats(courseId int64, enrollments []types.Enrollment) types.CourseStats {
+	var grades []float64
+
+	for _, enrollment := range enrollments {
+		if enrollment.Grade != nil {
+			grades = append(grades, *enrollment.Grade)
+		}
+	}
+
+	summary := stats.Calculate(grades...)
+
+	return types.CourseStats{
+		CourseId: courseId,
+		Enrolled: len(enrollments),
+		Graded:   summary.Count,
+		Average:  summary.Average,
+		Min:      summary.Min,
+		Max:      summary.Max,
+	}
+}
+
+// nilIfMissing turns a missing record into a GraphQL null rather than an
+// empty object.
+func nilIfMissing[T any](value *T, err error) (interface{}, error) {
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,245 @@
+// Package batch applies many student changes in one request, so a sync
+// does not have to make a call per student.
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/http/handlers/student"
+	"github.com/faysal0x1/Go-Learn/internal/http/versioning"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+)
+
+const (
+	// ModeAtomic applies every operation or none: they run in one
+	// transaction that the first failure rolls back.
+	ModeAtomic = "atomic"
+	// ModeBestEffort applies each operation on its own and carries on
+	// past failures.
+	ModeBestEffort = "best_effort"
+)
+
+const (
+	MethodCreate = "create"
+	MethodUpdate = "update"
+	MethodDelete = "delete"
+)
+
+// Request is the body of POST /api/batch. Mode defaults to atomic.
+type Request struct {
+	Mode       string      `json:"mode"`
+	Operations []Operation `json:"operations"`
+}
+
+// Operation is one change: a create with the new student as Body, an
+// update of student Id with its new fields as Body, or a delete of
+// student Id.
+type Operation struct {
+	Method string        `json:"method"`
+	Id     int64         `json:"id,omitempty"`
+	Body   types.Student `json:"body"`
+}
+
+// Result is the status code and body the single-student endpoint would
+// have answered the operation with.
+type Result struct {
+	Status int `json:"status"`
+	Body   any `json:"body,omitempty"`
+}
+
+type Response struct {
+	Mode    string   `json:"mode"`
+	Applied int      `json:"applied"`
+	Failed  int      `json:"failed"`
+	Results []Result `json:"results"`
+}
+
+// errRollback aborts an atomic batch once an operation failed; the
+// failure itself is in the operation's result.
+var errRollback = errors.New("batch: operation failed")
+
+// Run applies the operations of a batch in order and answers with a
+// result for each. A best-effort batch always answers 200. An atomic one
+// answers 200 when everything was applied; otherwise nothing was, the
+// response has the status of the operation that failed, and the
+// operations after it are marked 424 as they never ran.
+func Run(s storage.Storage, cfg config.Batch) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+
+		if err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := req.validate(cfg.MaxOperations); err != nil {
+			status := http.StatusBadRequest
+			if len(req.Operations) > cfg.MaxOperations {
+				status = http.StatusRequestEntityTooLarge
+			}
+			response.WriteJson(w, status, response.GeneralError(err))
+			return
+		}
+
+		slog.InfoContext(r.Context(), "running batch", slog.String("mode", req.Mode), slog.Int("operations", len(req.Operations)))
+
+		v := versioning.FromContext(r.Context())
+		results := make([]Result, len(req.Operations))
+		status := http.StatusOK
+
+		if req.Mode == ModeBestEffort {
+			for i, op := range req.Operations {
+				results[i] = apply(s, op, v)
+			}
+		} else {
+			batcher, ok := s.(storage.Batcher)
+			if !ok {
+				response.StorageError(w, storage.ErrNoBatch)
+				return
+			}
+
+			failed := -1
+
+			err := batcher.Batch(func(tx storage.Storage) error {
+				for i, op := range req.Operations {
+					results[i] = apply(tx, op, v)
+					if results[i].Status >= http.StatusBadRequest {
+						failed = i
+						return errRollback
+					}
+				}
+				return nil
+			})
+
+			switch {
+			case failed >= 0:
+				status = results[failed].Status
+				rolledBack(results, failed)
+			case err != nil:
+				response.StorageError(w, err)
+				return
+			}
+		}
+
+		res := Response{Mode: req.Mode, Results: results}
+		for _, result := range results {
+			if result.Status < http.StatusBadRequest {
+				res.Applied++
+			} else {
+				res.Failed++
+			}
+		}
+
+		slog.InfoContext(r.Context(), "batch finished", slog.String("mode", req.Mode), slog.Int("applied", res.Applied), slog.Int("failed", res.Failed))
+
+		response.WriteJson(w, status, res)
+	}
+}
+
+// validate checks the shape of the batch before anything runs, so a
+// malformed one is rejected whole. Student fields are validated per
+// operation, as the single endpoints do.
+func (req *Request) validate(maxOperations int) error {
+	if req.Mode == "" {
+		req.Mode = ModeAtomic
+	}
+
+	if req.Mode != ModeAtomic && req.Mode != ModeBestEffort {
+		return fmt.Errorf("unknown mode %q, use %q or %q", req.Mode, ModeAtomic, ModeBestEffort)
+	}
+
+	if len(req.Operations) == 0 {
+		return errors.New("operations are required")
+	}
+
+	if len(req.Operations) > maxOperations {
+		return fmt.Errorf("batch has %d operations, at most %d are allowed", len(req.Operations), maxOperations)
+	}
+
+	for i, op := range req.Operations {
+		switch op.Method {
+		case MethodCreate:
+		case MethodUpdate, MethodDelete:
+			if op.Id <= 0 {
+				return fmt.Errorf("operation %d: %s needs a positive id", i, op.Method)
+			}
+		default:
+			return fmt.Errorf("operation %d: unknown method %q", i, op.Method)
+		}
+	}
+
+	return nil
+}
+
+// apply runs one operation against s. Updated students are shown as API
+// version v shows them.
+func apply(s storage.Storage, op Operation, v versioning.Version) Result {
+	if op.Method == MethodDelete {
+		if err := s.DeleteStudent(op.Id); err != nil {
+			return failure(response.StorageStatus(err), err)
+		}
+		return Result{Status: http.StatusNoContent}
+	}
+
+	if err := validate.Struct(op.Body); err != nil {
+		return failure(http.StatusBadRequest, err)
+	}
+
+	if op.Method == MethodCreate {
+		id, err := s.CreateStudent(op.Body.Name, op.Body.Email, op.Body.Age)
+		if err != nil {
+			return failure(response.StorageStatus(err), err)
+		}
+		return Result{Status: http.StatusCreated, Body: map[string]int64{"id": id}}
+	}
+
+	op.Body.Id = op.Id
+
+	if err := s.UpdateStudent(op.Body); err != nil {
+		return failure(response.StorageStatus(err), err)
+	}
+
+	updated, err := s.GetStudentById(op.Id)
+	if err != nil {
+		return failure(response.StorageStatus(err), err)
+	}
+
+	// Version 1 shows students as they are stored, see student.V1.
+	if v == versioning.V1 {
+		return Result{Status: http.StatusOK, Body: updated}
+	}
+	return Result{Status: http.StatusOK, Body: student.NewResource(updated)}
+}
+
+func failure(status int, err error) Result {
+	return Result{Status: status, Body: response.GeneralError(err)}
+}
+
+// rolledBack marks every operation of an atomic batch but the one that
+// failed as not applied: the ones before it were rolled back and the ones
+// after it never ran.
+func rolledBack(results []Result, failed int) {
+	for i := range results {
+		switch {
+		case i < failed:
+			results[i] = failure(http.StatusFailedDependency, fmt.Errorf("rolled back: operation %d failed", failed))
+		case i > failed:
+			results[i] = failure(http.StatusFailedDependency, fmt.Errorf("not applied: operation %d failed", failed))
+		}
+	}
+}
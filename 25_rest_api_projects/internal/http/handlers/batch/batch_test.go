@@ -0,0 +1,171 @@
+package batch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+)
+
+func newStore(t *testing.T) *sqlite.Sqlite {
+	t.Helper()
+
+	store, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Db.Close() })
+	return store
+}
+
+func run(t *testing.T, store *sqlite.Sqlite, body string) (int, Response) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	Run(store, config.Batch{MaxOperations: 3})(rec, httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(body)))
+
+	var res Response
+	if rec.Header().Get("Content-Type") == "application/json" && strings.HasPrefix(rec.Body.String(), `{"mode"`) {
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, res
+}
+
+func statuses(results []Result) []int {
+	out := make([]int, len(results))
+	for i, result := range results {
+		out[i] = result.Status
+	}
+	return out
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAtomicBatchRollsBack(t *testing.T) {
+	store := newStore(t)
+	id, err := store.CreateStudent("Ada", "ada@example.edu", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, res := run(t, store, `{"operations": [
+		{"method": "create", "body": {"name": "Grace", "email": "grace@example.edu", "age": 21}},
+		{"method": "delete", "id": `+strconv.FormatInt(id, 10)+`},
+		{"method": "delete", "id": 999}
+	]}`)
+
+	if status != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", status, http.StatusNotFound)
+	}
+	want := []int{http.StatusFailedDependency, http.StatusFailedDependency, http.StatusNotFound}
+	if res.Mode != ModeAtomic || res.Applied != 0 || res.Failed != 3 || !equal(statuses(res.Results), want) {
+		t.Errorf("response = %+v, want mode %s and statuses %v", res, ModeAtomic, want)
+	}
+
+	students, err := store.GetStudents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(students) != 1 || students[0].Id != id {
+		t.Errorf("students after rollback = %+v, want only Ada", students)
+	}
+}
+
+func TestAtomicBatchApplies(t *testing.T) {
+	store := newStore(t)
+	id, err := store.CreateStudent("Ada", "ada@example.edu", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, res := run(t, store, `{"mode": "atomic", "operations": [
+		{"method": "create", "body": {"name": "Grace", "email": "grace@example.edu", "age": 21}},
+		{"method": "update", "id": `+strconv.FormatInt(id, 10)+`, "body": {"name": "Ada Lovelace", "email": "ada@example.edu", "age": 21}}
+	]}`)
+
+	want := []int{http.StatusCreated, http.StatusOK}
+	if status != http.StatusOK || res.Applied != 2 || res.Failed != 0 || !equal(statuses(res.Results), want) {
+		t.Errorf("got %d %+v, want 200 and statuses %v", status, res, want)
+	}
+
+	updated, err := store.GetStudentById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Ada Lovelace" || updated.Age != 21 {
+		t.Errorf("updated student = %+v", updated)
+	}
+
+	students, _ := store.GetStudents()
+	if len(students) != 2 {
+		t.Errorf("got %d students, want 2", len(students))
+	}
+}
+
+func TestBestEffortBatchCarriesOn(t *testing.T) {
+	store := newStore(t)
+
+	status, res := run(t, store, `{"mode": "best_effort", "operations": [
+		{"method": "create", "body": {"name": "Grace", "email": "grace@example.edu", "age": 21}},
+		{"method": "create", "body": {"name": "", "email": "not an email", "age": 0}},
+		{"method": "delete", "id": 999}
+	]}`)
+
+	want := []int{http.StatusCreated, http.StatusBadRequest, http.StatusNotFound}
+	if status != http.StatusOK || res.Applied != 1 || res.Failed != 2 || !equal(statuses(res.Results), want) {
+		t.Errorf("got %d %+v, want 200 and statuses %v", status, res, want)
+	}
+
+	students, _ := store.GetStudents()
+	if len(students) != 1 || students[0].Name != "Grace" {
+		t.Errorf("students = %+v, want only Grace", students)
+	}
+}
+
+func TestMalformedBatchIsRejectedWhole(t *testing.T) {
+	store := newStore(t)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"empty body", "", http.StatusBadRequest},
+		{"not json", "{", http.StatusBadRequest},
+		{"unknown mode", `{"mode": "eventually", "operations": [{"method": "delete", "id": 1}]}`, http.StatusBadRequest},
+		{"no operations", `{"operations": []}`, http.StatusBadRequest},
+		{"unknown method", `{"operations": [{"method": "upsert", "id": 1}]}`, http.StatusBadRequest},
+		{"too many operations", `{"operations": [{"method": "delete", "id": 1}, {"method": "delete", "id": 2}, {"method": "delete", "id": 3}, {"method": "delete", "id": 4}]}`, http.StatusRequestEntityTooLarge},
+		{"missing id", `{"operations": [{"method": "create", "body": {"name": "Grace", "email": "grace@example.edu", "age": 21}}, {"method": "update"}]}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := run(t, store, tt.body); status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+		})
+	}
+
+	if students, _ := store.GetStudents(); len(students) != 0 {
+		t.Errorf("a rejected batch created %d students", len(students))
+	}
+}
@@ -0,0 +1,176 @@
+package course
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/report"
+	"github.com/faysal0x1/Go-Learn/internal/stats"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/request"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+)
+
+func New(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.InfoContext(r.Context(), "creating a course")
+
+		var course types.Course
+
+		err := json.NewDecoder(r.Body).Decode(&course)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+
+		if err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := validate.Struct(course); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		lastId, err := storage.CreateCourse(course.Code, course.Title, course.Credits)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "course created successfully", slog.Int64("id", lastId))
+
+		response.WriteJson(w, http.StatusCreated, map[string]int64{"id": lastId})
+	}
+}
+
+func GetById(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		course, err := storage.GetCourseById(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, course)
+	}
+}
+
+func GetList(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		courses, err := storage.GetCourses()
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, courses)
+	}
+}
+
+// Stats summarises the recorded grades of a course using stats.Calculate.
+func Stats(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if _, err := storage.GetCourseById(id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		enrollments, err := storage.GetEnrollmentsByCourse(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		var grades []float64
+
+		for _, enrollment := range enrollments {
+			if enrollment.Grade != nil {
+				grades = append(grades, *enrollment.Grade)
+			}
+		}
+
+		summary := stats.Calculate(grades...)
+
+		response.WriteJson(w, http.StatusOK, types.CourseStats{
+			CourseId: id,
+			Enrolled: len(enrollments),
+			Graded:   summary.Count,
+			Average:  summary.Average,
+			Min:      summary.Min,
+			Max:      summary.Max,
+		})
+	}
+}
+
+// Roster renders the students enrolled in the course as a PDF.
+func Roster(storage storage.Storage, cfg config.Reports) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		course, err := storage.GetCourseById(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		enrollments, err := storage.GetEnrollmentsByCourse(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		studentIds := make([]int64, len(enrollments))
+		for i, enrollment := range enrollments {
+			studentIds[i] = enrollment.StudentId
+		}
+
+		students, err := storage.GetStudentsByIds(studentIds)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		byId := make(map[int64]types.Student, len(students))
+		for _, student := range students {
+			byId[student.Id] = student
+		}
+
+		entries := make([]report.RosterEntry, 0, len(enrollments))
+		for _, enrollment := range enrollments {
+			entries = append(entries, report.RosterEntry{Student: byId[enrollment.StudentId], Enrollment: enrollment})
+		}
+
+		school := cmp.Or(tenant.FromContext(r.Context()).Config.Name, cfg.Institution)
+		doc := report.Roster(report.PageSize(cfg.PageSize), school, course, entries, time.Now())
+
+		response.WritePDF(w, fmt.Sprintf("roster-%s.pdf", course.Code), doc.Bytes())
+	}
+}
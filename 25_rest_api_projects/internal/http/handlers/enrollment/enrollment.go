@@ -0,0 +1,106 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/request"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+)
+
+// Enroll adds the student in the body to the course in the path.
+func Enroll(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		courseId, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		var req types.EnrollRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		lastId, err := storage.Enroll(req.StudentId, courseId)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "student enrolled", slog.Int64("student_id", req.StudentId), slog.Int64("course_id", courseId))
+
+		response.WriteJson(w, http.StatusCreated, map[string]int64{"id": lastId})
+	}
+}
+
+func Unenroll(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		courseId, studentId, err := pathIds(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if err := storage.Unenroll(studentId, courseId); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func RecordGrade(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		courseId, studentId, err := pathIds(r)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		var req types.GradeRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if err := storage.RecordGrade(studentId, courseId, *req.Grade); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, response.Response{Status: response.StatusOK})
+	}
+}
+
+func pathIds(r *http.Request) (courseId int64, studentId int64, err error) {
+	courseId, err = request.PathId(r, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	studentId, err = request.PathId(r, "student_id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return courseId, studentId, nil
+}
@@ -0,0 +1,51 @@
+package featureflags
+
+import (
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/flags"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+type evaluations struct {
+	Tenant  string             `json:"tenant"`
+	Subject flags.Subject      `json:"subject"`
+	Flags   []flags.Evaluation `json:"flags"`
+}
+
+// List evaluates every flag for the subject of the request, or for the one
+// given by the tenant, user and roles query parameters, so a rollout can be
+// checked for a particular school or user.
+func List(set *flags.Set, resolver *tenant.Resolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		query := r.URL.Query()
+
+		t := tenant.FromContext(ctx)
+		if id := query.Get("tenant"); id != "" {
+			var err error
+			t, err = resolver.Lookup(id)
+			if err != nil {
+				response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+				return
+			}
+		}
+		ctx = tenant.WithTenant(ctx, t)
+
+		subject := flags.SubjectFromContext(ctx)
+		if query.Has("user") {
+			subject.Key = query.Get("user")
+		}
+		if query.Has("roles") {
+			subject.Roles = flags.ParseRoles(query.Get("roles"))
+		}
+		ctx = flags.WithSubject(ctx, subject)
+
+		response.WriteJson(w, http.StatusOK, evaluations{
+			Tenant:  t.Id,
+			Subject: subject,
+			Flags:   set.All(ctx),
+		})
+	}
+}
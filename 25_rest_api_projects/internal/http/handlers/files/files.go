@@ -0,0 +1,330 @@
+// Package files serves student photo and document uploads.
+package files
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/faysal0x1/Go-Learn/internal/blob"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/request"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+const (
+	KindPhoto    = "photo"
+	KindDocument = "document"
+)
+
+type Store interface {
+	GetStudentById(id int64) (types.Student, error)
+	CreateStudentFile(file types.StudentFile) (int64, error)
+	GetStudentFile(studentId int64, id int64) (types.StudentFile, error)
+	GetStudentFiles(studentId int64) ([]types.StudentFile, error)
+	DeleteStudentFile(studentId int64, id int64) error
+	BlobReferenced(hash string) (bool, error)
+}
+
+// Upload accepts a multipart/form-data body with a "file" part and an
+// optional "kind" field sent before it. The file is streamed straight into
+// the blob store; its type is sniffed from the content, not taken from the
+// client.
+func Upload(store Store, blobs *blob.Store, cfg config.Uploads) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		studentId, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if _, err := store.GetStudentById(studentId); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		var kind, filename string
+		var stored *blob.Blob
+
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+				return
+			}
+
+			switch part.FormName() {
+			case "kind":
+				value, err := io.ReadAll(io.LimitReader(part, 64))
+				if err != nil {
+					response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+					return
+				}
+				kind = strings.TrimSpace(string(value))
+			case "file":
+				if stored != nil {
+					response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("only one file per upload")))
+					return
+				}
+
+				filename = cleanFilename(part.FileName())
+
+				b, err := blobs.Put(part, cfg.MaxSize, cfg.AllowedTypes)
+				if err != nil {
+					response.WriteJson(w, uploadStatus(err), response.GeneralError(err))
+					return
+				}
+				stored = &b
+			}
+
+			part.Close()
+		}
+
+		if stored == nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(errors.New("field file is required")))
+			return
+		}
+
+		isImage := strings.HasPrefix(stored.ContentType, "image/")
+
+		if kind == "" {
+			kind = KindDocument
+			if isImage {
+				kind = KindPhoto
+			}
+		}
+
+		if kind != KindPhoto && kind != KindDocument {
+			release(store, blobs, stored.Hash)
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("kind must be %q or %q", KindPhoto, KindDocument)))
+			return
+		}
+
+		if kind == KindPhoto && !isImage {
+			release(store, blobs, stored.Hash)
+			response.WriteJson(w, http.StatusUnsupportedMediaType, response.GeneralError(fmt.Errorf("a photo must be an image, got %s", stored.ContentType)))
+			return
+		}
+
+		file := types.StudentFile{
+			StudentId:   studentId,
+			Kind:        kind,
+			Filename:    filename,
+			ContentType: stored.ContentType,
+			Size:        stored.Size,
+			SHA256:      stored.Hash,
+		}
+
+		if isImage {
+			thumbnail, err := blobs.Thumbnail(stored.Hash, cfg.ThumbnailSize)
+			if err != nil {
+				slog.WarnContext(r.Context(), "no thumbnail for upload", slog.String("sha256", stored.Hash), slog.String("error", err.Error()))
+			} else {
+				file.ThumbnailSHA256 = thumbnail.Hash
+			}
+		}
+
+		file.Id, err = store.CreateStudentFile(file)
+		if err != nil {
+			release(store, blobs, file.SHA256, file.ThumbnailSHA256)
+			response.StorageError(w, err)
+			return
+		}
+
+		created, err := store.GetStudentFile(studentId, file.Id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "student file uploaded", slog.Int64("student_id", studentId), slog.Int64("id", created.Id), slog.Int64("size", created.Size))
+
+		response.WriteJson(w, http.StatusCreated, created)
+	}
+}
+
+func GetList(store Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		studentId, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if _, err := store.GetStudentById(studentId); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		files, err := store.GetStudentFiles(studentId)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, files)
+	}
+}
+
+// Download serves the file content with support for Range, If-Range and
+// conditional requests on the content hash.
+func Download(store Store, blobs *blob.Store) http.HandlerFunc {
+	return serve(store, blobs, func(file types.StudentFile) (string, string, string) {
+		return file.SHA256, file.ContentType, "attachment"
+	})
+}
+
+// Thumbnail serves the PNG thumbnail of an image upload.
+func Thumbnail(store Store, blobs *blob.Store) http.HandlerFunc {
+	return serve(store, blobs, func(file types.StudentFile) (string, string, string) {
+		return file.ThumbnailSHA256, "image/png", "inline"
+	})
+}
+
+func serve(store Store, blobs *blob.Store, pick func(types.StudentFile) (hash, contentType, disposition string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, ok := lookup(w, r, store)
+		if !ok {
+			return
+		}
+
+		hash, contentType, disposition := pick(file)
+		if hash == "" {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(fmt.Errorf("file %d has no thumbnail", file.Id)))
+			return
+		}
+
+		content, err := blobs.Open(hash)
+		if errors.Is(err, blob.ErrNotFound) {
+			response.WriteJson(w, http.StatusNotFound, response.GeneralError(err))
+			return
+		}
+		if err != nil {
+			response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+			return
+		}
+		defer content.Close()
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Filename}))
+		w.Header().Set("ETag", `"`+hash+`"`)
+		w.Header().Set("Cache-Control", "private, max-age=86400")
+
+		http.ServeContent(w, r, file.Filename, file.CreatedAt, content)
+	}
+}
+
+// Delete removes the file and any blobs no other file shares.
+func Delete(store Store, blobs *blob.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, ok := lookup(w, r, store)
+		if !ok {
+			return
+		}
+
+		if err := store.DeleteStudentFile(file.StudentId, file.Id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		release(store, blobs, file.SHA256, file.ThumbnailSHA256)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func lookup(w http.ResponseWriter, r *http.Request, store Store) (types.StudentFile, bool) {
+	studentId, err := request.PathId(r, "id")
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return types.StudentFile{}, false
+	}
+
+	id, err := request.PathId(r, "file_id")
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+		return types.StudentFile{}, false
+	}
+
+	file, err := store.GetStudentFile(studentId, id)
+	if err != nil {
+		response.StorageError(w, err)
+		return types.StudentFile{}, false
+	}
+
+	return file, true
+}
+
+// release deletes the blobs among hashes that no file references. Blobs
+// written within blob.Grace are kept, since a concurrent upload may have
+// just reused them; the sweep removes them later. A failure only leaves an
+// orphaned blob behind, so it is logged, not returned.
+func release(store Store, blobs *blob.Store, hashes ...string) {
+	cutoff := time.Now().Add(-blob.Grace)
+
+	for _, hash := range hashes {
+		if hash == "" {
+			continue
+		}
+
+		referenced, err := store.BlobReferenced(hash)
+		if err == nil && !referenced {
+			err = blobs.Delete(hash, cutoff)
+		}
+
+		if err != nil {
+			slog.Warn("releasing blob", slog.String("sha256", hash), slog.String("error", err.Error()))
+		}
+	}
+}
+
+func uploadStatus(err error) int {
+	switch {
+	case errors.Is(err, blob.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, blob.ErrTypeDenied):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, blob.ErrEmpty):
+		return http.StatusBadRequest
+	}
+
+	return response.DecodeStatus(err)
+}
+
+// cleanFilename keeps only the base name without control characters; it is
+// echoed back in Content-Disposition, never used as a path.
+func cleanFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+
+	if name == "." || name == "/" || name == "" {
+		return "upload"
+	}
+
+	if len(name) > 255 {
+		name = name[:255]
+	}
+
+	return name
+}
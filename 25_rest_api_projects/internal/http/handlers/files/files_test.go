@@ -0,0 +1,201 @@
+package files
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/blob"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+const pdf = "%PDF-1.4\n1 0 obj << >> endobj\n%%EOF\n"
+
+type server struct {
+	mux     *http.ServeMux
+	store   *sqlite.Sqlite
+	blobDir string
+	student int64
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	store, err := sqlite.New(&config.Config{StoragePath: filepath.Join(dir, "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Db.Close() })
+
+	blobDir := filepath.Join(dir, "blobs")
+	blobs, err := blob.NewStore(blobDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Uploads{MaxSize: 1 << 20, AllowedTypes: []string{"application/pdf", "image/png"}, ThumbnailSize: 16}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/students/{id}/files", Upload(store, blobs, cfg))
+	mux.HandleFunc("GET /api/students/{id}/files/{file_id}", Download(store, blobs))
+	mux.HandleFunc("DELETE /api/students/{id}/files/{file_id}", Delete(store, blobs))
+
+	student, err := store.CreateStudent("Ada", "ada@example.edu", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &server{mux: mux, store: store, blobDir: blobDir, student: student}
+}
+
+func (s *server) do(req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *server) upload(t *testing.T, kind, content string) (*httptest.ResponseRecorder, types.StudentFile) {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if kind != "" {
+		form.WriteField("kind", kind)
+	}
+	part, _ := form.CreateFormFile("file", "../transcript.pdf")
+	part.Write([]byte(content))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/students/%d/files", s.student), &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	rec := s.do(req)
+
+	var file types.StudentFile
+	if rec.Code == http.StatusCreated {
+		if err := json.Unmarshal(rec.Body.Bytes(), &file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec, file
+}
+
+func (s *server) path(file types.StudentFile) string {
+	return fmt.Sprintf("/api/students/%d/files/%d", s.student, file.Id)
+}
+
+// blobExists reports whether the blob is on disk, first making it look
+// last written age ago when age is set.
+func (s *server) blobExists(t *testing.T, hash string, age time.Duration) bool {
+	t.Helper()
+
+	path := filepath.Join(s.blobDir, hash[:2], hash[2:4], hash)
+	if age > 0 {
+		then := time.Now().Add(-age)
+		if err := os.Chtimes(path, then, then); err != nil {
+			return false
+		}
+	}
+
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestUploadAndDownload(t *testing.T) {
+	s := newServer(t)
+
+	rec, file := s.upload(t, "", pdf)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload = %d %s", rec.Code, rec.Body)
+	}
+
+	if file.Kind != KindDocument || file.Filename != "transcript.pdf" || file.ContentType != "application/pdf" {
+		t.Errorf("file = %+v", file)
+	}
+
+	rec = s.do(httptest.NewRequest(http.MethodGet, s.path(file), nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != pdf {
+		t.Fatalf("download = %d %q", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("ETag") != `"`+file.SHA256+`"` || !strings.Contains(rec.Header().Get("Content-Disposition"), "transcript.pdf") {
+		t.Errorf("download headers = %v", rec.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, s.path(file), nil)
+	req.Header.Set("Range", "bytes=0-3")
+	if rec := s.do(req); rec.Code != http.StatusPartialContent || rec.Body.String() != "%PDF" {
+		t.Errorf("ranged download = %d %q", rec.Code, rec.Body)
+	}
+}
+
+func TestUploadRejectsAMismatchedKind(t *testing.T) {
+	s := newServer(t)
+
+	if rec, _ := s.upload(t, KindPhoto, pdf); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("pdf as photo = %d, want 415", rec.Code)
+	}
+	if rec, _ := s.upload(t, "avatar", pdf); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown kind = %d, want 400", rec.Code)
+	}
+	if rec, _ := s.upload(t, "", "plain text"); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("disallowed type = %d, want 415", rec.Code)
+	}
+}
+
+func TestDownloadOfAMissingBlobIsNotFound(t *testing.T) {
+	s := newServer(t)
+
+	id, err := s.store.CreateStudentFile(types.StudentFile{
+		StudentId: s.student, Kind: KindDocument, Filename: "lost.pdf", ContentType: "application/pdf", Size: 1, SHA256: strings.Repeat("ab", 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := s.do(httptest.NewRequest(http.MethodGet, s.path(types.StudentFile{Id: id}), nil)); rec.Code != http.StatusNotFound {
+		t.Errorf("download = %d, want 404", rec.Code)
+	}
+}
+
+func TestDeleteReleasesOnlyUnsharedOldBlobs(t *testing.T) {
+	s := newServer(t)
+
+	_, first := s.upload(t, "", pdf)
+	_, second := s.upload(t, "", pdf)
+	if first.SHA256 != second.SHA256 {
+		t.Fatalf("identical uploads got hashes %s and %s", first.SHA256, second.SHA256)
+	}
+
+	if rec := s.do(httptest.NewRequest(http.MethodDelete, s.path(first), nil)); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete = %d", rec.Code)
+	}
+	if !s.blobExists(t, second.SHA256, 2*blob.Grace) {
+		t.Fatal("deleting one of two files sharing a blob removed it")
+	}
+
+	if rec := s.do(httptest.NewRequest(http.MethodDelete, s.path(second), nil)); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete = %d", rec.Code)
+	}
+	if s.blobExists(t, second.SHA256, 0) {
+		t.Error("deleting the last file kept its old blob")
+	}
+
+	_, fresh := s.upload(t, "", pdf)
+	s.do(httptest.NewRequest(http.MethodDelete, s.path(fresh), nil))
+
+	if !s.blobExists(t, fresh.SHA256, 0) {
+		t.Error("a blob within its grace period was released")
+	}
+}
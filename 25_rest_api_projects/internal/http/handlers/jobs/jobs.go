@@ -0,0 +1,15 @@
+package jobs
+
+import (
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/scheduler"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// List reports every scheduled job with its next and latest run.
+func List(s *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, s.Status())
+	}
+}
@@ -0,0 +1,16 @@
+package mailsink
+
+import (
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/mail"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// List returns the messages the in-process mail sink captured, newest
+// first.
+func List(sink *mail.Sink) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.WriteJson(w, http.StatusOK, sink.Messages())
+	}
+}
@@ -0,0 +1,329 @@
+package roster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 64 << 10
+	sendBuffer     = 64
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// incoming is a client command. Ref is echoed back on the matching ack or
+// error so clients can correlate replies.
+type incoming struct {
+	Type      string         `json:"type"`
+	Ref       string         `json:"ref,omitempty"`
+	Roster    string         `json:"roster,omitempty"`
+	Id        int64          `json:"id,omitempty"`
+	CourseId  int64          `json:"course_id,omitempty"`
+	StudentId int64          `json:"student_id,omitempty"`
+	Student   *types.Student `json:"student,omitempty"`
+	Grade     *float64       `json:"grade,omitempty"`
+	Event     string         `json:"event,omitempty"`
+}
+
+type outgoing struct {
+	Type   string        `json:"type"`
+	Ref    string        `json:"ref,omitempty"`
+	Roster string        `json:"roster,omitempty"`
+	Status int           `json:"status,omitempty"`
+	Error  string        `json:"error,omitempty"`
+	Data   any           `json:"data,omitempty"`
+	Event  *events.Event `json:"event,omitempty"`
+}
+
+// commandError carries the HTTP status the equivalent REST call would have
+// returned.
+type commandError struct {
+	status int
+	err    error
+}
+
+func (e *commandError) Error() string {
+	return e.err.Error()
+}
+
+type conn struct {
+	hub   *Hub
+	store storage.Storage
+	ws    *websocket.Conn
+	out   chan outgoing
+	done  chan struct{}
+	once  sync.Once
+
+	// closeCode and closeReason are what writeLoop sends in the close
+	// frame once done is closed.
+	closeCode   int
+	closeReason string
+	writeDone   chan struct{}
+}
+
+// Serve upgrades the request to a WebSocket. Clients subscribe to rosters
+// to receive change notifications and send edit commands that are validated
+// and stored exactly like their REST counterparts.
+func Serve(hub *Hub, store storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade has already replied with an error status.
+			return
+		}
+
+		c := newConn(hub, store, ws)
+
+		if !hub.register(c) {
+			c.shutdown()
+			c.writeLoop()
+			return
+		}
+
+		go c.writeLoop()
+		c.readLoop()
+	}
+}
+
+func newConn(hub *Hub, store storage.Storage, ws *websocket.Conn) *conn {
+	return &conn{
+		hub:       hub,
+		store:     store,
+		ws:        ws,
+		out:       make(chan outgoing, sendBuffer),
+		done:      make(chan struct{}),
+		writeDone: make(chan struct{}),
+	}
+}
+
+// send queues msg without blocking. A client that lets its queue fill up is
+// disconnected rather than silently missing updates.
+func (c *conn) send(msg outgoing) {
+	select {
+	case <-c.done:
+	case c.out <- msg:
+	default:
+		slog.Warn("roster: closing slow websocket client", slog.String("remote", c.ws.RemoteAddr().String()))
+		c.closeWith(websocket.ClosePolicyViolation, "send buffer full")
+	}
+}
+
+// closeWith asks writeLoop to send a close frame and end the connection.
+// It never blocks, so the hub can call it while holding its lock: a slow
+// client only ever holds up its own write loop.
+func (c *conn) closeWith(code int, reason string) {
+	c.once.Do(func() {
+		c.closeCode, c.closeReason = code, reason
+		close(c.done)
+	})
+}
+
+func (c *conn) shutdown() {
+	c.closeWith(websocket.CloseGoingAway, "server shutting down")
+}
+
+func (c *conn) readLoop() {
+	defer func() {
+		c.hub.unregister(c)
+		c.closeWith(websocket.CloseNormalClosure, "")
+		<-c.writeDone
+	}()
+
+	c.ws.SetReadLimit(maxMessageSize)
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var in incoming
+		if err := json.Unmarshal(data, &in); err != nil {
+			c.send(outgoing{Type: "error", Status: http.StatusBadRequest, Error: err.Error()})
+			continue
+		}
+
+		result, err := c.handle(in)
+		if err != nil {
+			status := http.StatusInternalServerError
+
+			var cmdErr *commandError
+			if errors.As(err, &cmdErr) {
+				status = cmdErr.status
+			}
+
+			c.send(outgoing{Type: "error", Ref: in.Ref, Status: status, Error: err.Error()})
+			continue
+		}
+
+		c.send(outgoing{Type: "ack", Ref: in.Ref, Roster: in.Roster, Data: result})
+	}
+}
+
+// writeLoop is the only writer of data frames. It closes the connection
+// when it returns, after sending the close frame if closeWith asked for one.
+func (c *conn) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+		close(c.writeDone)
+	}()
+
+	for {
+		// A connection being closed gets its close frame next, not the
+		// rest of a queue it could not keep up with.
+		select {
+		case <-c.done:
+			c.writeClose()
+			return
+		default:
+		}
+
+		select {
+		case <-c.done:
+			c.writeClose()
+			return
+		case msg := <-c.out:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *conn) writeClose() {
+	c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, c.closeReason), time.Now().Add(writeWait))
+}
+
+func (c *conn) handle(in incoming) (any, error) {
+	switch in.Type {
+	case "subscribe":
+		roster, err := ParseRoster(in.Roster)
+		if err != nil {
+			return nil, badRequest(err)
+		}
+		c.hub.subscribe(c, roster)
+		return nil, nil
+
+	case "unsubscribe":
+		c.hub.unsubscribe(c, in.Roster)
+		return nil, nil
+
+	case "create_student":
+		if err := validateStudent(in.Student); err != nil {
+			return nil, err
+		}
+		id, err := c.store.CreateStudent(in.Student.Name, in.Student.Email, in.Student.Age)
+		if err != nil {
+			return nil, storageError(err)
+		}
+		return map[string]int64{"id": id}, nil
+
+	case "update_student":
+		if err := validateStudent(in.Student); err != nil {
+			return nil, err
+		}
+		in.Student.Id = in.Id
+		if err := c.store.UpdateStudent(*in.Student); err != nil {
+			return nil, storageError(err)
+		}
+		student, err := c.store.GetStudentById(in.Id)
+		if err != nil {
+			return nil, storageError(err)
+		}
+		return student, nil
+
+	case "delete_student":
+		if err := c.store.DeleteStudent(in.Id); err != nil {
+			return nil, storageError(err)
+		}
+		return nil, nil
+
+	case "transition":
+		transition, err := c.store.TransitionStudent(in.Id, in.Event)
+		switch {
+		case errors.Is(err, lifecycle.ErrUnknownEvent):
+			return nil, badRequest(err)
+		case errors.Is(err, lifecycle.ErrIllegalTransition):
+			return nil, &commandError{status: http.StatusConflict, err: err}
+		case err != nil:
+			return nil, storageError(err)
+		}
+		return transition, nil
+
+	case "enroll":
+		if err := validate.Struct(types.EnrollRequest{StudentId: in.StudentId}); err != nil {
+			return nil, badRequest(err)
+		}
+		id, err := c.store.Enroll(in.StudentId, in.CourseId)
+		if err != nil {
+			return nil, storageError(err)
+		}
+		return map[string]int64{"id": id}, nil
+
+	case "unenroll":
+		if err := c.store.Unenroll(in.StudentId, in.CourseId); err != nil {
+			return nil, storageError(err)
+		}
+		return nil, nil
+
+	case "grade":
+		if err := validate.Struct(types.GradeRequest{Grade: in.Grade}); err != nil {
+			return nil, badRequest(err)
+		}
+		if err := c.store.RecordGrade(in.StudentId, in.CourseId, *in.Grade); err != nil {
+			return nil, storageError(err)
+		}
+		return nil, nil
+	}
+
+	return nil, badRequest(fmt.Errorf("unknown command type: %q", in.Type))
+}
+
+func validateStudent(student *types.Student) error {
+	if student == nil {
+		return badRequest(fmt.Errorf("field student is required"))
+	}
+	if err := validate.Struct(*student); err != nil {
+		return badRequest(err)
+	}
+	return nil
+}
+
+func badRequest(err error) error {
+	return &commandError{status: http.StatusBadRequest, err: err}
+}
+
+func storageError(err error) error {
+	return &commandError{status: response.StorageStatus(err), err: err}
+}
@@ -0,0 +1,128 @@
+package roster
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/faysal0x1/Go-Learn/internal/events"
+)
+
+// StudentsRoster is the roster of every student. Course rosters are named
+// "courses/<id>" and receive that course's enrollment events.
+const StudentsRoster = "students"
+
+// Hub routes published events to the connections subscribed to the roster
+// they affect.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*conn]struct{}
+	conns       map[*conn]struct{}
+	closed      bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[*conn]struct{}),
+		conns:       make(map[*conn]struct{}),
+	}
+}
+
+// ParseRoster validates a roster name.
+func ParseRoster(name string) (string, error) {
+	if name == StudentsRoster {
+		return name, nil
+	}
+
+	if raw, ok := strings.CutPrefix(name, "courses/"); ok {
+		if id, err := strconv.ParseInt(raw, 10, 64); err == nil && id > 0 {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown roster: %q", name)
+}
+
+func rosterFor(event events.Event) string {
+	if event.CourseId != 0 {
+		return "courses/" + strconv.FormatInt(event.CourseId, 10)
+	}
+	return StudentsRoster
+}
+
+// Publish implements events.Publisher. It never blocks: a connection that
+// cannot keep up is marked for closing by its own send, and its write loop
+// sends the close frame.
+func (h *Hub) Publish(event events.Event) {
+	roster := rosterFor(event)
+	msg := outgoing{Type: "event", Roster: roster, Event: &event}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.subscribers[roster] {
+		c.send(msg)
+	}
+}
+
+func (h *Hub) register(c *conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+
+	h.conns[c] = struct{}{}
+	return true
+}
+
+func (h *Hub) unregister(c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.conns, c)
+	for roster, subs := range h.subscribers {
+		delete(subs, c)
+		if len(subs) == 0 {
+			delete(h.subscribers, roster)
+		}
+	}
+}
+
+func (h *Hub) subscribe(c *conn, roster string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscribers[roster] == nil {
+		h.subscribers[roster] = make(map[*conn]struct{})
+	}
+	h.subscribers[roster][c] = struct{}{}
+}
+
+func (h *Hub) unsubscribe(c *conn, roster string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers[roster], c)
+	if len(h.subscribers[roster]) == 0 {
+		delete(h.subscribers, roster)
+	}
+}
+
+// Close has every connection send a going-away close frame. The server does
+// not track hijacked connections, so this is registered for shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	conns := make([]*conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range conns {
+		c.shutdown()
+	}
+}
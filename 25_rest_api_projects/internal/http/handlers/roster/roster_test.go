@@ -0,0 +1,163 @@
+package roster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/gorilla/websocket"
+)
+
+func newTestServer(t *testing.T, hub *Hub) string {
+	t.Helper()
+
+	store, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { store.Db.Close() })
+
+	server := httptest.NewServer(Serve(hub, store))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return ws
+}
+
+func roundTrip(t *testing.T, ws *websocket.Conn, in incoming) outgoing {
+	t.Helper()
+
+	if err := ws.WriteJSON(in); err != nil {
+		t.Fatalf("sending %s: %v", in.Type, err)
+	}
+
+	var out outgoing
+	if err := ws.ReadJSON(&out); err != nil {
+		t.Fatalf("reading reply to %s: %v", in.Type, err)
+	}
+	return out
+}
+
+func TestCommandsAreAckedAndBroadcast(t *testing.T) {
+	hub := NewHub()
+	ws := dial(t, newTestServer(t, hub))
+
+	if out := roundTrip(t, ws, incoming{Type: "subscribe", Ref: "1", Roster: StudentsRoster}); out.Type != "ack" || out.Ref != "1" {
+		t.Fatalf("subscribe reply = %+v", out)
+	}
+
+	hub.Publish(events.New(events.StudentCreated, 7, nil))
+
+	var out outgoing
+	if err := ws.ReadJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Type != "event" || out.Roster != StudentsRoster || out.Event.StudentId != 7 {
+		t.Errorf("event = %+v", out)
+	}
+
+	// Course events go to the course roster only, so the next message is
+	// the reply to the command below.
+	hub.Publish(events.NewEnrollment(events.StudentEnrolled, 7, 3, nil))
+
+	if out := roundTrip(t, ws, incoming{Type: "bogus", Ref: "2"}); out.Type != "error" || out.Status != http.StatusBadRequest {
+		t.Errorf("unknown command reply = %+v, want a 400 error", out)
+	}
+}
+
+func TestCommandErrorsCarryRESTStatus(t *testing.T) {
+	ws := dial(t, newTestServer(t, NewHub()))
+
+	tests := []struct {
+		in   incoming
+		want int
+	}{
+		{incoming{Type: "create_student", Student: nil}, http.StatusBadRequest},
+		{incoming{Type: "delete_student", Id: 404}, http.StatusNotFound},
+		{incoming{Type: "transition", Id: 404, Event: "enroll"}, http.StatusNotFound},
+		{incoming{Type: "subscribe", Roster: "courses/x"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if out := roundTrip(t, ws, tt.in); out.Type != "error" || out.Status != tt.want {
+			t.Errorf("%s reply = %+v, want status %d", tt.in.Type, out, tt.want)
+		}
+	}
+}
+
+// TestSlowClientDoesNotBlockPublish fills a connection's queue without a
+// write loop draining it. Publish must return straight away, and the close
+// frame must only go out once the write loop runs.
+func TestSlowClientDoesNotBlockPublish(t *testing.T) {
+	accepted := make(chan *websocket.Conn, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		accepted <- ws
+	}))
+	defer server.Close()
+
+	client := dial(t, "ws"+strings.TrimPrefix(server.URL, "http"))
+
+	hub := NewHub()
+	c := newConn(hub, nil, <-accepted)
+	hub.register(c)
+	hub.subscribe(c, StudentsRoster)
+
+	start := time.Now()
+	for i := 0; i < sendBuffer+10; i++ {
+		hub.Publish(events.New(events.StudentCreated, int64(i), nil))
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("publishing to a slow client took %v", elapsed)
+	}
+
+	select {
+	case <-c.done:
+	default:
+		t.Fatal("overflowing client was not marked for closing")
+	}
+
+	go c.writeLoop()
+
+	// The queued events are dropped; the next frame is the close.
+	_, _, err := client.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Errorf("read after overflow error = %v, want a policy violation close", err)
+	}
+}
+
+func TestClosedHubRejectsConnections(t *testing.T) {
+	hub := NewHub()
+	hub.Close()
+
+	ws := dial(t, newTestServer(t, hub))
+
+	_, _, err := ws.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("read error = %v, want a going-away close", err)
+	}
+}
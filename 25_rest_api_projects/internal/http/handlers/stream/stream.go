@@ -0,0 +1,104 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// retryMillis is the reconnect delay suggested to EventSource clients.
+const retryMillis = 3000
+
+// Students streams student changes as Server-Sent Events. Clients resume
+// with the Last-Event-ID header (or a last_event_id query parameter). Ids
+// are "<epoch>-<seq>"; when the requested events are no longer buffered, or
+// the id belongs to an epoch from before a restart, a "reset" event tells
+// them to refetch the roster before continuing.
+func Students(broadcaster *events.Broadcaster, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+
+		lastId := r.Header.Get("Last-Event-ID")
+		if lastId == "" {
+			lastId = r.URL.Query().Get("last_event_id")
+		}
+
+		var epoch string
+		var after uint64
+		resume := lastId != ""
+
+		if resume {
+			var err error
+			epoch, after, err = events.ParseId(lastId)
+			if err != nil {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("invalid Last-Event-ID: %q", lastId)))
+				return
+			}
+		}
+
+		sub, replay, complete := broadcaster.Subscribe(epoch, after, resume)
+		defer sub.Unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+
+		if !complete {
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+
+		for _, item := range replay {
+			if err := writeEvent(w, item); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			slog.ErrorContext(r.Context(), "event stream: flushing", slog.String("error", err.Error()))
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-sub.Done:
+				return
+			case item := <-sub.C:
+				if err := writeEvent(w, item); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, item events.Sequenced) error {
+	data, err := json.Marshal(item.Event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", item.Id(), item.Event.Type, data)
+	return err
+}
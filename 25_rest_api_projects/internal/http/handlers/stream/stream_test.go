@@ -0,0 +1,137 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/events"
+)
+
+// readUntil reads stream lines until one starts with prefix.
+func readUntil(t *testing.T, lines *bufio.Scanner, prefix string) string {
+	t.Helper()
+
+	for lines.Scan() {
+		if strings.HasPrefix(lines.Text(), prefix) {
+			return lines.Text()
+		}
+	}
+	t.Fatalf("stream ended before a %q line: %v", prefix, lines.Err())
+	return ""
+}
+
+func TestStudentsResumesFromLastEventID(t *testing.T) {
+	b := events.NewBroadcaster(8, 8)
+	b.Publish(events.New(events.StudentCreated, 1, nil))
+	b.Publish(events.New(events.StudentUpdated, 1, nil))
+
+	server := httptest.NewServer(Students(b, time.Hour))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	req.Header.Set("Last-Event-ID", b.Epoch()+"-1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+
+	if got := readUntil(t, lines, "id:"); got != "id: "+b.Epoch()+"-2" {
+		t.Errorf("first replayed event %q, want id 2", got)
+	}
+	if got := readUntil(t, lines, "event:"); got != "event: student.updated" {
+		t.Errorf("replayed event type %q", got)
+	}
+
+	b.Publish(events.New(events.StudentDeleted, 1, nil))
+
+	if got := readUntil(t, lines, "id:"); got != "id: "+b.Epoch()+"-3" {
+		t.Errorf("live event %q, want id 3", got)
+	}
+}
+
+func TestStudentsSendsResetWhenEventsWereLost(t *testing.T) {
+	b := events.NewBroadcaster(1, 8)
+	for i := 0; i < 3; i++ {
+		b.Publish(events.New(events.StudentCreated, 1, nil))
+	}
+
+	server := httptest.NewServer(Students(b, time.Hour))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?last_event_id="+b.Epoch()+"-1", nil)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if got := readUntil(t, bufio.NewScanner(resp.Body), "event:"); got != "event: reset" {
+		t.Errorf("first event %q, want a reset", got)
+	}
+}
+
+func TestStudentsSendsResetAfterARestart(t *testing.T) {
+	before := events.NewBroadcaster(8, 8)
+	before.Publish(events.New(events.StudentCreated, 1, nil))
+
+	// The restarted server has published as many events, so only the epoch
+	// tells the client's id apart.
+	b := events.NewBroadcaster(8, 8)
+	b.Publish(events.New(events.StudentCreated, 2, nil))
+	b.Publish(events.New(events.StudentCreated, 3, nil))
+
+	server := httptest.NewServer(Students(b, time.Hour))
+	defer server.Close()
+
+	for _, lastId := range []string{before.Epoch() + "-1", "1"} {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		req.Header.Set("Last-Event-ID", lastId)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			cancel()
+			t.Fatal(err)
+		}
+
+		if got := readUntil(t, bufio.NewScanner(resp.Body), "event:"); got != "event: reset" {
+			t.Errorf("Last-Event-ID %q: first event %q, want a reset", lastId, got)
+		}
+
+		resp.Body.Close()
+		cancel()
+	}
+}
+
+func TestStudentsRejectsInvalidLastEventID(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+
+	Students(events.NewBroadcaster(1, 1), time.Hour)(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
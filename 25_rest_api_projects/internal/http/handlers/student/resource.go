@@ -0,0 +1,96 @@
+package student
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// Resource is a student as the latest API version shows it. Version 2
+// groups the lifecycle status with the time it last changed; version 1
+// clients get the flat fields of types.Student through V1.
+type Resource struct {
+	Id     int64  `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Age    int    `json:"age"`
+	Status Status `json:"status"`
+}
+
+type Status struct {
+	State     lifecycle.Status `json:"state"`
+	ChangedAt time.Time        `json:"changed_at"`
+}
+
+func NewResource(student types.Student) Resource {
+	return Resource{
+		Id:    student.Id,
+		Name:  student.Name,
+		Email: student.Email,
+		Age:   student.Age,
+		Status: Status{
+			State:     student.Status,
+			ChangedAt: student.StatusChangedAt,
+		},
+	}
+}
+
+func newResources(students []types.Student) []Resource {
+	if students == nil {
+		return nil
+	}
+
+	resources := make([]Resource, len(students))
+	for i, student := range students {
+		resources[i] = NewResource(student)
+	}
+	return resources
+}
+
+func (r Resource) student() types.Student {
+	return types.Student{
+		Id:              r.Id,
+		Name:            r.Name,
+		Email:           r.Email,
+		Age:             r.Age,
+		Status:          r.Status.State,
+		StatusChangedAt: r.Status.ChangedAt,
+	}
+}
+
+// V1 is the versioning.Transformer turning a student, or a list of them,
+// back into the version 1 shape.
+func V1(body []byte) ([]byte, error) {
+	var out any
+
+	switch trimmed := bytes.TrimSpace(body); {
+	case bytes.Equal(trimmed, []byte("null")):
+		return body, nil
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		var resources []Resource
+		if err := json.Unmarshal(trimmed, &resources); err != nil {
+			return nil, err
+		}
+
+		students := make([]types.Student, len(resources))
+		for i, resource := range resources {
+			students[i] = resource.student()
+		}
+		out = students
+	default:
+		var resource Resource
+		if err := json.Unmarshal(trimmed, &resource); err != nil {
+			return nil, err
+		}
+		out = resource.student()
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,55 @@
+package student
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+func TestV1(t *testing.T) {
+	changed := time.Date(2025, 6, 30, 9, 0, 0, 0, time.UTC)
+	student := types.Student{Id: 7, Name: "Ada", Email: "ada@example.com", Age: 20, Status: lifecycle.StatusEnrolled, StatusChangedAt: changed}
+
+	one, err := json.Marshal(NewResource(student))
+	if err != nil {
+		t.Fatal(err)
+	}
+	many, err := json.Marshal(newResources([]types.Student{student, student}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := V1(one)
+	if err != nil {
+		t.Fatalf("V1(object): %v", err)
+	}
+	var got types.Student
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != student {
+		t.Errorf("V1(object) = %+v, want %+v", got, student)
+	}
+
+	body, err = V1(many)
+	if err != nil {
+		t.Fatalf("V1(array): %v", err)
+	}
+	var list []types.Student
+	if err := json.Unmarshal(body, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1] != student {
+		t.Errorf("V1(array) = %+v", list)
+	}
+
+	if body, err := V1([]byte("null\n")); err != nil || string(body) != "null\n" {
+		t.Errorf("V1(null) = %q, %v", body, err)
+	}
+	if _, err := V1([]byte("{")); err == nil {
+		t.Error("V1 accepted malformed JSON")
+	}
+}
@@ -0,0 +1,359 @@
+package student
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/flags"
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/report"
+	"github.com/faysal0x1/Go-Learn/internal/stats"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/request"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+)
+
+func New(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.InfoContext(r.Context(), "creating a student")
+
+		var student types.Student
+
+		err := json.NewDecoder(r.Body).Decode(&student)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+
+		if err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := validate.Struct(student); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		lastId, err := storage.CreateStudent(student.Name, student.Email, student.Age)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "student created successfully", slog.Int64("id", lastId))
+
+		response.WriteJson(w, http.StatusCreated, map[string]int64{"id": lastId})
+	}
+}
+
+func GetById(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		student, err := storage.GetStudentById(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, NewResource(student))
+	}
+}
+
+func GetList(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		students, err := storage.GetStudents()
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, newResources(students))
+	}
+}
+
+func Update(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		var student types.Student
+
+		if err := json.NewDecoder(r.Body).Decode(&student); err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := validate.Struct(student); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		student.Id = id
+
+		if err := storage.UpdateStudent(student); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		updated, err := storage.GetStudentById(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, NewResource(updated))
+	}
+}
+
+func Delete(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if err := storage.DeleteStudent(id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GPA returns the credit-weighted grade point average over the student's
+// graded enrollments.
+func GPA(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if _, err := storage.GetStudentById(id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		enrollments, err := storage.GetEnrollmentsByStudent(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		var courseIds []int64
+		for _, enrollment := range enrollments {
+			if enrollment.Grade != nil {
+				courseIds = append(courseIds, enrollment.CourseId)
+			}
+		}
+
+		courses, err := storage.GetCoursesByIds(courseIds)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		byId := make(map[int64]types.Course, len(courses))
+		for _, course := range courses {
+			byId[course.Id] = course
+		}
+
+		gpa := types.StudentGPA{StudentId: id}
+
+		var grades, credits []float64
+
+		for _, enrollment := range enrollments {
+			if enrollment.Grade == nil {
+				continue
+			}
+
+			// A course deleted since the enrollments were read no longer
+			// counts.
+			course, ok := byId[enrollment.CourseId]
+			if !ok {
+				continue
+			}
+
+			grades = append(grades, *enrollment.Grade)
+			credits = append(credits, float64(course.Credits))
+			gpa.Courses++
+			gpa.Credits += course.Credits
+		}
+
+		gpa.GPA = stats.WeightedAverage(grades, credits)
+
+		response.WriteJson(w, http.StatusOK, gpa)
+	}
+}
+
+func Enrollments(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if _, err := storage.GetStudentById(id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		enrollments, err := storage.GetEnrollmentsByStudent(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, enrollments)
+	}
+}
+
+type transitionRequest struct {
+	Event string `json:"event" validate:"required"`
+}
+
+// Transition applies a lifecycle event to the student. Events that exist
+// but are not allowed from the current status are rejected with 409.
+func Transition(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		var req transitionRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		transition, err := storage.TransitionStudent(id, req.Event)
+
+		switch {
+		case errors.Is(err, lifecycle.ErrUnknownEvent):
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		case errors.Is(err, lifecycle.ErrIllegalTransition):
+			response.WriteJson(w, http.StatusConflict, response.GeneralError(err))
+			return
+		case err != nil:
+			response.StorageError(w, err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "student transitioned", slog.Int64("id", id), slog.String("from", string(transition.From)), slog.String("to", string(transition.To)))
+
+		response.WriteJson(w, http.StatusOK, transition)
+	}
+}
+
+func Transitions(storage storage.Storage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if _, err := storage.GetStudentById(id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		transitions, err := storage.GetStudentTransitions(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, transitions)
+	}
+}
+
+// TranscriptFlag ships Transcript dark: while it is off for a request the
+// endpoint answers 404 as if it did not exist.
+const TranscriptFlag = "student-transcripts"
+
+// Transcript renders the student's courses and GPA as a PDF.
+func Transcript(storage storage.Storage, cfg config.Reports) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !flags.Enabled(r.Context(), TranscriptFlag) {
+			http.NotFound(w, r)
+			return
+		}
+
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		student, err := storage.GetStudentById(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		enrollments, err := storage.GetEnrollmentsByStudent(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		courseIds := make([]int64, len(enrollments))
+		for i, enrollment := range enrollments {
+			courseIds[i] = enrollment.CourseId
+		}
+
+		courses, err := storage.GetCoursesByIds(courseIds)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		byId := make(map[int64]types.Course, len(courses))
+		for _, course := range courses {
+			byId[course.Id] = course
+		}
+
+		entries := make([]report.TranscriptEntry, 0, len(enrollments))
+		for _, enrollment := range enrollments {
+			entries = append(entries, report.TranscriptEntry{Course: byId[enrollment.CourseId], Enrollment: enrollment})
+		}
+
+		school := cmp.Or(tenant.FromContext(r.Context()).Config.Name, cfg.Institution)
+		doc := report.Transcript(report.PageSize(cfg.PageSize), school, student, entries, time.Now())
+
+		response.WritePDF(w, fmt.Sprintf("transcript-%d.pdf", id), doc.Bytes())
+	}
+}
@@ -0,0 +1,126 @@
+package student
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+func newStore(t *testing.T) *sqlite.Sqlite {
+	t.Helper()
+
+	store, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Db.Close() })
+	return store
+}
+
+// courseCalls counts how the courses of a request are loaded.
+type courseCalls struct {
+	storage.Storage
+	one, many int
+}
+
+func (s *courseCalls) GetCourseById(id int64) (types.Course, error) {
+	s.one++
+	return s.Storage.GetCourseById(id)
+}
+
+func (s *courseCalls) GetCoursesByIds(ids []int64) ([]types.Course, error) {
+	s.many++
+	return s.Storage.GetCoursesByIds(ids)
+}
+
+func TestGPALoadsCoursesInOneQuery(t *testing.T) {
+	store := newStore(t)
+
+	id, err := store.CreateStudent("Ada", "ada@example.edu", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grades := []struct {
+		credits int
+		grade   float64
+		graded  bool
+	}{{4, 4.0, true}, {2, 2.5, true}, {3, 0, false}}
+
+	for i, g := range grades {
+		course, err := store.CreateCourse(fmt.Sprintf("CS10%d", i), "Course", g.credits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Enroll(id, course); err != nil {
+			t.Fatal(err)
+		}
+		if g.graded {
+			if err := store.RecordGrade(id, course, g.grade); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	calls := &courseCalls{Storage: store}
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/students/%d/gpa", id), nil)
+	req.SetPathValue("id", fmt.Sprint(id))
+
+	rec := httptest.NewRecorder()
+	GPA(calls)(rec, req)
+
+	var gpa types.StudentGPA
+	if err := json.Unmarshal(rec.Body.Bytes(), &gpa); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+
+	want := types.StudentGPA{StudentId: id, Courses: 2, Credits: 6, GPA: (4*4.0 + 2*2.5) / 6}
+	if rec.Code != http.StatusOK || gpa != want {
+		t.Errorf("GPA = %d %+v, want %+v", rec.Code, gpa, want)
+	}
+
+	if calls.one != 0 || calls.many != 1 {
+		t.Errorf("loaded courses with %d single and %d batch queries, want one batch query", calls.one, calls.many)
+	}
+}
+
+func TestTranscriptIsBehindItsFlag(t *testing.T) {
+	store := newStore(t)
+
+	id, err := store.CreateStudent("Ada", "ada@example.edu", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcript := func(on bool) *httptest.ResponseRecorder {
+		t.Helper()
+
+		ctx := tenant.WithTenant(context.Background(), tenant.Tenant{Config: config.Tenant{Features: map[string]bool{TranscriptFlag: on}}})
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/students/%d/transcript", id), nil).WithContext(ctx)
+		req.SetPathValue("id", fmt.Sprint(id))
+
+		rec := httptest.NewRecorder()
+		Transcript(store, config.Reports{PageSize: "a4", Institution: "Test School"})(rec, req)
+		return rec
+	}
+
+	if rec := transcript(false); rec.Code != http.StatusNotFound {
+		t.Errorf("flag off = %d, want 404", rec.Code)
+	}
+
+	if rec := transcript(true); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("flag on = %d %s, want a PDF", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
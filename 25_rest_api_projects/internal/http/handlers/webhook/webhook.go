@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/request"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+	"github.com/faysal0x1/Go-Learn/internal/webhook"
+)
+
+// New registers a subscription. The secret is generated when omitted and is
+// only ever returned in this response.
+func New(store webhook.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slog.InfoContext(r.Context(), "creating a webhook")
+
+		var hook types.Webhook
+
+		err := json.NewDecoder(r.Body).Decode(&hook)
+		if errors.Is(err, io.EOF) {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("empty body")))
+			return
+		}
+
+		if err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+
+		if err := validate.Struct(hook); err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		for _, eventType := range hook.Events {
+			if !events.Type(eventType).IsValid() {
+				response.WriteJson(w, http.StatusBadRequest, response.GeneralError(fmt.Errorf("unknown event type: %s", eventType)))
+				return
+			}
+		}
+
+		if hook.Secret == "" {
+			hook.Secret = webhook.NewSecret()
+		}
+
+		lastId, err := store.CreateWebhook(hook)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		created, err := store.GetWebhookById(lastId)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		slog.InfoContext(r.Context(), "webhook created successfully", slog.Int64("id", lastId))
+
+		response.WriteJson(w, http.StatusCreated, created)
+	}
+}
+
+func GetList(store webhook.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hooks, err := store.GetWebhooks()
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		for i := range hooks {
+			hooks[i].Secret = ""
+		}
+
+		response.WriteJson(w, http.StatusOK, hooks)
+	}
+}
+
+func GetById(store webhook.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		hook, err := store.GetWebhookById(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		hook.Secret = ""
+
+		response.WriteJson(w, http.StatusOK, hook)
+	}
+}
+
+func Delete(store webhook.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if err := store.DeleteWebhook(id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// SetEnabled returns a handler that enables or disables a webhook. Enabling
+// resets the failure streak and resumes any deliveries still pending.
+func SetEnabled(store webhook.Store, enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if err := store.SetWebhookEnabled(id, enabled); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		hook, err := store.GetWebhookById(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		hook.Secret = ""
+
+		response.WriteJson(w, http.StatusOK, hook)
+	}
+}
+
+func Deliveries(store webhook.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := request.PathId(r, "id")
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, response.GeneralError(err))
+			return
+		}
+
+		if _, err := store.GetWebhookById(id); err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		deliveries, err := store.GetDeliveries(id)
+		if err != nil {
+			response.StorageError(w, err)
+			return
+		}
+
+		response.WriteJson(w, http.StatusOK, deliveries)
+	}
+}
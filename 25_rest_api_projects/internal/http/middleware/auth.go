@@ -0,0 +1,73 @@
+// Package middleware holds http.Handler wrappers applied around the router.
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+type KeyStore interface {
+	AuthenticateAPIKey(hash string) (types.APIKey, error)
+}
+
+// APIKey rejects requests to paths under one of the prefixes unless they
+// carry a key issued with `students_api create-api-key`, either as a bearer
+// token or in the X-API-Key header. The key is recorded in the request
+// context for Tenant to check against the resolved tenant.
+func APIKey(store KeyStore, prefixes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasPrefix(r.URL.Path, prefixes) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The Authorization header may carry a tenant JWT instead of
+			// a key, so only take bearer values shaped like a key.
+			key := r.Header.Get("X-API-Key")
+			if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && apikey.Valid(bearer) {
+				key = bearer
+			}
+
+			if !apikey.Valid(key) {
+				unauthorized(w)
+				return
+			}
+
+			authenticated, err := store.AuthenticateAPIKey(apikey.Hash(key))
+			if err != nil {
+				if !errors.Is(err, storage.ErrNotFound) {
+					slog.ErrorContext(r.Context(), "api key lookup failed", slog.String("error", err.Error()))
+					response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(err))
+					return
+				}
+				unauthorized(w)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(apikey.WithKey(r.Context(), authenticated)))
+		})
+	}
+}
+
+func hasPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="students-api"`)
+	response.WriteJson(w, http.StatusUnauthorized, response.GeneralError(fmt.Errorf("missing or invalid api key")))
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// MaxBody rejects requests whose declared length exceeds limit with 413
+// and caps the body of the rest, so a chunked upload cannot get past it
+// either. Handlers see the cap as an *http.MaxBytesError while decoding.
+func MaxBody(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				response.WriteJson(w, http.StatusRequestEntityTooLarge,
+					response.GeneralError(fmt.Errorf("request body exceeds %d bytes", limit)))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
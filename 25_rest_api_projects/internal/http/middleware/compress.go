@@ -0,0 +1,387 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// Compress encodes responses with gzip or deflate, whichever the client
+// prefers, and decodes request bodies the client encoded. Responses are
+// buffered up to the minimum size before deciding, so small ones go out as
+// they are; a flush decides straight away so event streams are compressed
+// and still delivered event by event. Partial, empty and already encoded
+// responses, HEAD requests and protocol upgrades are passed through.
+func Compress(cfg config.Compression) func(http.Handler) http.Handler {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	if _, err := gzip.NewWriterLevel(io.Discard, cfg.Level); err != nil {
+		cfg.Level = gzip.DefaultCompression
+	}
+
+	pools := newEncoderPools(cfg.Level)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := decodeRequest(r, cfg.MaxDecodedSize); err != nil {
+				w.Header().Set("Accept-Encoding", "gzip, deflate")
+				response.WriteJson(w, http.StatusUnsupportedMediaType, response.GeneralError(err))
+				return
+			}
+
+			if r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, cfg: cfg, encoding: encoding, pools: pools, status: http.StatusOK}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks gzip or deflate by their quality in an
+// Accept-Encoding header, gzip on a tie, or "" when neither is acceptable.
+func negotiateEncoding(header string) string {
+	quality := map[string]float64{}
+
+	for _, item := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(item, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if coding == "x-gzip" {
+			coding = "gzip"
+		}
+		quality[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, coding := range []string{"gzip", "deflate"} {
+		q, ok := quality[coding]
+		if !ok {
+			q = quality["*"]
+		}
+		if q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+	return best
+}
+
+// decodeRequest replaces an encoded request body with its decoded form,
+// which fails with *http.MaxBytesError past limit decoded bytes, so a small
+// upload cannot inflate into an unbounded one. Route body limits then
+// apply to the decoded bytes too.
+func decodeRequest(r *http.Request, limit int64) error {
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+
+	switch encoding {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip", "deflate":
+	default:
+		return fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	r.Body = &decodedBody{body: r.Body, encoding: encoding, limit: limit}
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	return nil
+}
+
+type decodedBody struct {
+	body     io.ReadCloser
+	encoding string
+	limit    int64
+
+	decoder io.ReadCloser
+	read    int64
+	err     error
+}
+
+func (b *decodedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	// The decoder reads the stream header, so it is only created once the
+	// handler reads the body.
+	if b.decoder == nil {
+		var err error
+		if b.encoding == "deflate" {
+			b.decoder, err = zlib.NewReader(b.body)
+		} else {
+			b.decoder, err = gzip.NewReader(b.body)
+		}
+		if err != nil {
+			b.err = fmt.Errorf("decoding %s request body: %w", b.encoding, err)
+			return 0, b.err
+		}
+	}
+
+	// Read one byte past the limit to tell a body of exactly limit bytes
+	// from a longer one.
+	if remaining := b.limit - b.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := b.decoder.Read(p)
+	b.read += int64(n)
+
+	if b.read > b.limit {
+		b.err = &http.MaxBytesError{Limit: b.limit}
+		return n - int(b.read-b.limit), b.err
+	}
+
+	return n, err
+}
+
+func (b *decodedBody) Close() error {
+	if b.decoder != nil {
+		b.decoder.Close()
+	}
+	return b.body.Close()
+}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+type encoderPools struct {
+	gzip    sync.Pool
+	deflate sync.Pool
+}
+
+func newEncoderPools(level int) *encoderPools {
+	p := &encoderPools{}
+	p.gzip.New = func() any {
+		zw, _ := gzip.NewWriterLevel(nil, level)
+		return zw
+	}
+	p.deflate.New = func() any {
+		zw, _ := zlib.NewWriterLevel(nil, level)
+		return zw
+	}
+	return p
+}
+
+func (p *encoderPools) pool(encoding string) *sync.Pool {
+	if encoding == "deflate" {
+		return &p.deflate
+	}
+	return &p.gzip
+}
+
+// compressWriter holds back the response until it knows whether to
+// compress it: once MinSize bytes are written, on a flush, or when the
+// handler returns.
+type compressWriter struct {
+	http.ResponseWriter
+	cfg      config.Compression
+	encoding string
+	pools    *encoderPools
+
+	status      int
+	wroteHeader bool
+	started     bool
+	hijacked    bool
+	buf         []byte
+	enc         encoder
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if w.wroteHeader {
+		return
+	}
+	w.status, w.wroteHeader = status, true
+
+	if !w.compressible() {
+		w.start(false)
+		return
+	}
+
+	if length, err := strconv.Atoi(w.Header().Get("Content-Length")); err == nil && length < w.cfg.MinSize {
+		w.start(false)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.started {
+		if w.enc != nil {
+			return w.enc.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+
+	if len(w.buf) >= w.cfg.MinSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// compressible reports whether the response as described by its status
+// and headers so far may be compressed.
+func (w *compressWriter) compressible() bool {
+	switch w.status {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+
+	h := w.Header()
+
+	if h.Get("Content-Encoding") != "" || strings.Contains(strings.ToLower(h.Get("Cache-Control")), "no-transform") {
+		return false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	for _, skip := range w.cfg.SkipTypes {
+		if skip != "" && strings.HasPrefix(mediaType, strings.ToLower(skip)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// start sends the header, compressed when compress is set and the
+// response allows it, followed by whatever was buffered.
+func (w *compressWriter) start(compress bool) error {
+	w.started = true
+	h := w.Header()
+
+	// net/http would sniff the type from the first bytes written, which
+	// are gzip once compressing, so sniff the buffered plain bytes here.
+	if _, ok := h["Content-Type"]; !ok && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if compress && w.compressible() {
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		h.Set("Content-Encoding", w.encoding)
+
+		// The encoded body differs byte for byte from the one the
+		// validator was computed for.
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+
+		w.enc = w.pools.pool(w.encoding).Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	buf := w.buf
+	w.buf = nil
+
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// Flush sends what the handler wrote so far. A stream is compressed from
+// its first flush however little it has written.
+func (w *compressWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.started {
+		w.start(true)
+	}
+
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, rw, err
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close sends a response still held back, uncompressed as it stayed under
+// the minimum size, and finishes the compressed stream.
+func (w *compressWriter) close() {
+	if w.hijacked {
+		return
+	}
+
+	if !w.started && (w.wroteHeader || len(w.buf) > 0) {
+		w.start(false)
+	}
+
+	if w.enc != nil {
+		w.enc.Close()
+		w.pools.pool(w.encoding).Put(w.enc)
+		w.enc = nil
+	}
+}
@@ -0,0 +1,247 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+var compression = config.Compression{
+	Enabled:        true,
+	Level:          gzip.DefaultCompression,
+	MinSize:        64,
+	SkipTypes:      []string{"image/"},
+	MaxDecodedSize: 1 << 10,
+}
+
+var large = strings.Repeat(`{"name":"Ada","email":"ada@example.edu"}`, 20)
+
+func serveCompressed(method, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/students", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	rec := httptest.NewRecorder()
+	Compress(compression)(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func writes(contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, body)
+	}
+}
+
+func gunzip(t *testing.T, r io.Reader) string {
+	t.Helper()
+
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	return string(body)
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		"identity":                "",
+		"gzip":                    "gzip",
+		"x-gzip":                  "gzip",
+		"deflate":                 "deflate",
+		"gzip, deflate":           "gzip",
+		"gzip;q=0.5, deflate":     "deflate",
+		"GZIP;q=0.8, br":          "gzip",
+		"*":                       "gzip",
+		"*;q=0.5, gzip;q=0":       "deflate",
+		"gzip;q=0, deflate;q=0.0": "",
+	}
+
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompressesLargeResponses(t *testing.T) {
+	rec := serveCompressed(http.MethodGet, "gzip, deflate", writes("application/json", large))
+
+	if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Vary") != "Accept-Encoding" || rec.Header().Get("ETag") != `W/"v1"` {
+		t.Fatalf("headers = %v", rec.Header())
+	}
+	if body := gunzip(t, rec.Body); body != large {
+		t.Errorf("decoded body = %q", body)
+	}
+
+	rec = serveCompressed(http.MethodGet, "deflate", writes("application/json", large))
+
+	zr, err := zlib.NewReader(rec.Body)
+	if err != nil || rec.Header().Get("Content-Encoding") != "deflate" {
+		t.Fatalf("deflate response: %v, headers %v", err, rec.Header())
+	}
+	if body, _ := io.ReadAll(zr); string(body) != large {
+		t.Errorf("deflated body = %q", body)
+	}
+}
+
+func TestLeavesSomeResponsesAlone(t *testing.T) {
+	tests := []struct {
+		name, method, accept string
+		handler              http.HandlerFunc
+	}{
+		{"small", http.MethodGet, "gzip", writes("application/json", `{"id":1}`)},
+		{"not accepted", http.MethodGet, "", writes("application/json", large)},
+		{"skipped type", http.MethodGet, "gzip", writes("image/png", large)},
+		{"head", http.MethodHead, "gzip", writes("application/json", large)},
+		{"already encoded", http.MethodGet, "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "br")
+			io.WriteString(w, large)
+		}},
+		{"partial", http.MethodGet, "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusPartialContent)
+			io.WriteString(w, large)
+		}},
+	}
+
+	for _, tt := range tests {
+		rec := serveCompressed(tt.method, tt.accept, tt.handler)
+
+		if encoding := rec.Header().Get("Content-Encoding"); encoding == "gzip" {
+			t.Errorf("%s: response was gzipped", tt.name)
+		}
+		if tt.method != http.MethodHead && rec.Body.Len() == 0 {
+			t.Errorf("%s: body lost", tt.name)
+		}
+	}
+}
+
+func TestFlushCompressesStreamsAsTheyGo(t *testing.T) {
+	event := "data: {\"id\":1}\n\n"
+
+	req := httptest.NewRequest(http.MethodGet, "/api/students/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+	var flushed []byte
+
+	Compress(compression)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, event)
+		w.(http.Flusher).Flush()
+
+		flushed = bytes.Clone(rec.Body.Bytes())
+	})).ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("headers = %v, want a gzipped stream", rec.Header())
+	}
+
+	// What reached the client by the flush already decodes to the event,
+	// well under the minimum size.
+	zr, err := gzip.NewReader(bytes.NewReader(flushed))
+	if err != nil {
+		t.Fatalf("flushed bytes: %v", err)
+	}
+	got := make([]byte, len(event))
+	if _, err := io.ReadFull(zr, got); err != nil || string(got) != event {
+		t.Errorf("flushed stream = %q, %v, want %q", got, err, event)
+	}
+}
+
+func compressed(t *testing.T, data []byte) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+// decodes serves a request with the given body and Content-Encoding through
+// Compress to a handler that reads the body like the JSON handlers do.
+func decodes(t *testing.T, body io.Reader, encoding string) (int, string) {
+	t.Helper()
+
+	var got []byte
+	handler := Compress(compression)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if got, err = io.ReadAll(r.Body); err != nil {
+			response.WriteJson(w, response.DecodeStatus(err), response.GeneralError(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/students", body)
+	req.Header.Set("Content-Encoding", encoding)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code, string(got)
+}
+
+func TestDecodesRequestBodies(t *testing.T) {
+	body := `{"name":"Ada"}`
+
+	if code, got := decodes(t, compressed(t, []byte(body)), "gzip"); code != http.StatusNoContent || got != body {
+		t.Errorf("gzip body = %d %q", code, got)
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte(body))
+	zw.Close()
+
+	if code, got := decodes(t, &buf, "deflate"); code != http.StatusNoContent || got != body {
+		t.Errorf("deflate body = %d %q", code, got)
+	}
+
+	if code, _ := decodes(t, strings.NewReader("not gzip"), "gzip"); code != http.StatusBadRequest {
+		t.Errorf("corrupt body = %d, want 400", code)
+	}
+
+	if code, _ := decodes(t, strings.NewReader(body), "br"); code != http.StatusUnsupportedMediaType {
+		t.Errorf("unsupported encoding = %d, want 415", code)
+	}
+}
+
+func TestDecodedSizeIsLimited(t *testing.T) {
+	limit := int(compression.MaxDecodedSize)
+
+	// A megabyte of zeros gzips to a few kilobytes.
+	bomb := compressed(t, make([]byte, 1<<20))
+	if bomb.Len() > 4<<10 {
+		t.Fatalf("bomb is %d bytes compressed", bomb.Len())
+	}
+
+	if code, got := decodes(t, bomb, "gzip"); code != http.StatusRequestEntityTooLarge || len(got) > limit {
+		t.Errorf("inflating bomb = %d after %d bytes, want 413 within %d", code, len(got), limit)
+	}
+
+	if code, got := decodes(t, compressed(t, make([]byte, limit)), "gzip"); code != http.StatusNoContent || len(got) != limit {
+		t.Errorf("body of exactly the limit = %d with %d bytes", code, len(got))
+	}
+
+	if code, _ := decodes(t, compressed(t, make([]byte, limit+1)), "gzip"); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("body one byte over the limit = %d, want 413", code)
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+var corsConfig = config.CORS{
+	AllowedOrigins: []string{"https://app.example.com", "https://*.schools.test"},
+	AllowedMethods: []string{"GET", "POST"},
+	AllowedHeaders: []string{"Authorization", "Content-Type"},
+	ExposedHeaders: []string{"X-Request-ID"},
+	MaxAge:         10 * time.Minute,
+}
+
+func preflight(origin, method, headers string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/api/students", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+
+	// The API key check stands in for authentication, which preflights
+	// never pass.
+	rec := httptest.NewRecorder()
+	CORS(corsConfig)(APIKey(keyStore{}, "/api/")(ok)).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSPreflight(t *testing.T) {
+	tests := []struct {
+		name, origin, method, headers string
+		allowed                       bool
+	}{
+		{"allowed", "https://app.example.com", "POST", "content-type, authorization", true},
+		{"wildcard subdomain", "https://north.schools.test", "GET", "", true},
+		{"apex of a wildcard", "https://schools.test", "GET", "", false},
+		{"unknown origin", "https://evil.example.com", "GET", "", false},
+		{"method not allowed", "https://app.example.com", "DELETE", "", false},
+		{"header not allowed", "https://app.example.com", "POST", "X-Custom", false},
+	}
+
+	for _, tt := range tests {
+		rec := preflight(tt.origin, tt.method, tt.headers)
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("%s: status = %d, want 204 without reaching authentication", tt.name, rec.Code)
+		}
+
+		methods := rec.Header().Get("Access-Control-Allow-Methods")
+		if tt.allowed != (methods == "GET, POST") {
+			t.Errorf("%s: Access-Control-Allow-Methods = %q, allowed %v", tt.name, methods, tt.allowed)
+		}
+
+		if tt.allowed && (rec.Header().Get("Access-Control-Allow-Origin") != tt.origin || rec.Header().Get("Access-Control-Max-Age") != "600") {
+			t.Errorf("%s: headers = %v", tt.name, rec.Header())
+		}
+	}
+}
+
+func TestCORSActualRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/students", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	rec := httptest.NewRecorder()
+	CORS(corsConfig)(ok).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want the handler's 200", rec.Code)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || rec.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+		t.Errorf("headers = %v", rec.Header())
+	}
+	if rec.Header().Get("Access-Control-Allow-Methods") != "" {
+		t.Error("an actual request got preflight headers")
+	}
+}
+
+func TestCORSCredentialsEchoTheOrigin(t *testing.T) {
+	cfg := config.CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	rec := httptest.NewRecorder()
+	CORS(cfg)(ok).ServeHTTP(rec, req)
+
+	if rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("headers = %v, want the origin echoed with credentials", rec.Header())
+	}
+}
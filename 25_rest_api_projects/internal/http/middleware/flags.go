@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/flags"
+)
+
+// Flags makes set available to flags.Enabled in handlers below, evaluated
+// for the user key and roles named in the configured headers. Nothing
+// verifies those headers, so any client can claim a role or pick the key
+// its rollout bucket comes from: a flag may choose which version of a
+// feature a request gets, never whether it is allowed. Have the gateway
+// set or strip the headers to keep rollouts to who they were meant for.
+func Flags(set *flags.Set, cfg config.Flags) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := flags.WithSet(r.Context(), set)
+			ctx = flags.WithSubject(ctx, flags.Subject{
+				Key:   strings.TrimSpace(r.Header.Get(cfg.UserHeader)),
+				Roles: flags.ParseRoles(r.Header.Get(cfg.RoleHeader)),
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/flags"
+)
+
+func TestFlagsReadsTheSubjectFromHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	if err := os.WriteFile(path, []byte("flags:\n  reports:\n    enabled: true\n    roles: [staff]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Flags{Path: path, UserHeader: "X-User-ID", RoleHeader: "X-User-Roles"}
+
+	set, err := flags.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subject flags.Subject
+	var enabled bool
+
+	handler := Flags(set, cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = flags.SubjectFromContext(r.Context())
+		enabled = flags.Enabled(r.Context(), "reports")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/students", nil)
+	req.Header.Set("X-User-ID", " user-7 ")
+	req.Header.Set("X-User-Roles", "teacher, staff,,")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if subject.Key != "user-7" || len(subject.Roles) != 2 || subject.Roles[1] != "staff" {
+		t.Errorf("subject = %+v", subject)
+	}
+	if !enabled {
+		t.Error("a flag targeting staff was off for a staff request")
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/students", nil))
+
+	if enabled || subject.Key != "" || subject.Roles != nil {
+		t.Errorf("without headers: subject = %+v, enabled %v", subject, enabled)
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	cfg := config.Security{
+		HSTSMaxAge:            time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentSecurityPolicy: "default-src 'none'",
+		FrameOptions:          "DENY",
+	}
+
+	notFound := http.HandlerFunc(http.NotFound)
+
+	rec := httptest.NewRecorder()
+	SecurityHeaders(cfg)(notFound).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"Content-Security-Policy":   "default-src 'none'",
+		"X-Frame-Options":           "DENY",
+		"Strict-Transport-Security": "max-age=3600; includeSubDomains",
+		"Referrer-Policy":           "",
+	}
+
+	for name, value := range want {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("%s = %q on an error response, want %q", name, got, value)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	SecurityHeaders(config.Security{})(ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("HSTS was set with a max age of zero")
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// Tenant resolves the tenant of every request and stores it in the request
+// context for the handlers and storage below. Unresolvable requests are
+// rejected here, as are requests whose API key was issued for another
+// tenant and requests over the tenant's rate limit. Sources such as the
+// subdomain are chosen by the client, so the key is what binds a request to
+// its school.
+func Tenant(resolver *tenant.Resolver, limiter *tenant.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t, err := resolver.Resolve(httpSource{r})
+			if err != nil {
+				response.WriteJson(w, tenantStatus(r, err), response.GeneralError(err))
+				return
+			}
+
+			if err := apikey.CheckTenant(r.Context(), t.Id); err != nil {
+				response.WriteJson(w, http.StatusForbidden, response.GeneralError(err))
+				return
+			}
+
+			if ok, retryAfter := limiter.Allow(t); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				response.WriteJson(w, http.StatusTooManyRequests, response.GeneralError(errors.New("rate limit exceeded")))
+				return
+			}
+
+			trace.FromContext(r.Context()).SetAttr("tenant", t.Id)
+
+			next.ServeHTTP(w, r.WithContext(tenant.WithTenant(r.Context(), t)))
+		})
+	}
+}
+
+func tenantStatus(r *http.Request, err error) int {
+	switch {
+	case errors.Is(err, tenant.ErrMissing):
+		return http.StatusBadRequest
+	case errors.Is(err, tenant.ErrUnknown):
+		return http.StatusNotFound
+	case errors.Is(err, tenant.ErrInvalid):
+		return http.StatusUnauthorized
+	}
+
+	slog.ErrorContext(r.Context(), "tenant resolution failed", slog.String("error", err.Error()))
+	return http.StatusInternalServerError
+}
+
+type httpSource struct {
+	r *http.Request
+}
+
+func (s httpSource) Header(name string) string {
+	return s.r.Header.Get(name)
+}
+
+func (s httpSource) Host() string {
+	if host, _, err := net.SplitHostPort(s.r.Host); err == nil {
+		return host
+	}
+	return s.r.Host
+}
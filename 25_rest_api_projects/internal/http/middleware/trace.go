@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bufio"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+)
+
+// RequestIdHeader is read from incoming requests and echoed on responses.
+const RequestIdHeader = "X-Request-ID"
+
+// Trace gives every request a request id and a server span, continuing
+// the trace of an incoming traceparent header, and logs the request once
+// it completes. With a nil tracer it still assigns request ids and logs.
+func Trace(tracer *trace.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestId := r.Header.Get(RequestIdHeader)
+			if requestId == "" || len(requestId) > 128 {
+				requestId = trace.NewRequestId()
+			}
+			w.Header().Set(RequestIdHeader, requestId)
+
+			ctx := trace.WithRequestId(r.Context(), requestId)
+
+			if remote, ok := trace.Extract(r.Header); ok {
+				ctx = trace.WithRemote(ctx, remote)
+			}
+
+			ctx, span := tracer.StartSpan(ctx, r.Method, trace.KindServer)
+			span.SetAttr("http.request.method", r.Method)
+			span.SetAttr("url.path", r.URL.Path)
+			span.SetAttr("request_id", requestId)
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			span.SetAttr("http.response.status_code", recorder.status)
+			if recorder.status >= 500 {
+				span.SetError(errorStatus(recorder.status))
+			}
+			span.End()
+
+			slog.LogAttrs(ctx, slog.LevelInfo, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", recorder.status),
+				slog.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
+
+// Route names the request span after the matched route pattern, which
+// is only known once the router has run.
+func Route(r *http.Request) {
+	if r.Pattern == "" {
+		return
+	}
+
+	span := trace.FromContext(r.Context())
+	span.SetName(r.Pattern)
+	span.SetAttr("http.route", r.Pattern)
+}
+
+type errorStatus int
+
+func (s errorStatus) Error() string {
+	return http.StatusText(int(s))
+}
+
+// statusRecorder remembers the response status. It passes flushing and
+// hijacking through so event streams and WebSockets keep working.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusRecorder) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.status = http.StatusSwitchingProtocols
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
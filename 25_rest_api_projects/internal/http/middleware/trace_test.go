@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+)
+
+func TestTraceContinuesIncomingTraces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	tracer, err := trace.New(config.Tracing{Enabled: true, Exporter: "file", File: path, ServiceName: "test", SampleRatio: 1, BatchSize: 10, FlushInterval: time.Hour, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer.Start()
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	var inner trace.SpanContext
+	handler := Trace(tracer)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inner = trace.FromContext(r.Context()).SpanContext()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/students", nil)
+	req.Header.Set("traceparent", parent)
+	req.Header.Set(RequestIdHeader, "req-1")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	tracer.Stop()
+
+	if rec.Header().Get(RequestIdHeader) != "req-1" {
+		t.Errorf("request id header = %q, want it echoed", rec.Header().Get(RequestIdHeader))
+	}
+
+	if inner.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || !inner.Sampled {
+		t.Errorf("handler span = %+v, want it in the incoming trace", inner)
+	}
+
+	exported, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"parent_id":"00f067aa0ba902b7"`, `"http.response.status_code":502`, `"error":"Bad Gateway"`} {
+		if !strings.Contains(string(exported), want) {
+			t.Errorf("exported span %s is missing %s", exported, want)
+		}
+	}
+}
+
+func TestTraceAssignsRequestIds(t *testing.T) {
+	handler := Trace(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIdHeader, strings.Repeat("x", 200))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if id := rec.Header().Get(RequestIdHeader); id == "" || len(id) > 128 {
+		t.Errorf("request id = %q, want a fresh one in place of the oversized header", id)
+	}
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/http/versioning"
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+// APIVersionHeader names the version a response was served as.
+const APIVersionHeader = "X-API-Version"
+
+var (
+	versionPrefix    = regexp.MustCompile(`^/v([0-9]+)(/|$)`)
+	versionMediaType = regexp.MustCompile(`^application/vnd\.students\.v([0-9]+)\+json$`)
+)
+
+// Version works out which API version a request is for: the one in a /v1
+// or /v2 prefix, or for /api paths the one the Accept header asks for,
+// falling back to the configured default. /api requests are rerouted to
+// that version's prefix, so the router only holds versioned routes.
+// Responses of deprecated versions carry Deprecation, Sunset and Link
+// headers.
+func Version(cfg config.Versioning) func(http.Handler) http.Handler {
+	deprecations := deprecationHeaders(cfg.Deprecations)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var v versioning.Version
+
+			if match := versionPrefix.FindStringSubmatch(r.URL.Path); match != nil {
+				n, _ := strconv.Atoi(match[1])
+				v = versioning.Version(n)
+
+				if !versioning.Supported(v) {
+					response.WriteJson(w, http.StatusNotFound, response.GeneralError(fmt.Errorf("unsupported API version %s", v)))
+					return
+				}
+			} else if rest, ok := strings.CutPrefix(r.URL.Path, versioning.Legacy); ok && (rest == "" || rest[0] == '/') {
+				w.Header().Add("Vary", "Accept")
+
+				negotiated, err := negotiateVersion(r.Header.Get("Accept"), versioning.Version(cfg.Default))
+				if err != nil {
+					response.WriteJson(w, http.StatusNotAcceptable, response.GeneralError(err))
+					return
+				}
+				v = negotiated
+
+				r = r.Clone(r.Context())
+				r.URL.Path = v.Prefix() + rest
+				if r.URL.RawPath != "" {
+					r.URL.RawPath = v.Prefix() + strings.TrimPrefix(r.URL.RawPath, versioning.Legacy)
+				}
+			} else {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(APIVersionHeader, strconv.Itoa(int(v)))
+			for name, values := range deprecations[v] {
+				w.Header()[name] = values
+			}
+
+			next.ServeHTTP(w, r.WithContext(versioning.WithVersion(r.Context(), v)))
+		})
+	}
+}
+
+// negotiateVersion picks the supported version with the highest quality
+// among the vendor media types in an Accept header. A header naming only
+// unsupported versions cannot be satisfied; one naming none gets fallback.
+func negotiateVersion(accept string, fallback versioning.Version) (versioning.Version, error) {
+	var best versioning.Version
+	bestQ, requested := 0.0, false
+
+	for _, item := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+
+		match := versionMediaType.FindStringSubmatch(mediaType)
+		if match == nil {
+			continue
+		}
+		requested = true
+
+		n, _ := strconv.Atoi(match[1])
+		v := versioning.Version(n)
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if versioning.Supported(v) && q > bestQ {
+			best, bestQ = v, q
+		}
+	}
+
+	switch {
+	case best != 0:
+		return best, nil
+	case requested:
+		return 0, fmt.Errorf("none of the requested API versions is supported, use %s", versioning.Latest.MediaType())
+	}
+
+	return fallback, nil
+}
+
+// deprecationHeaders prepares the headers of each deprecated version:
+// Deprecation as an RFC 9745 date, Sunset as an HTTP date and links to the
+// migration notes. Dates were checked by config validation; one that does
+// not parse is left out.
+func deprecationHeaders(deprecations map[int]config.Deprecation) map[versioning.Version]http.Header {
+	headers := map[versioning.Version]http.Header{}
+
+	for version, deprecation := range deprecations {
+		h := http.Header{}
+
+		if since, err := time.Parse(time.DateOnly, deprecation.Since); err == nil {
+			h.Set("Deprecation", "@"+strconv.FormatInt(since.Unix(), 10))
+		}
+
+		sunset, err := time.Parse(time.DateOnly, deprecation.Sunset)
+		if err == nil {
+			h.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+
+		if deprecation.Link != "" {
+			h.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", deprecation.Link))
+			if err == nil {
+				h.Add("Link", fmt.Sprintf("<%s>; rel=\"sunset\"", deprecation.Link))
+			}
+		}
+
+		headers[versioning.Version(version)] = h
+	}
+
+	return headers
+}
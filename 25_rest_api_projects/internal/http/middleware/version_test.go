@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/http/versioning"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   versioning.Version
+		ok     bool
+	}{
+		{"", versioning.V1, true},
+		{"application/json", versioning.V1, true},
+		{"application/vnd.students.v2+json", versioning.V2, true},
+		{"application/vnd.students.v1+json, application/vnd.students.v2+json;q=0.5", versioning.V1, true},
+		{"application/vnd.students.v9+json, application/vnd.students.v2+json;q=0.1", versioning.V2, true},
+		{"application/vnd.students.v9+json", 0, false},
+		{"application/vnd.students.v2+json;q=0", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := negotiateVersion(tt.accept, versioning.V1)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("negotiateVersion(%q) = %v, %v, want %v (ok %v)", tt.accept, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestVersionRoutesRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	versioning.Handle(mux, "GET /api/students/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s %s", versioning.FromContext(r.Context()), r.URL.Path, r.PathValue("id"))
+	}), nil)
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+
+	handler := Version(config.Versioning{
+		Default: 1,
+		Deprecations: map[int]config.Deprecation{
+			1: {Since: "2025-01-01", Sunset: "2026-01-01", Link: "https://docs.example.com/v2"},
+		},
+	})(mux)
+
+	tests := []struct {
+		path, accept string
+		status       int
+		body         string
+		version      string
+	}{
+		{"/v1/students/7", "", http.StatusOK, "v1 /v1/students/7 7", "1"},
+		{"/v2/students/7", "application/vnd.students.v1+json", http.StatusOK, "v2 /v2/students/7 7", "2"},
+		{"/api/students/7", "", http.StatusOK, "v1 /v1/students/7 7", "1"},
+		{"/api/students/7", "application/vnd.students.v2+json", http.StatusOK, "v2 /v2/students/7 7", "2"},
+		{"/api/students/7", "application/vnd.students.v3+json", http.StatusNotAcceptable, "", ""},
+		{"/v3/students/7", "", http.StatusNotFound, "", ""},
+		{"/health", "application/vnd.students.v2+json", http.StatusOK, "ok", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status || (tt.body != "" && rec.Body.String() != tt.body) || rec.Header().Get(APIVersionHeader) != tt.version {
+			t.Errorf("GET %s (%s) = %d %q version %q, want %d %q version %q", tt.path, tt.accept, rec.Code, rec.Body, rec.Header().Get(APIVersionHeader), tt.status, tt.body, tt.version)
+		}
+	}
+}
+
+func TestVersionHeaders(t *testing.T) {
+	handler := Version(config.Versioning{
+		Default: 2,
+		Deprecations: map[int]config.Deprecation{
+			1: {Since: "2025-01-01", Sunset: "2026-01-01", Link: "https://docs.example.com/v2"},
+		},
+	})(ok)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/students", nil))
+
+	want := map[string]string{
+		"Deprecation": "@1735689600",
+		"Sunset":      "Thu, 01 Jan 2026 00:00:00 GMT",
+	}
+	for name, value := range want {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if links := rec.Header().Values("Link"); len(links) != 2 || links[0] != `<https://docs.example.com/v2>; rel="deprecation"` {
+		t.Errorf("Link = %q", links)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/students", nil))
+
+	if rec.Header().Get(APIVersionHeader) != "2" || rec.Header().Get("Deprecation") != "" || rec.Header().Get("Vary") != "Accept" {
+		t.Errorf("current version headers = %v", rec.Header())
+	}
+}
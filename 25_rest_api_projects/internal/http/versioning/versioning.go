@@ -0,0 +1,174 @@
+// Package versioning lets several versions of the API share one router.
+// Routes are registered under a prefix per version, /v1 and /v2, and
+// handlers are written for the latest version; older versions get the
+// same handler with a transformer turning its response into the shape
+// they promised.
+package versioning
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+)
+
+type Version int
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+
+	Latest = V2
+)
+
+// Versions lists the supported versions, oldest first.
+var Versions = []Version{V1, V2}
+
+// Legacy is the unversioned prefix served as whichever version the
+// client negotiates.
+const Legacy = "/api"
+
+func Supported(v Version) bool {
+	return v >= V1 && v <= Latest
+}
+
+// Prefix is the path prefix of version v, "/v2" for V2.
+func (v Version) Prefix() string {
+	return "/v" + strconv.Itoa(int(v))
+}
+
+func (v Version) String() string {
+	return "v" + strconv.Itoa(int(v))
+}
+
+// MediaType is the Accept value that asks for version v on a legacy path.
+func (v Version) MediaType() string {
+	return "application/vnd.students." + v.String() + "+json"
+}
+
+type contextKey struct{}
+
+func WithVersion(ctx context.Context, v Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext returns the version a request is served as, the latest one
+// when it was never negotiated.
+func FromContext(ctx context.Context) Version {
+	if v, ok := ctx.Value(contextKey{}).(Version); ok {
+		return v
+	}
+	return Latest
+}
+
+// Transformer rewrites a JSON response body written by a latest-version
+// handler into the shape of an older version.
+type Transformer func(body []byte) ([]byte, error)
+
+// Transformers holds the transformer of each older version of a route.
+type Transformers map[Version]Transformer
+
+// Handle registers handler for pattern, an "/api/..." route, under the
+// prefix of every version, wrapped in the version's transformer when
+// transformers has one. Patterns outside /api are registered as they are.
+func Handle(mux *http.ServeMux, pattern string, handler http.Handler, transformers Transformers) {
+	if _, ok := versionedPath(pattern); !ok {
+		mux.Handle(pattern, handler)
+		return
+	}
+
+	for _, v := range Versions {
+		h := handler
+		if transform, ok := transformers[v]; ok {
+			h = Transform(transform, h)
+		}
+		HandleVersion(mux, v, pattern, h)
+	}
+}
+
+// HandleVersion registers handler for pattern, an "/api/..." route, for
+// version v only, for routes that were added, removed or rewritten there.
+func HandleVersion(mux *http.ServeMux, v Version, pattern string, handler http.Handler) {
+	method, path, _ := strings.Cut(pattern, " ")
+	if path == "" {
+		method, path = "", method
+	}
+
+	rest, ok := versionedPath(path)
+	if !ok {
+		panic(fmt.Sprintf("versioning: %q is not under %s", pattern, Legacy))
+	}
+
+	path = v.Prefix() + rest
+	if method != "" {
+		path = method + " " + path
+	}
+
+	mux.Handle(path, handler)
+}
+
+func versionedPath(pattern string) (string, bool) {
+	_, path, found := strings.Cut(pattern, " ")
+	if !found {
+		path = pattern
+	}
+
+	rest, ok := strings.CutPrefix(path, Legacy)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return "", false
+	}
+	return rest, true
+}
+
+// Transform runs next and passes its response through fn when it is a
+// successful JSON response, so error bodies, files and streams are left
+// alone. The response is buffered, so use it for plain JSON routes only.
+func Transform(fn Transformer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := &bufferedWriter{header: w.Header(), status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		body := buf.body.Bytes()
+
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if buf.status >= 200 && buf.status < 300 && mediaType == "application/json" && len(body) > 0 {
+			transformed, err := fn(body)
+			if err != nil {
+				response.WriteJson(w, http.StatusInternalServerError, response.GeneralError(fmt.Errorf("transforming response for %s: %w", FromContext(r.Context()), err)))
+				return
+			}
+			body = transformed
+			w.Header().Del("Content-Length")
+		}
+
+		w.WriteHeader(buf.status)
+		w.Write(body)
+	})
+}
+
+type bufferedWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status, w.wroteHeader = status, true
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
@@ -0,0 +1,106 @@
+package versioning
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleRegistersEveryVersion(t *testing.T) {
+	mux := http.NewServeMux()
+	Handle(mux, "GET /api/students", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`"v2"`))
+	}), Transformers{V1: func([]byte) ([]byte, error) { return []byte(`"v1"`), nil }})
+	Handle(mux, "GET /health", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}), nil)
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/v1/students", http.StatusOK, `"v1"`},
+		{"/v2/students", http.StatusOK, `"v2"`},
+		{"/health", http.StatusOK, "ok"},
+		{"/api/students", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.status || (tt.body != "" && rec.Body.String() != tt.body) {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.path, rec.Code, rec.Body, tt.status, tt.body)
+		}
+	}
+}
+
+func TestHandleVersionPanicsOutsideLegacy(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("HandleVersion accepted a route outside /api")
+		}
+	}()
+	HandleVersion(http.NewServeMux(), V1, "GET /apis/students", http.NotFoundHandler())
+}
+
+func TestTransformOnlySuccessfulJSON(t *testing.T) {
+	upper := func(body []byte) ([]byte, error) { return bytes.ToUpper(body), nil }
+
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		want        string
+	}{
+		{"json", http.StatusOK, "application/json", "BODY"},
+		{"json with charset", http.StatusCreated, "application/json; charset=utf-8", "BODY"},
+		{"error", http.StatusBadRequest, "application/json", "body"},
+		{"not json", http.StatusOK, "text/csv", "body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Transform(upper, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte("body"))
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/students", nil))
+
+			if rec.Code != tt.status || rec.Body.String() != tt.want {
+				t.Errorf("got %d %q, want %d %q", rec.Code, rec.Body, tt.status, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformError(t *testing.T) {
+	handler := Transform(func([]byte) ([]byte, error) { return nil, errors.New("bad shape") }, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/students", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if v := FromContext(context.Background()); v != Latest {
+		t.Errorf("FromContext without a version = %v, want %v", v, Latest)
+	}
+	if v := FromContext(WithVersion(context.Background(), V1)); v != V1 {
+		t.Errorf("FromContext = %v, want %v", v, V1)
+	}
+}
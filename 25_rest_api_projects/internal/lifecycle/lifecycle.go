@@ -0,0 +1,114 @@
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Status is the enrollment status a student carries through their time at
+// the school.
+type Status string
+
+const (
+	StatusApplicant Status = "applicant"
+	StatusEnrolled  Status = "enrolled"
+	StatusSuspended Status = "suspended"
+	StatusGraduated Status = "graduated"
+	StatusWithdrawn Status = "withdrawn"
+)
+
+// Initial is the status every new student starts in.
+const Initial = StatusApplicant
+
+var (
+	ErrUnknownEvent      = errors.New("unknown transition event")
+	ErrIllegalTransition = errors.New("illegal transition")
+)
+
+// transitions is the single source of truth for the lifecycle: from status,
+// event name, resulting status. It follows the shape of StateMachine[T] in
+// 19_Generics.
+var transitions = map[Status]map[string]Status{
+	StatusApplicant: {
+		"enroll":   StatusEnrolled,
+		"withdraw": StatusWithdrawn,
+	},
+	StatusEnrolled: {
+		"suspend":  StatusSuspended,
+		"graduate": StatusGraduated,
+		"withdraw": StatusWithdrawn,
+	},
+	StatusSuspended: {
+		"reinstate": StatusEnrolled,
+		"withdraw":  StatusWithdrawn,
+	},
+}
+
+func AllStatuses() []Status {
+	return []Status{StatusApplicant, StatusEnrolled, StatusSuspended, StatusGraduated, StatusWithdrawn}
+}
+
+func (s Status) IsValid() bool {
+	for _, status := range AllStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKnownEvent reports whether event appears anywhere in the table.
+func IsKnownEvent(event string) bool {
+	for _, events := range transitions {
+		if _, ok := events[event]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the status reached by applying event to from. Its errors
+// name the events that would have been accepted.
+func Next(from Status, event string) (Status, error) {
+	if !IsKnownEvent(event) {
+		return "", fmt.Errorf("%w: %s, expected one of %s", ErrUnknownEvent, event, strings.Join(allEvents(), ", "))
+	}
+
+	to, ok := transitions[from][event]
+	if !ok {
+		allowed := Events(from)
+		if len(allowed) == 0 {
+			return "", fmt.Errorf("%w: cannot %s a student who is %s, no further transitions are allowed", ErrIllegalTransition, event, from)
+		}
+		return "", fmt.Errorf("%w: cannot %s a student who is %s, allowed: %s", ErrIllegalTransition, event, from, strings.Join(allowed, ", "))
+	}
+
+	return to, nil
+}
+
+// allEvents lists every event in the table.
+func allEvents() []string {
+	var events []string
+	for _, from := range transitions {
+		for event := range from {
+			if !slices.Contains(events, event) {
+				events = append(events, event)
+			}
+		}
+	}
+	sort.Strings(events)
+	return events
+}
+
+// Events lists the events that are legal from the given status.
+func Events(from Status) []string {
+	events := make([]string, 0, len(transitions[from]))
+	for event := range transitions[from] {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
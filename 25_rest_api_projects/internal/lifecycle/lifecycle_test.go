@@ -0,0 +1,81 @@
+package lifecycle
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		from    Status
+		event   string
+		want    Status
+		wantErr error
+	}{
+		{StatusApplicant, "enroll", StatusEnrolled, nil},
+		{StatusApplicant, "withdraw", StatusWithdrawn, nil},
+		{StatusEnrolled, "suspend", StatusSuspended, nil},
+		{StatusEnrolled, "graduate", StatusGraduated, nil},
+		{StatusSuspended, "reinstate", StatusEnrolled, nil},
+		{StatusApplicant, "graduate", "", ErrIllegalTransition},
+		{StatusGraduated, "withdraw", "", ErrIllegalTransition},
+		{StatusEnrolled, "expel", "", ErrUnknownEvent},
+	}
+
+	for _, tt := range tests {
+		got, err := Next(tt.from, tt.event)
+
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Next(%s, %s) error = %v, want %v", tt.from, tt.event, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("Next(%s, %s) = %q, want %q", tt.from, tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestNextErrorsNameAllowedEvents(t *testing.T) {
+	_, err := Next(StatusApplicant, "graduate")
+	if err == nil || !strings.HasSuffix(err.Error(), "allowed: enroll, withdraw") {
+		t.Errorf("illegal transition error = %v, want the allowed events listed", err)
+	}
+
+	_, err = Next(StatusWithdrawn, "enroll")
+	if err == nil || !strings.Contains(err.Error(), "no further transitions") {
+		t.Errorf("transition from a final status error = %v", err)
+	}
+
+	_, err = Next(StatusEnrolled, "expel")
+	if err == nil || !strings.Contains(err.Error(), "expected one of enroll, graduate, reinstate, suspend, withdraw") {
+		t.Errorf("unknown event error = %v, want every event listed", err)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	if got, want := Events(StatusEnrolled), []string{"graduate", "suspend", "withdraw"}; !slices.Equal(got, want) {
+		t.Errorf("Events(enrolled) = %v, want %v", got, want)
+	}
+
+	for _, final := range []Status{StatusGraduated, StatusWithdrawn} {
+		if got := Events(final); len(got) != 0 {
+			t.Errorf("Events(%s) = %v, want none", final, got)
+		}
+	}
+}
+
+func TestEveryTransitionReachesAValidStatus(t *testing.T) {
+	for from, events := range transitions {
+		if !from.IsValid() {
+			t.Errorf("transitions has unknown status %q", from)
+		}
+		for event, to := range events {
+			if !to.IsValid() {
+				t.Errorf("%s --%s--> %q is not a valid status", from, event, to)
+			}
+		}
+	}
+}
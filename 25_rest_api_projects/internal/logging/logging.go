@@ -0,0 +1,102 @@
+// Package logging builds the server's slog logger from config.Logging.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+)
+
+// Redacted replaces the value of attributes named in config.Logging.Redact.
+const Redacted = "[REDACTED]"
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// New returns a logger writing to the configured output and a closer that
+// flushes and closes the log file, if any. Records logged with a context
+// carry its request and trace ids.
+func New(cfg config.Logging) (*slog.Logger, io.Closer, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, nil, fmt.Errorf("logging: %w", err)
+	}
+
+	var out io.Writer
+	var closer io.Closer = nopCloser{}
+
+	switch cfg.Output {
+	case "stderr":
+		out = os.Stderr
+	case "stdout":
+		out = os.Stdout
+	case "file":
+		file, err := OpenRotatingFile(cfg.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, closer = file, file
+	default:
+		return nil, nil, fmt.Errorf("logging: unknown output %q", cfg.Output)
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		AddSource:   cfg.AddSource,
+		ReplaceAttr: redact(cfg.Redact),
+	}
+
+	var handler slog.Handler
+	switch cfg.Format {
+	case "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		closer.Close()
+		return nil, nil, fmt.Errorf("logging: unknown format %q", cfg.Format)
+	}
+
+	return slog.New(trace.NewLogHandler(handler)), closer, nil
+}
+
+// redact hides the value of any attribute whose key contains one of keys,
+// ignoring case, so "password", "X-Api-Key" and "refresh_token" are caught
+// by "password", "api_key" and "token". Keys are compared with dashes read
+// as underscores.
+func redact(keys []string) func(groups []string, a slog.Attr) slog.Attr {
+	var normalized []string
+	for _, key := range keys {
+		if key = normalize(key); key != "" {
+			normalized = append(normalized, key)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindGroup {
+			return a
+		}
+
+		key := normalize(a.Key)
+		for _, sensitive := range normalized {
+			if strings.Contains(key, sensitive) {
+				return slog.String(a.Key, Redacted)
+			}
+		}
+		return a
+	}
+}
+
+func normalize(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+}
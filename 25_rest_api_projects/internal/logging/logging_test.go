@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+// logTo returns a JSON logger writing to a file and a function reading the
+// records written so far.
+func logTo(t *testing.T, cfg config.Logging) (*slog.Logger, func() []map[string]any) {
+	t.Helper()
+
+	cfg.Format = "json"
+	cfg.Output = "file"
+	cfg.File = config.LogFile{Path: filepath.Join(t.TempDir(), "test.log")}
+
+	logger, closer, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { closer.Close() })
+
+	return logger, func() []map[string]any {
+		t.Helper()
+
+		data, err := os.ReadFile(cfg.File.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" {
+				continue
+			}
+			var record map[string]any
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("decoding %q: %v", line, err)
+			}
+			records = append(records, record)
+		}
+		return records
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	logger, records := logTo(t, config.Logging{Level: "info", Redact: []string{"password", "api-key", " Token "}})
+
+	logger.Info("login",
+		slog.String("user", "ada"),
+		slog.String("Password", "hunter2"),
+		slog.String("X-Api-Key", "sk_123"),
+		slog.String("refresh_token", "abc"),
+		slog.Group("request", slog.String("api_key", "sk_456"), slog.String("path", "/api/students")),
+	)
+
+	logged := records()
+	if len(logged) != 1 {
+		t.Fatalf("records = %v", logged)
+	}
+	record := logged[0]
+
+	for _, key := range []string{"Password", "X-Api-Key", "refresh_token"} {
+		if record[key] != Redacted {
+			t.Errorf("%s = %v, want it redacted", key, record[key])
+		}
+	}
+
+	request, _ := record["request"].(map[string]any)
+	if request["api_key"] != Redacted || request["path"] != "/api/students" {
+		t.Errorf("request group = %v, want only the key redacted", request)
+	}
+
+	if record["user"] != "ada" || record["msg"] != "login" {
+		t.Errorf("record = %v, want other attributes kept", record)
+	}
+}
+
+func TestNothingIsRedactedWithoutKeys(t *testing.T) {
+	logger, records := logTo(t, config.Logging{Level: "info"})
+
+	logger.Info("login", slog.String("password", "hunter2"))
+
+	if got := records()[0]["password"]; got != "hunter2" {
+		t.Errorf("password = %v with no redact keys", got)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	logger, records := logTo(t, config.Logging{Level: "warn"})
+
+	logger.Info("dropped")
+	logger.WarnContext(context.Background(), "kept")
+
+	logged := records()
+	if len(logged) != 1 || logged[0]["msg"] != "kept" {
+		t.Errorf("records = %v, want only the warning", logged)
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	for _, cfg := range []config.Logging{
+		{Level: "loud", Format: "text", Output: "stderr"},
+		{Level: "info", Format: "xml", Output: "stderr"},
+		{Level: "info", Format: "text", Output: "syslog"},
+		{Level: "info", Format: "text", Output: "file"},
+	} {
+		if _, _, err := New(cfg); err == nil {
+			t.Errorf("New(%+v) succeeded", cfg)
+		}
+	}
+}
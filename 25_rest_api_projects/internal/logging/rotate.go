@@ -0,0 +1,307 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+// backupLayout is the timestamp in rotated file names, which sorts in the
+// order the files were rotated.
+const backupLayout = "20060102T150405.000Z"
+
+// RotatingFile is a log file that is moved aside once it would grow past
+// the size limit or an interval boundary passes. "students-api.log" becomes
+// "students-api-20261019T120000.000Z.log", gzipped in the background to
+// ".log.gz" when compression is on, and the oldest backups beyond the limit
+// are removed.
+type RotatingFile struct {
+	cfg config.LogFile
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	period time.Time
+	last   time.Time // stamp of the latest backup
+
+	// background runs one compression and prune at a time.
+	background sync.Mutex
+	wg         sync.WaitGroup
+}
+
+// OpenRotatingFile opens or creates the log file, appending to what an
+// earlier run wrote.
+func OpenRotatingFile(cfg config.LogFile) (*RotatingFile, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("logging: file path is required")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("logging: %w", err)
+	}
+
+	f := &RotatingFile{cfg: cfg}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	// A file left by an earlier run belongs to the period it was last
+	// written in, so a restart on the next day still rotates it.
+	if info, err := f.file.Stat(); err == nil && f.size > 0 {
+		f.period = f.periodOf(info.ModTime())
+	}
+
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.cfg.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("logging: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("logging: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.period = f.periodOf(time.Now())
+	return nil
+}
+
+// periodOf is the start of the rotation interval t falls in.
+func (f *RotatingFile) periodOf(t time.Time) time.Time {
+	if f.cfg.Interval <= 0 {
+		return time.Time{}
+	}
+	return t.UTC().Truncate(f.cfg.Interval)
+}
+
+// Write appends p, rotating first when p would take the file past the size
+// limit or a new interval has begun. A single record larger than the limit
+// is still written whole.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.due(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			// Keep logging to the current file rather than losing records.
+			fmt.Fprintf(os.Stderr, "logging: rotating %s: %v\n", f.cfg.Path, err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) due(next int64) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	if f.cfg.MaxSize > 0 && f.size+next > f.cfg.MaxSize {
+		return true
+	}
+
+	return f.cfg.Interval > 0 && !f.periodOf(time.Now()).Equal(f.period)
+}
+
+// rotate moves the current file aside and starts a new one. Compression and
+// pruning run in the background so logging is not held up.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	backup := f.backupName(time.Now())
+
+	if err := os.Rename(f.cfg.Path, backup); err != nil {
+		if reopenErr := f.open(); reopenErr != nil {
+			f.file = nil
+			return reopenErr
+		}
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		f.file = nil
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		f.background.Lock()
+		defer f.background.Unlock()
+
+		if f.cfg.Compress {
+			if err := compress(backup); err != nil {
+				slog.Error("logging: compressing rotated file", slog.String("path", backup), slog.String("error", err.Error()))
+			}
+		}
+
+		if err := f.prune(); err != nil {
+			slog.Error("logging: removing old log files", slog.String("error", err.Error()))
+		}
+	}()
+
+	return nil
+}
+
+// backupName names the file rotated at t. Should two rotations land in the
+// same millisecond, the later one is stamped a millisecond on, so names
+// still sort in rotation order for prune.
+func (f *RotatingFile) backupName(t time.Time) string {
+	base, ext := f.split()
+
+	t = t.UTC().Truncate(time.Millisecond)
+	if !t.After(f.last) {
+		t = f.last.Add(time.Millisecond)
+	}
+
+	for {
+		candidate := base + "-" + t.Format(backupLayout) + ext
+		if !exists(candidate) && !exists(candidate+".gz") {
+			f.last = t
+			return candidate
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func (f *RotatingFile) split() (base string, ext string) {
+	ext = filepath.Ext(f.cfg.Path)
+	return strings.TrimSuffix(f.cfg.Path, ext), ext
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// prune removes all but the newest MaxBackups rotated files.
+func (f *RotatingFile) prune() error {
+	if f.cfg.MaxBackups <= 0 {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	base, _ := f.split()
+	prefix := filepath.Base(base) + "-"
+	dir := filepath.Dir(f.cfg.Path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		rest, ok := strings.CutPrefix(entry.Name(), prefix)
+		if entry.IsDir() || !ok || len(rest) < len(backupLayout) {
+			continue
+		}
+
+		if _, err := time.Parse(backupLayout, rest[:len(backupLayout)]); err != nil {
+			continue
+		}
+
+		backups = append(backups, entry.Name())
+	}
+
+	slices.Sort(backups)
+
+	var errs []error
+	for _, name := range backups[:max(0, len(backups)-f.cfg.MaxBackups)] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// compress gzips path to path.gz and removes path. The .gz file only
+// appears once complete. A file pruned before its turn came is skipped.
+func compress(path string) error {
+	src, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".compress-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := gzip.NewWriter(tmp)
+	zw.Name = filepath.Base(path)
+
+	if _, err := io.Copy(zw, src); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path+".gz"); err != nil {
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
+
+// Close closes the file and waits for rotated files to be compressed.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.wg.Wait()
+	return err
+}
@@ -0,0 +1,188 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+// rotated returns the rotated files next to path, oldest first.
+func rotated(t *testing.T, path string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(strings.TrimSuffix(path, ".log") + "-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(matches)
+	return matches
+}
+
+// read returns the content of a log file, gunzipping it if needed.
+func read(t *testing.T, path string) string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		r = zr
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func line(i int) []byte {
+	return []byte(fmt.Sprintf("record %02d %s\n", i, strings.Repeat("x", 20)))
+}
+
+func TestRotatesBySizeAndKeepsEveryRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+
+	f, err := OpenRotatingFile(config.LogFile{Path: path, MaxSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want strings.Builder
+	for i := range 10 {
+		if _, err := f.Write(line(i)); err != nil {
+			t.Fatal(err)
+		}
+		want.Write(line(i))
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups := rotated(t, path)
+	if len(backups) != 3 {
+		t.Fatalf("backups = %v, want 3 for 10 records of 30 bytes", backups)
+	}
+
+	var got strings.Builder
+	for _, backup := range backups {
+		content := read(t, backup)
+		if len(content) > 100 {
+			t.Errorf("%s is %d bytes, over the limit", backup, len(content))
+		}
+		got.WriteString(content)
+	}
+	got.WriteString(read(t, path))
+
+	if got.String() != want.String() {
+		t.Errorf("records across files = %q, want %q", got.String(), want.String())
+	}
+
+	if _, err := f.Write(line(0)); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func TestOversizedRecordIsWrittenWhole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := OpenRotatingFile(config.LogFile{Path: path, MaxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if content := read(t, path); content != string(line(1)) || len(rotated(t, path)) != 0 {
+		t.Errorf("content = %q, backups %v, want the record whole in the empty file", content, rotated(t, path))
+	}
+}
+
+func TestCompressesAndPrunesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := OpenRotatingFile(config.LogFile{Path: path, MaxSize: 40, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 6 {
+		if _, err := f.Write(line(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups := rotated(t, path)
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want the newest 2", backups)
+	}
+
+	for i, backup := range backups {
+		if !strings.HasSuffix(backup, ".log.gz") {
+			t.Errorf("%s was not compressed", backup)
+			continue
+		}
+		if got, want := read(t, backup), string(line(3+i)); got != want {
+			t.Errorf("%s = %q, want %q", backup, got, want)
+		}
+	}
+}
+
+func TestRotatesAtIntervalBoundaries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	// A file last written yesterday is rotated on the first write today,
+	// even across a restart.
+	if err := os.WriteFile(path, line(0), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	yesterday := time.Now().Add(-24 * time.Hour)
+	if err := os.Chtimes(path, yesterday, yesterday); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenRotatingFile(config.LogFile{Path: path, Interval: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Write(line(1))
+	f.Write(line(2))
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups := rotated(t, path)
+	if len(backups) != 1 || read(t, backups[0]) != string(line(0)) {
+		t.Fatalf("backups = %v, want yesterday's file", backups)
+	}
+
+	if got, want := read(t, path), string(line(1))+string(line(2)); got != want {
+		t.Errorf("current file = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,233 @@
+package mail
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/webhook"
+)
+
+const (
+	pollInterval = 5 * time.Second
+	batchSize    = 50
+)
+
+// The messages the dispatcher sends, by template name.
+const (
+	Enrolled     = "enrolled"
+	Transitioned = "transitioned"
+)
+
+// Store is the mail queue. QueueMail ignores a message already queued for
+// the same event and recipient; DueMails spans all tenants.
+type Store interface {
+	QueueMail(mail types.Mail) (bool, error)
+	DueMails(now time.Time, limit int) ([]types.Mail, error)
+	UpdateMail(mail types.Mail) error
+}
+
+// Directory looks up the records a message is about in the tenant the
+// event came from.
+type Directory interface {
+	Student(tenant string, id int64) (types.Student, error)
+	Course(tenant string, id int64) (types.Course, error)
+	School(tenant string) string
+}
+
+// Dispatcher queues a rendered message when a student enrols or changes
+// status and a background loop sends queued messages through the mailer.
+type Dispatcher struct {
+	store     Store
+	directory Directory
+	templates *Templates
+	mailer    Mailer
+	cfg       config.Mail
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(store Store, directory Directory, templates *Templates, mailer Mailer, cfg config.Mail) *Dispatcher {
+	return &Dispatcher{
+		store:     store,
+		directory: directory,
+		templates: templates,
+		mailer:    mailer,
+		cfg:       cfg,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Enqueue implements events.Queue. Loading and queueing errors are returned
+// so the event is offered again; a message already queued for the event is
+// not queued twice. Events that can never produce a message, because the
+// student or course is gone or the template fails, are logged and dropped.
+func (d *Dispatcher) Enqueue(event events.Event) error {
+	tenantId := cmp.Or(event.Tenant, tenant.Default)
+	data := Data{School: d.directory.School(tenantId)}
+
+	var name string
+	var err error
+
+	switch event.Type {
+	case events.StudentEnrolled:
+		name = Enrolled
+		data.Course, err = d.directory.Course(tenantId, event.CourseId)
+	case events.StudentTransitioned:
+		name = Transitioned
+		if err := decode(event.Data, &data.Transition); err != nil {
+			slog.Error("mail: decoding event data", slog.String("event_id", event.Id), slog.String("error", err.Error()))
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if err == nil {
+		data.Student, err = d.directory.Student(tenantId, event.StudentId)
+	}
+
+	if errors.Is(err, storage.ErrNotFound) {
+		slog.Warn("mail: skipping event for a deleted record", slog.String("event_id", event.Id), slog.String("error", err.Error()))
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("mail: loading message data for event %s: %w", event.Id, err)
+	}
+
+	subject, text, html, err := d.templates.Render(name, data)
+	if err != nil {
+		slog.Error("mail: rendering message", slog.String("template", name), slog.String("error", err.Error()))
+		return nil
+	}
+
+	queued, err := d.store.QueueMail(types.Mail{
+		Tenant:        tenantId,
+		EventId:       event.Id,
+		Template:      name,
+		Recipient:     data.Student.Email,
+		Subject:       subject,
+		Text:          text,
+		HTML:          html,
+		NextAttemptAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("mail: queueing message for event %s: %w", event.Id, err)
+	}
+
+	if queued {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// decode converts event data, a struct when published in process and a map
+// after a trip through the outbox, into v.
+func decode(data any, v any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.run(ctx)
+	}()
+}
+
+// Stop ends the loop and waits for a send in progress to finish.
+func (d *Dispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.sendDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+func (d *Dispatcher) sendDue(ctx context.Context) {
+	mails, err := d.store.DueMails(time.Now().UTC(), batchSize)
+	if err != nil {
+		slog.Error("mail: loading due messages", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, mail := range mails {
+		if ctx.Err() != nil {
+			return
+		}
+		d.attempt(ctx, mail)
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, mail types.Mail) {
+	err := d.mailer.Send(ctx, Message{
+		From:    d.cfg.From,
+		To:      []string{mail.Recipient},
+		Subject: mail.Subject,
+		Text:    mail.Text,
+		HTML:    mail.HTML,
+	})
+
+	mail.Attempts++
+
+	if err == nil {
+		now := time.Now().UTC()
+		mail.Status = types.MailSent
+		mail.SentAt = &now
+		mail.LastError = ""
+		slog.Info("mail sent", slog.Int64("mail_id", mail.Id), slog.String("template", mail.Template))
+	} else {
+		mail.LastError = err.Error()
+
+		if mail.Attempts >= d.cfg.MaxAttempts {
+			mail.Status = types.MailFailed
+			slog.Error("mail failed, giving up", slog.Int64("mail_id", mail.Id), slog.String("error", err.Error()))
+		} else {
+			mail.NextAttemptAt = time.Now().UTC().Add(webhook.Backoff(mail.Attempts, d.cfg.BaseBackoff, d.cfg.MaxBackoff))
+			slog.Warn("mail send failed, will retry", slog.Int64("mail_id", mail.Id), slog.Int("attempts", mail.Attempts), slog.String("error", err.Error()))
+		}
+	}
+
+	if err := d.store.UpdateMail(mail); err != nil {
+		slog.Error("mail: saving message", slog.Int64("mail_id", mail.Id), slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,177 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+type queue struct {
+	mails []types.Mail
+	err   error
+}
+
+func (q *queue) QueueMail(mail types.Mail) (bool, error) {
+	if q.err != nil {
+		return false, q.err
+	}
+	for _, queued := range q.mails {
+		if queued.EventId == mail.EventId && queued.Recipient == mail.Recipient {
+			return false, nil
+		}
+	}
+	mail.Id = int64(len(q.mails) + 1)
+	q.mails = append(q.mails, mail)
+	return true, nil
+}
+
+func (q *queue) DueMails(now time.Time, limit int) ([]types.Mail, error) {
+	return nil, nil
+}
+
+func (q *queue) UpdateMail(mail types.Mail) error {
+	q.mails[mail.Id-1] = mail
+	return nil
+}
+
+type directory struct {
+	err error
+}
+
+func (d directory) Student(tenant string, id int64) (types.Student, error) {
+	if d.err != nil {
+		return types.Student{}, d.err
+	}
+	if id != 1 {
+		return types.Student{}, storage.ErrNotFound
+	}
+	return types.Student{Id: 1, Name: "Ada", Email: "ada@example.edu"}, nil
+}
+
+func (d directory) Course(tenant string, id int64) (types.Course, error) {
+	return types.Course{Id: id, Code: "CS101", Title: "Programming", Credits: 3}, nil
+}
+
+func (d directory) School(tenant string) string {
+	return "School of " + tenant
+}
+
+type mailer struct {
+	mu   sync.Mutex
+	sent []Message
+	err  error
+}
+
+func (m *mailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var mailConfig = config.Mail{From: "no-reply@school.test", MaxAttempts: 2, BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+
+func newDispatcher(t *testing.T, q *queue, dir directory, m *mailer) *Dispatcher {
+	t.Helper()
+
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewDispatcher(q, dir, templates, m, mailConfig)
+}
+
+func TestEnqueueRendersAndQueuesOnce(t *testing.T) {
+	q := &queue{}
+	d := newDispatcher(t, q, directory{}, &mailer{})
+
+	enrolled := events.Event{Id: "evt-1", Type: events.StudentEnrolled, Tenant: "north", StudentId: 1, CourseId: 7}
+	transitioned := events.New(events.StudentTransitioned, 1, types.StudentTransition{From: lifecycle.StatusApplicant, To: lifecycle.StatusEnrolled})
+
+	for _, event := range []events.Event{enrolled, enrolled, transitioned, events.New(events.StudentUpdated, 1, nil)} {
+		if err := d.Enqueue(event); err != nil {
+			t.Fatalf("Enqueue(%s): %v", event.Type, err)
+		}
+	}
+
+	if len(q.mails) != 2 {
+		t.Fatalf("queued %d messages, want one per mail event", len(q.mails))
+	}
+
+	first := q.mails[0]
+	if first.Tenant != "north" || first.Template != Enrolled || first.Recipient != "ada@example.edu" || first.Subject != "You are enrolled in CS101" {
+		t.Errorf("enrolled mail = %+v", first)
+	}
+	if !strings.Contains(first.Text, "School of north") || first.HTML == "" {
+		t.Errorf("enrolled mail body = %q, html %q", first.Text, first.HTML)
+	}
+
+	if q.mails[1].Subject != "Your student status is now enrolled" {
+		t.Errorf("transitioned subject = %q", q.mails[1].Subject)
+	}
+}
+
+func TestEnqueueErrors(t *testing.T) {
+	gone := events.Event{Id: "evt-1", Type: events.StudentEnrolled, StudentId: 2, CourseId: 7}
+	if err := newDispatcher(t, &queue{}, directory{}, &mailer{}).Enqueue(gone); err != nil {
+		t.Errorf("event for a deleted student = %v, want it dropped", err)
+	}
+
+	event := events.Event{Id: "evt-2", Type: events.StudentEnrolled, StudentId: 1, CourseId: 7}
+
+	if err := newDispatcher(t, &queue{}, directory{err: errors.New("database is locked")}, &mailer{}).Enqueue(event); err == nil {
+		t.Error("a failed lookup was dropped, want it returned so the event is retried")
+	}
+
+	if err := newDispatcher(t, &queue{err: errors.New("disk full")}, directory{}, &mailer{}).Enqueue(event); err == nil {
+		t.Error("a failed queue write was dropped, want it returned so the event is retried")
+	}
+}
+
+func TestAttemptRetriesThenGivesUp(t *testing.T) {
+	q := &queue{}
+	m := &mailer{err: errors.New("connection refused")}
+	d := newDispatcher(t, q, directory{}, m)
+
+	if err := d.Enqueue(events.Event{Id: "evt-1", Type: events.StudentEnrolled, StudentId: 1, CourseId: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now().UTC()
+	d.attempt(context.Background(), q.mails[0])
+
+	if mail := q.mails[0]; mail.Attempts != 1 || mail.Status == types.MailFailed || !mail.NextAttemptAt.After(before) || mail.LastError == "" {
+		t.Errorf("after one failure = %+v, want a retry scheduled", mail)
+	}
+
+	d.attempt(context.Background(), q.mails[0])
+
+	if mail := q.mails[0]; mail.Attempts != 2 || mail.Status != types.MailFailed {
+		t.Errorf("after MaxAttempts failures = %+v, want failed", mail)
+	}
+
+	m.err = nil
+	q.mails[0].Status = types.MailPending
+	d.attempt(context.Background(), q.mails[0])
+
+	if mail := q.mails[0]; mail.Status != types.MailSent || mail.SentAt == nil || mail.LastError != "" {
+		t.Errorf("after a successful send = %+v", mail)
+	}
+
+	if len(m.sent) != 1 || m.sent[0].From != mailConfig.From || m.sent[0].To[0] != "ada@example.edu" {
+		t.Errorf("sent = %+v", m.sent)
+	}
+}
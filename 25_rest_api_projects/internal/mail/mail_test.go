@@ -0,0 +1,191 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+var message = Message{
+	From:    "Students API <no-reply@school.test>",
+	To:      []string{"Ada Lovelace <ada@example.edu>"},
+	Subject: "Bienvenue à l'école",
+	Text:    "Hello Ada,\n\nYou are now enrolled in CS101 – a line long enough that quoted-printable has to wrap it somewhere along the way.\n",
+}
+
+func parse(t *testing.T, raw []byte) *mail.Message {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("reading encoded message: %v\n%s", err, raw)
+	}
+	return msg
+}
+
+// text decodes a quoted-printable body, turning its CRLF line breaks back
+// into the newlines of the message.
+func text(r io.Reader) string {
+	body, _ := io.ReadAll(quotedprintable.NewReader(r))
+	return strings.ReplaceAll(string(body), "\r\n", "\n")
+}
+
+func TestEncodeTextOnly(t *testing.T) {
+	raw, err := Encode(message)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	msg := parse(t, raw)
+
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != message.Subject {
+		t.Errorf("Subject = %q, want %q", subject, message.Subject)
+	}
+	if msg.Header.Get("To") != `"Ada Lovelace" <ada@example.edu>` || !strings.HasSuffix(msg.Header.Get("Message-Id"), "@school.test>") {
+		t.Errorf("headers = %v", msg.Header)
+	}
+	if msg.Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", msg.Header.Get("Content-Type"))
+	}
+
+	if body := text(msg.Body); body != message.Text {
+		t.Errorf("body = %q, want %q", body, message.Text)
+	}
+}
+
+func TestEncodeWithHTML(t *testing.T) {
+	withHTML := message
+	withHTML.HTML = "<p>Hello <strong>Ada</strong></p>"
+
+	raw, err := Encode(withHTML)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	msg := parse(t, raw)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", withHTML.Text},
+		{"text/html; charset=utf-8", withHTML.HTML},
+	} {
+		part, err := parts.NextRawPart()
+		if err != nil {
+			t.Fatalf("reading part: %v", err)
+		}
+
+		body := text(part)
+		if part.Header.Get("Content-Type") != want.contentType || body != want.body {
+			t.Errorf("part = %q %q, want %q %q", part.Header.Get("Content-Type"), body, want.contentType, want.body)
+		}
+	}
+
+	if _, err := parts.NextPart(); err != io.EOF {
+		t.Errorf("after two parts: %v, want EOF", err)
+	}
+}
+
+func TestEncodeRejectsBadAddresses(t *testing.T) {
+	bad := message
+	bad.From = "not an address"
+	if _, err := Encode(bad); err == nil {
+		t.Error("Encode accepted a bad sender")
+	}
+
+	bad = message
+	bad.To = []string{"ada@example.edu", "@"}
+	if _, err := Encode(bad); err == nil {
+		t.Error("Encode accepted a bad recipient")
+	}
+}
+
+func TestSMTPDeliversToTheSink(t *testing.T) {
+	sink, err := NewSink("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+
+	host, port, _ := net.SplitHostPort(sink.Addr())
+	portNumber, _ := strconv.Atoi(port)
+
+	smtp := NewSMTP(config.SMTP{Host: host, Port: portNumber, Username: "user", Password: "secret", TLS: "none", Timeout: 5 * time.Second})
+
+	if err := smtp.Send(context.Background(), message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	captured := sink.Messages()
+	if len(captured) != 1 {
+		t.Fatalf("sink has %d messages, want 1", len(captured))
+	}
+
+	got := captured[0]
+	if got.From != "no-reply@school.test" || len(got.To) != 1 || got.To[0] != "ada@example.edu" || got.Subject != message.Subject {
+		t.Errorf("captured = %+v", got)
+	}
+
+	if body := text(parse(t, []byte(got.Raw)).Body); body != message.Text {
+		t.Errorf("captured body = %q", body)
+	}
+}
+
+func TestSMTPRequiresStartTLSWhenAsked(t *testing.T) {
+	sink, err := NewSink("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+
+	host, port, _ := net.SplitHostPort(sink.Addr())
+	portNumber, _ := strconv.Atoi(port)
+
+	smtp := NewSMTP(config.SMTP{Host: host, Port: portNumber, TLS: "starttls", Timeout: 5 * time.Second})
+
+	if err := smtp.Send(context.Background(), message); err != ErrNoStartTLS {
+		t.Errorf("Send without STARTTLS = %v, want ErrNoStartTLS", err)
+	}
+	if len(sink.Messages()) != 0 {
+		t.Error("the message was sent in the clear")
+	}
+}
+
+func TestFileWritesEmlFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+
+	file, err := NewFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := file.Send(context.Background(), message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(matches) != 1 || filepath.Ext(matches[0]) != ".eml" {
+		t.Fatalf("files = %v, want one .eml file", matches)
+	}
+
+	raw, _ := os.ReadFile(matches[0])
+	parse(t, raw)
+}
@@ -0,0 +1,252 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sinkKeep    = 200
+	sinkMaxSize = 10 << 20
+)
+
+// Captured is a message received by a Sink.
+type Captured struct {
+	Id         int       `json:"id"`
+	From       string    `json:"from"`
+	To         []string  `json:"to"`
+	Subject    string    `json:"subject"`
+	ReceivedAt time.Time `json:"received_at"`
+	Raw        string    `json:"raw"`
+}
+
+// Sink is a small SMTP server that accepts every message and keeps the
+// latest ones in memory, standing in for a real mail server in development
+// and tests. It offers no TLS, so point the smtp transport at it with tls
+// "none"; AUTH PLAIN is accepted with any credentials.
+type Sink struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []Captured
+	next     int
+	conns    map[net.Conn]struct{}
+
+	wg sync.WaitGroup
+}
+
+// NewSink starts listening on addr and serving in the background.
+func NewSink(addr string) (*Sink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("mail sink: %w", err)
+	}
+
+	s := &Sink{listener: listener, conns: map[net.Conn]struct{}{}}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.serve()
+	}()
+
+	return s, nil
+}
+
+// Addr is the address the sink listens on, useful with port 0.
+func (s *Sink) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops accepting connections, drops open ones and waits for their
+// sessions to end.
+func (s *Sink) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// Messages returns the captured messages, newest first.
+func (s *Sink) Messages() []Captured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]Captured, len(s.messages))
+	for i, msg := range s.messages {
+		messages[len(s.messages)-1-i] = msg
+	}
+	return messages
+}
+
+func (s *Sink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Error("mail sink: accept", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.session(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+// session speaks just enough SMTP for net/smtp and common clients.
+func (s *Sink) session(conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(5 * time.Minute))
+
+	text := textproto.NewConn(conn)
+	reply := func(format string, args ...any) bool {
+		return text.PrintfLine(format, args...) == nil
+	}
+
+	if !reply("220 localhost students-api mail sink") {
+		return
+	}
+
+	var from string
+	var to []string
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			reply("250 localhost")
+		case "EHLO":
+			reply("250-localhost")
+			reply("250-8BITMIME")
+			reply("250-AUTH PLAIN")
+			reply("250 SIZE %d", sinkMaxSize)
+		case "AUTH":
+			// "AUTH PLAIN" without the credentials asks for them.
+			if strings.EqualFold(strings.TrimSpace(arg), "PLAIN") {
+				reply("334 ")
+				if _, err := text.ReadLine(); err != nil {
+					return
+				}
+			}
+			reply("235 authenticated")
+		case "MAIL":
+			from, to = path(arg), nil
+			reply("250 ok")
+		case "RCPT":
+			to = append(to, path(arg))
+			reply("250 ok")
+		case "DATA":
+			if from == "" || len(to) == 0 {
+				reply("503 need MAIL and RCPT first")
+				continue
+			}
+
+			reply("354 end data with <CR><LF>.<CR><LF>")
+
+			dot := text.DotReader()
+			raw, err := io.ReadAll(io.LimitReader(dot, sinkMaxSize+1))
+			if err != nil {
+				return
+			}
+			if _, err := io.Copy(io.Discard, dot); err != nil {
+				return
+			}
+			if len(raw) > sinkMaxSize {
+				reply("552 message too large")
+				continue
+			}
+
+			id := s.store(from, to, raw)
+			reply("250 ok queued as %d", id)
+			from, to = "", nil
+		case "RSET":
+			from, to = "", nil
+			reply("250 ok")
+		case "NOOP":
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func (s *Sink) store(from string, to []string, raw []byte) int {
+	var subject string
+	if msg, err := mail.ReadMessage(bytes.NewReader(raw)); err == nil {
+		subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		if err != nil {
+			subject = msg.Header.Get("Subject")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	s.messages = append(s.messages, Captured{
+		Id:         s.next,
+		From:       from,
+		To:         to,
+		Subject:    subject,
+		ReceivedAt: time.Now().UTC(),
+		Raw:        string(raw),
+	})
+
+	if len(s.messages) > sinkKeep {
+		s.messages = s.messages[len(s.messages)-sinkKeep:]
+	}
+
+	return s.next
+}
+
+// path extracts the address from "FROM:<a@b.c> SIZE=123".
+func path(arg string) string {
+	_, rest, _ := strings.Cut(arg, ":")
+	rest = strings.TrimSpace(rest)
+
+	if start := strings.Index(rest, "<"); start >= 0 {
+		if end := strings.Index(rest[start:], ">"); end >= 0 {
+			return rest[start+1 : start+end]
+		}
+	}
+
+	address, _, _ := strings.Cut(rest, " ")
+	return address
+}
@@ -0,0 +1,101 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// Data is what templates are executed with. Course is set for enrollment
+// mail and Transition for status change mail.
+type Data struct {
+	School     string
+	Student    types.Student
+	Course     types.Course
+	Transition types.StudentTransition
+}
+
+// Templates renders a message named "enrolled" from enrolled.txt.tmpl,
+// which also defines "enrolled.subject", and the optional
+// enrolled.html.tmpl. HTML is escaped by html/template.
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// LoadTemplates parses the *.tmpl files of dir, or the built-in templates
+// when dir is empty.
+func LoadTemplates(dir string) (*Templates, error) {
+	var fsys fs.FS = builtin
+	pattern := "templates/*.tmpl"
+
+	if dir != "" {
+		fsys, pattern = os.DirFS(dir), "*.tmpl"
+	}
+
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{
+		text: texttemplate.New("mail").Option("missingkey=error"),
+		html: htmltemplate.New("mail").Option("missingkey=error"),
+	}
+
+	for _, name := range matches {
+		var err error
+		if strings.HasSuffix(name, ".html.tmpl") {
+			_, err = t.html.ParseFS(fsys, name)
+		} else {
+			_, err = t.text.ParseFS(fsys, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mail templates: %w", err)
+		}
+	}
+
+	for _, name := range []string{Enrolled, Transitioned} {
+		if t.text.Lookup(name+".txt.tmpl") == nil || t.text.Lookup(name+".subject") == nil {
+			return nil, fmt.Errorf("mail templates: %s.txt.tmpl defining %q is required", name, name+".subject")
+		}
+	}
+
+	return t, nil
+}
+
+// Render executes the templates of message name.
+func (t *Templates) Render(name string, data Data) (subject string, text string, html string, err error) {
+	var buf bytes.Buffer
+
+	if err := t.text.ExecuteTemplate(&buf, name+".subject", data); err != nil {
+		return "", "", "", err
+	}
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := t.text.ExecuteTemplate(&buf, name+".txt.tmpl", data); err != nil {
+		return "", "", "", err
+	}
+	text = buf.String()
+
+	if t.html.Lookup(name+".html.tmpl") != nil {
+		buf.Reset()
+		if err := t.html.ExecuteTemplate(&buf, name+".html.tmpl", data); err != nil {
+			return "", "", "", err
+		}
+		html = buf.String()
+	}
+
+	return subject, text, html, nil
+}
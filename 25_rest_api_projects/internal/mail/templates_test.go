@@ -0,0 +1,81 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+
+	data := Data{
+		School:  "Example <College>",
+		Student: types.Student{Name: "Ada"},
+		Course:  types.Course{Code: "CS101", Title: "Programming", Credits: 3},
+		Transition: types.StudentTransition{
+			From: lifecycle.StatusApplicant,
+			To:   lifecycle.StatusEnrolled,
+		},
+	}
+
+	subject, text, html, err := templates.Render(Enrolled, data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if subject != "You are enrolled in CS101" {
+		t.Errorf("subject = %q", subject)
+	}
+	if !strings.Contains(text, "Hello Ada,") || !strings.Contains(text, "Example <College>") {
+		t.Errorf("text = %q", text)
+	}
+	if !strings.Contains(html, "Example &lt;College&gt;") {
+		t.Errorf("html = %q, want the school escaped", html)
+	}
+
+	subject, _, _, err = templates.Render(Transitioned, data)
+	if err != nil || subject != "Your student status is now enrolled" {
+		t.Errorf("transitioned subject = %q, %v", subject, err)
+	}
+}
+
+func TestCustomTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("enrolled.txt.tmpl", `{{define "enrolled.subject"}}  Welcome
+to {{.Course.Code}} {{end}}Hi {{.Student.Name}}`)
+
+	if _, err := LoadTemplates(dir); err == nil {
+		t.Fatal("LoadTemplates accepted a directory without the transitioned template")
+	}
+
+	write("transitioned.txt.tmpl", `{{define "transitioned.subject"}}Now {{.Transition.To}}{{end}}{{.Missing}}`)
+
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+
+	subject, text, html, err := templates.Render(Enrolled, Data{Student: types.Student{Name: "Ada"}, Course: types.Course{Code: "CS101"}})
+	if err != nil || subject != "Welcome to CS101" || text != "Hi Ada" || html != "" {
+		t.Errorf("Render = %q, %q, %q, %v", subject, text, html, err)
+	}
+
+	if _, _, _, err := templates.Render(Transitioned, Data{}); err == nil {
+		t.Error("a template using an unknown field rendered")
+	}
+}
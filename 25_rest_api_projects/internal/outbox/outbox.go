@@ -0,0 +1,214 @@
+// Package outbox relays events from the outbox table to sinks. Storage
+// writes each event in the same transaction as the change it describes;
+// a relay per sink reads them in order, sends them and then records how far
+// that sink got. A crash between sending and recording means the event is
+// sent again after restart, so delivery is at least once and consumers
+// should deduplicate on the event id.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+type Store interface {
+	OutboxAfter(afterId int64, limit int) ([]types.OutboxMessage, error)
+	OutboxOffset(sink string) (int64, error)
+	SetOutboxOffset(sink string, id int64) error
+	PruneOutbox(sinks []string, before time.Time) (int64, error)
+}
+
+// Relay runs one loop per sink so a failing sink only holds back its own
+// deliveries.
+type Relay struct {
+	store Store
+	cfg   config.Outbox
+	sinks map[string]Sink
+
+	mu     sync.Mutex
+	wakes  []chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRelay(store Store, cfg config.Outbox, sinks map[string]Sink) *Relay {
+	return &Relay{store: store, cfg: cfg, sinks: sinks}
+}
+
+// Wake makes every sink loop check for new messages now instead of at its
+// next poll.
+func (r *Relay) Wake() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, wake := range r.wakes {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	for name, sink := range r.sinks {
+		wake := make(chan struct{}, 1)
+
+		r.mu.Lock()
+		r.wakes = append(r.wakes, wake)
+		r.mu.Unlock()
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.run(ctx, name, sink, wake)
+		}()
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.prune(ctx)
+	}()
+}
+
+// Stop ends the loops, waits for in-flight sends to finish and closes
+// sinks that hold resources.
+func (r *Relay) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	for _, sink := range r.sinks {
+		if closer, ok := sink.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+func (r *Relay) run(ctx context.Context, name string, sink Sink, wake <-chan struct{}) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	failures := 0
+
+	for {
+		delay := time.Duration(0)
+
+		if err := r.drain(ctx, name, sink); err != nil {
+			failures++
+			delay = backoff(r.cfg.PollInterval, r.cfg.MaxBackoff, failures)
+			slog.Error("outbox: relay failed", slog.String("sink", name), slog.Int("failures", failures), slog.String("error", err.Error()))
+		} else {
+			failures = 0
+		}
+
+		if delay > 0 {
+			// Back off without listening for wakes, which would just retry
+			// a sink that is still failing.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+// backoff doubles base for every consecutive failure, up to max. The shift
+// is capped so a sink failing for hours cannot overflow the delay.
+func backoff(base, max time.Duration, failures int) time.Duration {
+	delay := base << min(failures, 16)
+	if delay <= 0 || delay > max {
+		return max
+	}
+	return delay
+}
+
+// drain sends messages to sink until none are left, advancing the sink's
+// offset after each one.
+func (r *Relay) drain(ctx context.Context, name string, sink Sink) error {
+	offset, err := r.store.OutboxOffset(name)
+	if err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		messages, err := r.store.OutboxAfter(offset, r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		for _, message := range messages {
+			var event events.Event
+
+			if err := json.Unmarshal(message.Payload, &event); err != nil {
+				// A payload that cannot be decoded never will be; skip it
+				// rather than block the sink forever.
+				slog.Error("outbox: dropping undecodable message", slog.Int64("id", message.Id), slog.String("error", err.Error()))
+			} else if err := sink.Send(ctx, event); err != nil {
+				return err
+			}
+
+			if err := r.store.SetOutboxOffset(name, message.Id); err != nil {
+				return err
+			}
+
+			offset = message.Id
+		}
+	}
+
+	return nil
+}
+
+func (r *Relay) prune(ctx context.Context) {
+	if r.cfg.Retention <= 0 {
+		return
+	}
+
+	names := make([]string, 0, len(r.sinks))
+	for name := range r.sinks {
+		names = append(names, name)
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.store.PruneOutbox(names, time.Now().UTC().Add(-r.cfg.Retention))
+		if err != nil {
+			slog.Error("outbox: pruning", slog.String("error", err.Error()))
+		} else if n > 0 {
+			slog.Info("outbox: pruned delivered messages", slog.Int64("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
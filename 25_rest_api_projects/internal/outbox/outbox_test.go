@@ -0,0 +1,184 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+)
+
+func TestBackoffIsCapped(t *testing.T) {
+	base, max := time.Second, time.Minute
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{6, time.Minute},
+		// Without the cap these shifts overflow to zero or a negative
+		// duration, which would retry a failing sink in a busy loop.
+		{63, time.Minute},
+		{1000, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(base, max, tt.failures); got != tt.want {
+			t.Errorf("backoff after %d failures = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func newStore(t *testing.T) *sqlite.Sqlite {
+	t.Helper()
+
+	s, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { s.Db.Close() })
+
+	return s
+}
+
+// flakySink fails the first send and records the events it accepts.
+type flakySink struct {
+	mu       sync.Mutex
+	attempts int
+	received []events.Event
+	done     chan struct{}
+	want     int
+}
+
+func (s *flakySink) Send(ctx context.Context, event events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts++
+	if s.attempts == 1 {
+		return errors.New("sink unavailable")
+	}
+
+	s.received = append(s.received, event)
+	if len(s.received) == s.want {
+		close(s.done)
+	}
+	return nil
+}
+
+func TestRelayRetriesFailedSendsInOrder(t *testing.T) {
+	store := newStore(t)
+
+	var ids []int64
+	for _, email := range []string{"ada@example.edu", "grace@example.edu", "alan@example.edu"} {
+		id, err := store.CreateStudent("Student", email, 20)
+		if err != nil {
+			t.Fatalf("CreateStudent: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	sink := &flakySink{done: make(chan struct{}), want: len(ids)}
+
+	relay := NewRelay(store, config.Outbox{PollInterval: time.Millisecond, MaxBackoff: 5 * time.Millisecond, BatchSize: 2}, map[string]Sink{"test": sink})
+	relay.Start()
+
+	select {
+	case <-sink.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay did not deliver every event")
+	}
+	relay.Stop()
+
+	for i, event := range sink.received {
+		if event.Type != events.StudentCreated || event.StudentId != ids[i] {
+			t.Errorf("event %d = %s for student %d, want student.created for %d", i, event.Type, event.StudentId, ids[i])
+		}
+	}
+
+	offset, err := store.OutboxOffset("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if messages, _ := store.OutboxAfter(offset, 10); len(messages) != 0 {
+		t.Errorf("%d messages left after the offset, want none", len(messages))
+	}
+}
+
+// failingQueue refuses events until it is told to accept them.
+type failingQueue struct {
+	fail     bool
+	enqueued []string
+}
+
+func (q *failingQueue) Enqueue(event events.Event) error {
+	if q.fail {
+		return errors.New("queue unavailable")
+	}
+	q.enqueued = append(q.enqueued, event.Id)
+	return nil
+}
+
+func TestBusSinkFailsWhenAQueueFails(t *testing.T) {
+	queue := &failingQueue{fail: true}
+	var published []string
+
+	bus := events.NewBus()
+	bus.SubscribeQueue(queue)
+	bus.Subscribe(events.PublisherFunc(func(event events.Event) {
+		published = append(published, event.Id)
+	}))
+
+	sink := Bus(bus)
+	event := events.New(events.StudentCreated, 1, nil)
+
+	if err := sink.Send(context.Background(), event); err == nil {
+		t.Fatal("Send succeeded although the queue failed")
+	}
+	if len(published) != 0 {
+		t.Errorf("live subscribers got %v before the queue accepted the event", published)
+	}
+
+	queue.fail = false
+	if err := sink.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(queue.enqueued) != 1 || len(published) != 1 {
+		t.Errorf("enqueued %v and published %v, want the event once each", queue.enqueued, published)
+	}
+}
+
+func TestFileSinkAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	sink, err := File(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 2 {
+		if err := sink.Send(context.Background(), events.New(events.StudentCreated, int64(i+1), nil)); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	sink.(*fileSink).Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"student.created"`) {
+		t.Errorf("file = %q, want two JSON lines", data)
+	}
+}
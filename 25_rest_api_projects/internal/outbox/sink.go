@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+)
+
+// Sink receives relayed events. Send returning an error makes the relay
+// retry the same event later.
+type Sink interface {
+	Send(ctx context.Context, event events.Event) error
+}
+
+// SinkFunc adapts a plain function to the Sink interface.
+type SinkFunc func(ctx context.Context, event events.Event) error
+
+func (f SinkFunc) Send(ctx context.Context, event events.Event) error {
+	return f(ctx, event)
+}
+
+// Bus delivers to the in-process bus that feeds webhooks, mail, the event
+// stream and roster WebSockets. A queue on the bus failing to store the
+// event fails the send, so the relay retries it.
+func Bus(bus *events.Bus) Sink {
+	return SinkFunc(func(ctx context.Context, event events.Event) error {
+		return bus.Deliver(event)
+	})
+}
+
+// fileSink appends each event as a line of JSON and syncs it to disk
+// before reporting success.
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func File(path string) (Sink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+func (s *fileSink) Send(ctx context.Context, event events.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+// httpSink POSTs each event as JSON. Any 2xx response counts as delivered;
+// the X-Event-Id header lets the receiver drop redeliveries.
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func HTTP(url string, cfg config.Outbox) Sink {
+	return &httpSink{url: url, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+func (s *httpSink) Send(ctx context.Context, event events.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", event.Id)
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("outbox: %s responded %s", s.url, resp.Status)
+	}
+
+	return nil
+}
+
+// Sinks builds the sinks named in cfg.Sinks. "bus" delivers to bus, "file"
+// appends to cfg.File and "http" posts to cfg.URL.
+func Sinks(cfg config.Outbox, bus *events.Bus) (map[string]Sink, error) {
+	sinks := map[string]Sink{}
+
+	for _, name := range cfg.Sinks {
+		switch name {
+		case "bus":
+			sinks[name] = Bus(bus)
+		case "file":
+			sink, err := File(cfg.File)
+			if err != nil {
+				return nil, fmt.Errorf("outbox file sink: %w", err)
+			}
+			sinks[name] = sink
+		case "http":
+			if cfg.URL == "" {
+				return nil, fmt.Errorf("outbox http sink: url is required")
+			}
+			sinks[name] = HTTP(cfg.URL, cfg)
+		default:
+			return nil, fmt.Errorf("outbox: unknown sink %q", name)
+		}
+	}
+
+	return sinks, nil
+}
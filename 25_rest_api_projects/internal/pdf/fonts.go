@@ -0,0 +1,86 @@
+package pdf
+
+// Font is one of the standard base-14 fonts every PDF reader ships, so
+// nothing needs embedding and the output stays small.
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	HelveticaOblique
+	Courier
+)
+
+var fontNames = [...]string{
+	Helvetica:        "Helvetica",
+	HelveticaBold:    "Helvetica-Bold",
+	HelveticaOblique: "Helvetica-Oblique",
+	Courier:          "Courier",
+}
+
+// Advance widths in 1/1000 em of the printable ASCII range, 0x20 to 0x7e,
+// from the Adobe AFM files.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// Width returns how many points s takes up when set in font at size.
+// Characters outside ASCII are measured as an average letter.
+func Width(font Font, size float64, s string) float64 {
+	var units int
+
+	for _, c := range encode(s) {
+		units += charWidth(font, c)
+	}
+
+	return float64(units) * size / 1000
+}
+
+func charWidth(font Font, c byte) int {
+	if font == Courier {
+		return 600
+	}
+
+	if c < 0x20 || c > 0x7e {
+		return 556
+	}
+
+	if font == HelveticaBold {
+		return helveticaBoldWidths[c-0x20]
+	}
+	return helveticaWidths[c-0x20]
+}
+
+// encode maps s to WinAnsiEncoding, which matches Latin-1 for the
+// characters a name is likely to contain. Anything else becomes '?'.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+
+	for _, r := range s {
+		switch {
+		case r < 0x20:
+			out = append(out, ' ')
+		case r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		default:
+			out = append(out, '?')
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,341 @@
+// Package pdf writes simple flowing documents: lines of text, rules and
+// tables that break across pages. Output depends only on what was written,
+// never on the clock or a random id, so the same document always encodes to
+// the same bytes.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PageSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	A4     = PageSize{Width: 595.28, Height: 841.89}
+	Letter = PageSize{Width: 612, Height: 792}
+)
+
+// Info fills the document properties readers show. A zero Created leaves
+// the creation date out.
+type Info struct {
+	Title   string
+	Author  string
+	Subject string
+	Created time.Time
+}
+
+const (
+	lineHeight = 1.25
+	footerSize = 8
+)
+
+type Document struct {
+	size   PageSize
+	margin float64
+	info   Info
+	footer string
+
+	pages []*bytes.Buffer
+	y     float64
+
+	font     Font
+	fontSize float64
+}
+
+// New starts an empty document. Content is laid out inside margin points
+// of every edge, with the bottom margin also holding the footer.
+func New(size PageSize, margin float64) *Document {
+	return &Document{
+		size:     size,
+		margin:   margin,
+		font:     Helvetica,
+		fontSize: 10,
+	}
+}
+
+func (d *Document) SetInfo(info Info) {
+	d.info = info
+}
+
+// SetFooter prints text at the bottom left of every page, next to the page
+// number.
+func (d *Document) SetFooter(text string) {
+	d.footer = text
+}
+
+// SetFont applies to everything written after it.
+func (d *Document) SetFont(font Font, size float64) {
+	d.font = font
+	d.fontSize = size
+}
+
+// Pages returns how many pages have been started.
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// ContentWidth is the width between the left and right margins.
+func (d *Document) ContentWidth() float64 {
+	return d.size.Width - 2*d.margin
+}
+
+// Text writes s as a paragraph in the current font, wrapping at spaces to
+// fit the content width.
+func (d *Document) Text(s string) {
+	leading := d.fontSize * lineHeight
+
+	for _, line := range wrap(s, d.font, d.fontSize, d.ContentWidth()) {
+		d.ensure(leading)
+		d.text(d.margin, d.y-d.fontSize, d.font, d.fontSize, line)
+		d.y -= leading
+	}
+}
+
+// Space moves down by points, starting a new page when that runs past the
+// bottom margin.
+func (d *Document) Space(points float64) {
+	d.ensure(points)
+	d.y -= points
+}
+
+// Rule draws a thin line across the content width.
+func (d *Document) Rule() {
+	d.ensure(6)
+	d.line(d.margin, d.y-3, d.size.Width-d.margin, d.y-3)
+	d.y -= 6
+}
+
+// page returns the page being written, starting the first one on demand.
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.newPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+	d.y = d.size.Height - d.margin
+}
+
+// ensure starts a new page unless height points still fit above the
+// bottom margin.
+func (d *Document) ensure(height float64) {
+	if len(d.pages) == 0 || d.y-height < d.margin {
+		d.newPage()
+	}
+}
+
+func (d *Document) text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F%d %s Tf %s %s Td %s Tj ET\n", font+1, num(size), num(x), num(y), literal(encode(s)))
+}
+
+func (d *Document) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %s %s m %s %s l S\n", num(x1), num(y1), num(x2), num(y2))
+}
+
+func (d *Document) fill(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.page(), "%s g %s %s %s %s re f 0 g\n", num(gray), num(x), num(y), num(w), num(h))
+}
+
+// Bytes encodes the document. It can be called again after writing more.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*bytes.Buffer{new(bytes.Buffer)}
+	}
+
+	out := &writer{}
+	out.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// Objects are numbered up front: catalog, page tree, fonts, then a
+	// page and its content stream for every page, and the info dictionary
+	// last.
+	const catalog, tree, firstFont = 1, 2, 3
+	firstPage := firstFont + len(fontNames)
+	info := firstPage + 2*len(pages)
+
+	out.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", tree))
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	out.object(tree, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	fonts := make([]string, len(fontNames))
+	for i, name := range fontNames {
+		out.object(firstFont+i, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, firstFont+i)
+	}
+
+	for i, content := range pages {
+		id := firstPage + 2*i
+		out.object(id, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			tree, num(d.size.Width), num(d.size.Height), strings.Join(fonts, " "), id+1))
+		out.stream(id+1, d.withFooter(content.Bytes(), i+1, len(pages)))
+	}
+
+	out.object(info, d.infoDict())
+
+	xref := out.n
+	out.printf("xref\n0 %d\n0000000000 65535 f \n", len(out.offsets)+1)
+	for _, offset := range out.offsets {
+		out.printf("%010d 00000 n \n", offset)
+	}
+	out.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(out.offsets)+1, catalog, info, xref)
+
+	n, err := w.Write(out.buf.Bytes())
+	return int64(n), err
+}
+
+func (d *Document) withFooter(content []byte, page, pages int) []byte {
+	var buf bytes.Buffer
+	buf.Write(content)
+
+	y := d.margin / 2
+	if d.footer != "" {
+		fmt.Fprintf(&buf, "BT /F%d %d Tf %s %s Td %s Tj ET\n", Helvetica+1, footerSize, num(d.margin), num(y), literal(encode(d.footer)))
+	}
+
+	number := fmt.Sprintf("Page %d of %d", page, pages)
+	x := d.size.Width - d.margin - Width(Helvetica, footerSize, number)
+	fmt.Fprintf(&buf, "BT /F%d %d Tf %s %s Td %s Tj ET\n", Helvetica+1, footerSize, num(x), num(y), literal(encode(number)))
+
+	return buf.Bytes()
+}
+
+func (d *Document) infoDict() string {
+	entries := []string{"/Producer (students-api)"}
+
+	for _, field := range []struct{ key, value string }{
+		{"Title", d.info.Title},
+		{"Author", d.info.Author},
+		{"Subject", d.info.Subject},
+	} {
+		if field.value != "" {
+			entries = append(entries, "/"+field.key+" "+literal(encode(field.value)))
+		}
+	}
+
+	if !d.info.Created.IsZero() {
+		entries = append(entries, "/CreationDate "+literal([]byte(d.info.Created.UTC().Format("D:20060102150405Z"))))
+	}
+
+	return "<< " + strings.Join(entries, " ") + " >>"
+}
+
+// writer tracks the byte offset of every object for the xref table.
+type writer struct {
+	buf     bytes.Buffer
+	n       int
+	offsets []int
+}
+
+func (w *writer) printf(format string, args ...any) {
+	n, _ := fmt.Fprintf(&w.buf, format, args...)
+	w.n += n
+}
+
+func (w *writer) object(id int, body string) {
+	w.offsets = append(w.offsets, w.n)
+	w.printf("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (w *writer) stream(id int, data []byte) {
+	var compressed bytes.Buffer
+	zw, _ := zlib.NewWriterLevel(&compressed, zlib.BestCompression)
+	zw.Write(data)
+	zw.Close()
+
+	w.offsets = append(w.offsets, w.n)
+	w.printf("%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", id, compressed.Len())
+	n, _ := w.buf.Write(compressed.Bytes())
+	w.n += n
+	w.printf("\nendstream\nendobj\n")
+}
+
+// literal quotes s as a PDF string.
+func literal(s []byte) string {
+	var b strings.Builder
+	b.WriteByte('(')
+
+	for _, c := range s {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	b.WriteByte(')')
+	return b.String()
+}
+
+// num formats a coordinate with at most two decimals, which is finer than
+// any printer resolves and keeps the output stable.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// wrap breaks s into lines no wider than width, splitting at spaces. A
+// single word wider than width gets a line of its own.
+func wrap(s string, font Font, size, width float64) []string {
+	var lines []string
+
+	for _, paragraph := range strings.Split(s, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := words[0]
+		for _, word := range words[1:] {
+			if Width(font, size, line+" "+word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// fit shortens s with "..." until it is no wider than width.
+func fit(s string, font Font, size, width float64) string {
+	if Width(font, size, s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if candidate := string(runes) + "..."; Width(font, size, candidate) <= width {
+			return candidate
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,110 @@
+package pdf
+
+type Align int
+
+const (
+	Left Align = iota
+	Right
+	Center
+)
+
+// Column widths are shares of the content width; they need not add up to
+// one, only to each other.
+type Column struct {
+	Header string
+	Width  float64
+	Align  Align
+}
+
+// Table is a grid of single-line cells. Cells too wide for their column are
+// cut short with "...". The header row, when any column has one, is
+// repeated at the top of every page the table runs onto, and Totals is set
+// in bold below a rule.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	Totals  []string
+}
+
+const cellPadding = 4.0
+
+// Table writes t in the current font size.
+func (d *Document) Table(t Table) {
+	widths := d.columnWidths(t.Columns)
+	rowHeight := d.fontSize * 1.8
+
+	header := make([]string, len(t.Columns))
+	hasHeader := false
+	for i, column := range t.Columns {
+		header[i] = column.Header
+		hasHeader = hasHeader || column.Header != ""
+	}
+
+	writeHeader := func() {
+		if !hasHeader {
+			return
+		}
+		d.fill(d.margin, d.y-rowHeight, d.ContentWidth(), rowHeight, 0.9)
+		d.row(t.Columns, widths, header, HelveticaBold, rowHeight)
+	}
+
+	d.ensure(rowHeight * 2)
+	writeHeader()
+
+	for _, cells := range t.Rows {
+		if d.y-rowHeight < d.margin {
+			d.newPage()
+			writeHeader()
+		}
+		d.row(t.Columns, widths, cells, d.font, rowHeight)
+	}
+
+	if t.Totals != nil {
+		d.ensure(rowHeight + 6)
+		d.Rule()
+		d.row(t.Columns, widths, t.Totals, HelveticaBold, rowHeight)
+	}
+}
+
+func (d *Document) row(columns []Column, widths []float64, cells []string, font Font, height float64) {
+	x := d.margin
+	baseline := d.y - height/2 - d.fontSize*0.35
+
+	for i, column := range columns {
+		if i < len(cells) && cells[i] != "" {
+			room := widths[i] - 2*cellPadding
+			text := fit(cells[i], font, d.fontSize, room)
+
+			offset := cellPadding
+			switch column.Align {
+			case Right:
+				offset += room - Width(font, d.fontSize, text)
+			case Center:
+				offset += (room - Width(font, d.fontSize, text)) / 2
+			}
+
+			d.text(x+offset, baseline, font, d.fontSize, text)
+		}
+		x += widths[i]
+	}
+
+	d.y -= height
+}
+
+func (d *Document) columnWidths(columns []Column) []float64 {
+	var total float64
+	for _, column := range columns {
+		total += column.Width
+	}
+
+	widths := make([]float64, len(columns))
+	for i, column := range columns {
+		if total == 0 {
+			widths[i] = d.ContentWidth() / float64(len(columns))
+			continue
+		}
+		widths[i] = d.ContentWidth() * column.Width / total
+	}
+
+	return widths
+}
@@ -0,0 +1,213 @@
+// Package report lays out the printable documents registrars ask for. The
+// functions take plain records and the issue time, so the same inputs
+// always produce the same PDF.
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/pdf"
+	"github.com/faysal0x1/Go-Learn/internal/stats"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+const margin = 56
+
+// PageSize maps the reports.page_size setting to a page, defaulting to A4.
+func PageSize(name string) pdf.PageSize {
+	if name == "letter" {
+		return pdf.Letter
+	}
+	return pdf.A4
+}
+
+// TranscriptEntry is one course a student took.
+type TranscriptEntry struct {
+	Course     types.Course
+	Enrollment types.Enrollment
+}
+
+// RosterEntry is one student in a course.
+type RosterEntry struct {
+	Student    types.Student
+	Enrollment types.Enrollment
+}
+
+// Transcript lists every course of student in the order they were taken,
+// with the credit-weighted GPA of the graded ones.
+func Transcript(size pdf.PageSize, school string, student types.Student, entries []TranscriptEntry, issued time.Time) *pdf.Document {
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, func(a, b TranscriptEntry) int {
+		return cmp.Or(
+			a.Enrollment.EnrolledAt.Compare(b.Enrollment.EnrolledAt),
+			cmp.Compare(a.Course.Code, b.Course.Code),
+		)
+	})
+
+	doc := pdf.New(size, margin)
+	doc.SetInfo(pdf.Info{
+		Title:   "Transcript - " + student.Name,
+		Author:  school,
+		Subject: "Academic transcript",
+		Created: issued,
+	})
+	doc.SetFooter(fmt.Sprintf("%s - transcript of %s (student %d)", school, student.Name, student.Id))
+
+	heading(doc, school, "Academic Transcript")
+
+	doc.Table(pdf.Table{
+		Columns: []pdf.Column{{Width: 1}, {Width: 3}},
+		Rows: [][]string{
+			{"Student", student.Name},
+			{"Student ID", strconv.FormatInt(student.Id, 10)},
+			{"Email", student.Email},
+			{"Status", string(student.Status)},
+			{"Issued", issued.UTC().Format("2 January 2006")},
+		},
+	})
+	doc.Space(12)
+
+	var rows [][]string
+	var grades, credits []float64
+	gradedCredits := 0
+
+	for _, entry := range entries {
+		grade := "in progress"
+		if entry.Enrollment.Grade != nil {
+			grade = points(*entry.Enrollment.Grade)
+			grades = append(grades, *entry.Enrollment.Grade)
+			credits = append(credits, float64(entry.Course.Credits))
+			gradedCredits += entry.Course.Credits
+		}
+
+		rows = append(rows, []string{
+			entry.Course.Code,
+			entry.Course.Title,
+			strconv.Itoa(entry.Course.Credits),
+			entry.Enrollment.EnrolledAt.UTC().Format("2006-01-02"),
+			grade,
+		})
+	}
+
+	if len(rows) == 0 {
+		doc.SetFont(pdf.HelveticaOblique, 10)
+		doc.Text("No courses on record.")
+		return doc
+	}
+
+	doc.Table(pdf.Table{
+		Columns: []pdf.Column{
+			{Header: "Course", Width: 1.2},
+			{Header: "Title", Width: 3.5},
+			{Header: "Credits", Width: 1, Align: pdf.Right},
+			{Header: "Enrolled", Width: 1.4, Align: pdf.Center},
+			{Header: "Grade", Width: 1.2, Align: pdf.Right},
+		},
+		Rows:   rows,
+		Totals: []string{"", "Graded credits and GPA", strconv.Itoa(gradedCredits), "", points(stats.WeightedAverage(grades, credits))},
+	})
+
+	doc.Space(18)
+	doc.SetFont(pdf.HelveticaOblique, 8)
+	doc.Text("Grades are grade points on a 0-4 scale. The GPA is weighted by course credits and counts graded courses only.")
+
+	return doc
+}
+
+// Roster lists the students enrolled in course by name, with their grades
+// and the class average.
+func Roster(size pdf.PageSize, school string, course types.Course, entries []RosterEntry, issued time.Time) *pdf.Document {
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, func(a, b RosterEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.Student.Name, b.Student.Name),
+			cmp.Compare(a.Student.Id, b.Student.Id),
+		)
+	})
+
+	doc := pdf.New(size, margin)
+	doc.SetInfo(pdf.Info{
+		Title:   "Roster - " + course.Code,
+		Author:  school,
+		Subject: "Class roster",
+		Created: issued,
+	})
+	doc.SetFooter(fmt.Sprintf("%s - roster of %s", school, course.Code))
+
+	heading(doc, school, "Class Roster")
+
+	doc.Table(pdf.Table{
+		Columns: []pdf.Column{{Width: 1}, {Width: 3}},
+		Rows: [][]string{
+			{"Course", course.Code + " - " + course.Title},
+			{"Credits", strconv.Itoa(course.Credits)},
+			{"Enrolled", strconv.Itoa(len(entries))},
+			{"Issued", issued.UTC().Format("2 January 2006")},
+		},
+	})
+	doc.Space(12)
+
+	if len(entries) == 0 {
+		doc.SetFont(pdf.HelveticaOblique, 10)
+		doc.Text("No students are enrolled.")
+		return doc
+	}
+
+	var rows [][]string
+	var grades []float64
+
+	for i, entry := range entries {
+		grade := ""
+		if entry.Enrollment.Grade != nil {
+			grade = points(*entry.Enrollment.Grade)
+			grades = append(grades, *entry.Enrollment.Grade)
+		}
+
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			strconv.FormatInt(entry.Student.Id, 10),
+			entry.Student.Name,
+			entry.Student.Email,
+			string(entry.Student.Status),
+			grade,
+		})
+	}
+
+	average := "-"
+	if len(grades) > 0 {
+		average = points(stats.Calculate(grades...).Average)
+	}
+
+	doc.Table(pdf.Table{
+		Columns: []pdf.Column{
+			{Header: "#", Width: 0.5, Align: pdf.Right},
+			{Header: "ID", Width: 0.8, Align: pdf.Right},
+			{Header: "Name", Width: 2.6},
+			{Header: "Email", Width: 3},
+			{Header: "Status", Width: 1.3},
+			{Header: "Grade", Width: 0.9, Align: pdf.Right},
+		},
+		Rows:   rows,
+		Totals: []string{"", "", fmt.Sprintf("%d graded", len(grades)), "", "Average", average},
+	})
+
+	return doc
+}
+
+func heading(doc *pdf.Document, school string, title string) {
+	doc.SetFont(pdf.HelveticaBold, 16)
+	doc.Text(school)
+	doc.SetFont(pdf.Helvetica, 12)
+	doc.Text(title)
+	doc.Rule()
+	doc.Space(6)
+	doc.SetFont(pdf.Helvetica, 10)
+}
+
+func points(grade float64) string {
+	return strconv.FormatFloat(grade, 'f', 2, 64)
+}
@@ -0,0 +1,99 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/pdf"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var issued = time.Date(2025, time.June, 30, 9, 0, 0, 0, time.UTC)
+
+// golden compares doc with testdata/name byte for byte, or rewrites the
+// file when the tests run with -update.
+func golden(t *testing.T, name string, doc *pdf.Document) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	got := doc.Bytes()
+
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run with -update to create it)", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file; run go test ./internal/report -update if the change is intended", name)
+	}
+
+	if again := doc.Bytes(); !bytes.Equal(got, again) {
+		t.Errorf("%s encodes differently on a second call", name)
+	}
+}
+
+func grade(g float64) *float64 {
+	return &g
+}
+
+func TestTranscriptGolden(t *testing.T) {
+	student := types.Student{Id: 42, Name: "Ada Lovelace", Email: "ada@example.edu", Age: 20, Status: lifecycle.StatusEnrolled}
+
+	entries := []TranscriptEntry{
+		{
+			Course:     types.Course{Id: 2, Code: "MATH201", Title: "Linear Algebra", Credits: 4},
+			Enrollment: types.Enrollment{Grade: grade(3.7), EnrolledAt: time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			Course:     types.Course{Id: 1, Code: "CS101", Title: "Introduction to Programming", Credits: 3},
+			Enrollment: types.Enrollment{Grade: grade(4), EnrolledAt: time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			Course:     types.Course{Id: 3, Code: "PHYS110", Title: "Mechanics", Credits: 3},
+			Enrollment: types.Enrollment{EnrolledAt: time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	golden(t, "transcript.pdf", Transcript(pdf.A4, "Example College", student, entries, issued))
+}
+
+func TestRosterGolden(t *testing.T) {
+	course := types.Course{Id: 1, Code: "CS101", Title: "Introduction to Programming", Credits: 3}
+
+	var entries []RosterEntry
+	for i := range 90 {
+		entry := RosterEntry{
+			Student: types.Student{
+				Id:     int64(i + 1),
+				Name:   fmt.Sprintf("Student %02d", 90-i),
+				Email:  fmt.Sprintf("student%02d@example.edu", 90-i),
+				Status: lifecycle.StatusEnrolled,
+			},
+		}
+		if i%3 != 0 {
+			entry.Enrollment.Grade = grade(float64(i%5) * 0.8)
+		}
+		entries = append(entries, entry)
+	}
+
+	doc := Roster(pdf.Letter, "Example College", course, entries, issued)
+	if doc.Pages() < 2 {
+		t.Fatalf("roster of %d students has %d pages, want several", len(entries), doc.Pages())
+	}
+
+	golden(t, "roster.pdf", doc)
+}
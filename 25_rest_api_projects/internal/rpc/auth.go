@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/http/middleware"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// unaryAPIKey and streamAPIKey reject calls unless their metadata carries a
+// key issued with `students_api create-api-key`, in x-api-key or as a bearer
+// token, checked against the store the HTTP APIKey middleware uses. A nil
+// store leaves the service open, as auth.require_api_key does for REST.
+// The key is recorded in the context for the tenant interceptor to check.
+func unaryAPIKey(store middleware.KeyStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAPIKey(store middleware.KeyStore) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), store)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, store middleware.KeyStore) (context.Context, error) {
+	if store == nil {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	key := metadataSource(md).Header("x-api-key")
+	if bearer, ok := strings.CutPrefix(metadataSource(md).Header("authorization"), "Bearer "); ok && apikey.Valid(bearer) {
+		key = bearer
+	}
+
+	if !apikey.Valid(key) {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid api key")
+	}
+
+	authenticated, err := store.AuthenticateAPIKey(apikey.Hash(key))
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			slog.ErrorContext(ctx, "api key lookup failed", slog.String("error", err.Error()))
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid api key")
+	}
+
+	return apikey.WithKey(ctx, authenticated), nil
+}
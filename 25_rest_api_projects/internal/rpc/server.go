@@ -0,0 +1,230 @@
+// Package rpc serves the students API over gRPC. The service definition is
+// in proto/students/v1; regenerate pkg/pb by running `buf generate` from the
+// project root.
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/http/middleware"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/utils/validate"
+	studentsv1 "github.com/faysal0x1/Go-Learn/pkg/pb/students/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Backend returns the storage and event broadcaster of the tenant in ctx,
+// the same ones the HTTP handlers of that tenant use.
+type Backend func(ctx context.Context) (storage.Storage, *events.Broadcaster, error)
+
+type studentService struct {
+	studentsv1.UnimplementedStudentServiceServer
+
+	backend Backend
+}
+
+// NewServer returns a gRPC server with StudentService registered. Every
+// call is traced, checked for an API key when keys is not nil, resolved to
+// a tenant from its metadata and then served from that tenant's backend.
+func NewServer(backend Backend, resolver *tenant.Resolver, limiter *tenant.Limiter, keys middleware.KeyStore, tracer *trace.Tracer, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryTrace(tracer), unaryAPIKey(keys), unaryTenant(resolver, limiter)),
+		grpc.ChainStreamInterceptor(streamTrace(tracer), streamAPIKey(keys), streamTenant(resolver, limiter)),
+	)
+
+	server := grpc.NewServer(opts...)
+
+	studentsv1.RegisterStudentServiceServer(server, &studentService{backend: backend})
+
+	return server
+}
+
+func (s *studentService) storage(ctx context.Context) (storage.Storage, error) {
+	store, _, err := s.backend(ctx)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return store, nil
+}
+
+func (s *studentService) CreateStudent(ctx context.Context, req *studentsv1.CreateStudentRequest) (*studentsv1.CreateStudentResponse, error) {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	student := types.Student{Name: req.GetName(), Email: req.GetEmail(), Age: int(req.GetAge())}
+
+	if err := validate.Struct(student); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	id, err := store.CreateStudent(student.Name, student.Email, student.Age)
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	created, err := store.GetStudentById(id)
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return &studentsv1.CreateStudentResponse{Student: toProto(created)}, nil
+}
+
+func (s *studentService) GetStudent(ctx context.Context, req *studentsv1.GetStudentRequest) (*studentsv1.GetStudentResponse, error) {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	student, err := store.GetStudentById(req.GetId())
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return &studentsv1.GetStudentResponse{Student: toProto(student)}, nil
+}
+
+func (s *studentService) ListStudents(ctx context.Context, req *studentsv1.ListStudentsRequest) (*studentsv1.ListStudentsResponse, error) {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := store.GetStudents()
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	resp := &studentsv1.ListStudentsResponse{Students: make([]*studentsv1.Student, 0, len(students))}
+	for _, student := range students {
+		resp.Students = append(resp.Students, toProto(student))
+	}
+
+	return resp, nil
+}
+
+func (s *studentService) UpdateStudent(ctx context.Context, req *studentsv1.UpdateStudentRequest) (*studentsv1.UpdateStudentResponse, error) {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	student := types.Student{Id: req.GetId(), Name: req.GetName(), Email: req.GetEmail(), Age: int(req.GetAge())}
+
+	if err := validate.Struct(student); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := store.UpdateStudent(student); err != nil {
+		return nil, storageError(err)
+	}
+
+	updated, err := store.GetStudentById(student.Id)
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return &studentsv1.UpdateStudentResponse{Student: toProto(updated)}, nil
+}
+
+func (s *studentService) DeleteStudent(ctx context.Context, req *studentsv1.DeleteStudentRequest) (*studentsv1.DeleteStudentResponse, error) {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.DeleteStudent(req.GetId()); err != nil {
+		return nil, storageError(err)
+	}
+
+	return &studentsv1.DeleteStudentResponse{}, nil
+}
+
+// WatchStudents follows the same broadcaster as the SSE stream, so epochs
+// and sequence numbers are interchangeable between the two.
+func (s *studentService) WatchStudents(req *studentsv1.WatchStudentsRequest, stream grpc.ServerStreamingServer[studentsv1.WatchStudentsResponse]) error {
+	_, broadcaster, err := s.backend(stream.Context())
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	sub, replay, complete := broadcaster.Subscribe(req.GetEpoch(), req.GetAfterSequence(), req.GetResume())
+	defer sub.Unsubscribe()
+
+	if !complete {
+		if err := stream.Send(&studentsv1.WatchStudentsResponse{Type: "reset", Epoch: broadcaster.Epoch()}); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range replay {
+		if err := stream.Send(eventToProto(item)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-sub.Done:
+			return status.Error(codes.Unavailable, "watch ended; resume from the last sequence")
+		case item := <-sub.C:
+			if err := stream.Send(eventToProto(item)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toProto(student types.Student) *studentsv1.Student {
+	return &studentsv1.Student{
+		Id:              student.Id,
+		Name:            student.Name,
+		Email:           student.Email,
+		Age:             int32(student.Age),
+		Status:          string(student.Status),
+		StatusChangedAt: timestamppb.New(student.StatusChangedAt),
+	}
+}
+
+func eventToProto(item events.Sequenced) *studentsv1.WatchStudentsResponse {
+	resp := &studentsv1.WatchStudentsResponse{
+		Epoch:      item.Epoch,
+		Sequence:   item.Seq,
+		Type:       string(item.Event.Type),
+		EventId:    item.Event.Id,
+		StudentId:  item.Event.StudentId,
+		CourseId:   item.Event.CourseId,
+		OccurredAt: timestamppb.New(item.Event.OccurredAt),
+	}
+
+	if student, ok := item.Event.Data.(types.Student); ok {
+		resp.Student = toProto(student)
+	}
+
+	return resp
+}
+
+func storageError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, storage.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, storage.ErrInvalidRef):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,298 @@
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/http/middleware"
+	"github.com/faysal0x1/Go-Learn/internal/rpc"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	studentsv1 "github.com/faysal0x1/Go-Learn/pkg/pb/students/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves backend over an in-process listener and returns a client
+// for it.
+func dial(t *testing.T, resolver *tenant.Resolver, backend rpc.Backend) studentsv1.StudentServiceClient {
+	t.Helper()
+
+	return dialWithKeys(t, resolver, backend, nil)
+}
+
+// dialWithKeys is dial with API keys required and checked against keys.
+func dialWithKeys(t *testing.T, resolver *tenant.Resolver, backend rpc.Backend, keys middleware.KeyStore) studentsv1.StudentServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	server := rpc.NewServer(backend, resolver, tenant.NewLimiter(), keys, nil)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return studentsv1.NewStudentServiceClient(conn)
+}
+
+func newStore(t *testing.T) *sqlite.Sqlite {
+	t.Helper()
+
+	s, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { s.Db.Close() })
+
+	return s
+}
+
+func staticBackend(store storage.Storage, broadcaster *events.Broadcaster) rpc.Backend {
+	return func(context.Context) (storage.Storage, *events.Broadcaster, error) {
+		return store, broadcaster, nil
+	}
+}
+
+func wantCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+
+	if got := status.Code(err); got != want {
+		t.Errorf("status = %v (%v), want %v", got, err, want)
+	}
+}
+
+func TestStudentCRUD(t *testing.T) {
+	client := dial(t, tenant.NewResolver(config.Tenancy{}), staticBackend(newStore(t), events.NewBroadcaster(1, 1)))
+	ctx := context.Background()
+
+	created, err := client.CreateStudent(ctx, &studentsv1.CreateStudentRequest{Name: "Ada", Email: "ada@example.edu", Age: 20})
+	if err != nil {
+		t.Fatalf("CreateStudent: %v", err)
+	}
+
+	id := created.GetStudent().GetId()
+	if id == 0 || created.GetStudent().GetStatus() != "applicant" {
+		t.Fatalf("created = %v", created.GetStudent())
+	}
+
+	got, err := client.GetStudent(ctx, &studentsv1.GetStudentRequest{Id: id})
+	if err != nil || got.GetStudent().GetName() != "Ada" {
+		t.Fatalf("GetStudent = %v, %v", got, err)
+	}
+
+	updated, err := client.UpdateStudent(ctx, &studentsv1.UpdateStudentRequest{Id: id, Name: "Ada Lovelace", Email: "ada@example.edu", Age: 21})
+	if err != nil || updated.GetStudent().GetName() != "Ada Lovelace" || updated.GetStudent().GetAge() != 21 {
+		t.Fatalf("UpdateStudent = %v, %v", updated, err)
+	}
+
+	list, err := client.ListStudents(ctx, &studentsv1.ListStudentsRequest{})
+	if err != nil || len(list.GetStudents()) != 1 {
+		t.Fatalf("ListStudents = %v, %v", list, err)
+	}
+
+	if _, err := client.DeleteStudent(ctx, &studentsv1.DeleteStudentRequest{Id: id}); err != nil {
+		t.Fatalf("DeleteStudent: %v", err)
+	}
+
+	_, err = client.GetStudent(ctx, &studentsv1.GetStudentRequest{Id: id})
+	wantCode(t, err, codes.NotFound)
+}
+
+// conflictingStore fails every create as a duplicate.
+type conflictingStore struct {
+	storage.Storage
+}
+
+func (conflictingStore) CreateStudent(string, string, int) (int64, error) {
+	return 0, fmt.Errorf("student: %w", storage.ErrAlreadyExists)
+}
+
+func TestStatusCodes(t *testing.T) {
+	store := newStore(t)
+	client := dial(t, tenant.NewResolver(config.Tenancy{}), staticBackend(store, events.NewBroadcaster(1, 1)))
+	ctx := context.Background()
+
+	_, err := client.CreateStudent(ctx, &studentsv1.CreateStudentRequest{Name: "", Email: "not an email", Age: 20})
+	wantCode(t, err, codes.InvalidArgument)
+
+	_, err = client.UpdateStudent(ctx, &studentsv1.UpdateStudentRequest{Id: 404, Name: "Ada", Email: "ada@example.edu", Age: 20})
+	wantCode(t, err, codes.NotFound)
+
+	_, err = client.DeleteStudent(ctx, &studentsv1.DeleteStudentRequest{Id: 404})
+	wantCode(t, err, codes.NotFound)
+
+	conflicting := dial(t, tenant.NewResolver(config.Tenancy{}), staticBackend(conflictingStore{store}, events.NewBroadcaster(1, 1)))
+
+	_, err = conflicting.CreateStudent(ctx, &studentsv1.CreateStudentRequest{Name: "Ada", Email: "ada@example.edu", Age: 20})
+	wantCode(t, err, codes.AlreadyExists)
+}
+
+func TestTenantErrors(t *testing.T) {
+	resolver := tenant.NewResolver(config.Tenancy{
+		Enabled: true,
+		Sources: []string{"header"},
+		Header:  "X-Tenant-ID",
+		Tenants: map[string]config.Tenant{"north": {}},
+	})
+	client := dial(t, resolver, staticBackend(newStore(t), events.NewBroadcaster(1, 1)))
+
+	_, err := client.ListStudents(context.Background(), &studentsv1.ListStudentsRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant-id", "south")
+	_, err = client.ListStudents(ctx, &studentsv1.ListStudentsRequest{})
+	wantCode(t, err, codes.NotFound)
+}
+
+func TestAPIKeyRequired(t *testing.T) {
+	store := newStore(t)
+
+	key, prefix, hash := apikey.Generate()
+	if _, err := store.CreateAPIKey("tests", prefix, hash); err != nil {
+		t.Fatalf("creating key: %v", err)
+	}
+	other, _, _ := apikey.Generate()
+
+	client := dialWithKeys(t, tenant.NewResolver(config.Tenancy{}), staticBackend(store, events.NewBroadcaster(1, 1)), store)
+
+	_, err := client.ListStudents(context.Background(), &studentsv1.ListStudentsRequest{})
+	wantCode(t, err, codes.Unauthenticated)
+
+	unknown := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", other)
+	_, err = client.ListStudents(unknown, &studentsv1.ListStudentsRequest{})
+	wantCode(t, err, codes.Unauthenticated)
+
+	// Streams are checked before the first message is sent.
+	stream, err := client.WatchStudents(context.Background(), &studentsv1.WatchStudentsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	wantCode(t, err, codes.Unauthenticated)
+
+	for _, md := range [][]string{{"x-api-key", key}, {"authorization", "Bearer " + key}} {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), md...)
+		if _, err := client.ListStudents(ctx, &studentsv1.ListStudentsRequest{}); err != nil {
+			t.Errorf("ListStudents with %s: %v", md[0], err)
+		}
+	}
+}
+
+func recvTypes(t *testing.T, stream grpc.ServerStreamingClient[studentsv1.WatchStudentsResponse], n int) []*studentsv1.WatchStudentsResponse {
+	t.Helper()
+
+	var got []*studentsv1.WatchStudentsResponse
+	for range n {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		got = append(got, resp)
+	}
+	return got
+}
+
+func TestWatchStudentsResumes(t *testing.T) {
+	broadcaster := events.NewBroadcaster(2, 8)
+	for i := int64(1); i <= 3; i++ {
+		broadcaster.Publish(events.New(events.StudentCreated, i, nil))
+	}
+
+	client := dial(t, tenant.NewResolver(config.Tenancy{}), staticBackend(newStore(t), broadcaster))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchStudents(ctx, &studentsv1.WatchStudentsRequest{Epoch: broadcaster.Epoch(), AfterSequence: 2, Resume: true})
+	if err != nil {
+		t.Fatalf("WatchStudents: %v", err)
+	}
+
+	replayed := recvTypes(t, stream, 1)
+	if replayed[0].GetSequence() != 3 || replayed[0].GetStudentId() != 3 || replayed[0].GetEpoch() != broadcaster.Epoch() {
+		t.Errorf("replayed = %v, want sequence 3", replayed[0])
+	}
+
+	broadcaster.Publish(events.New(events.StudentDeleted, 1, nil))
+
+	live := recvTypes(t, stream, 1)
+	if live[0].GetSequence() != 4 || live[0].GetType() != string(events.StudentDeleted) {
+		t.Errorf("live = %v, want sequence 4", live[0])
+	}
+}
+
+func TestWatchStudentsResetsWhenEventsWereLost(t *testing.T) {
+	broadcaster := events.NewBroadcaster(2, 8)
+	for i := int64(1); i <= 5; i++ {
+		broadcaster.Publish(events.New(events.StudentCreated, i, nil))
+	}
+
+	client := dial(t, tenant.NewResolver(config.Tenancy{}), staticBackend(newStore(t), broadcaster))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchStudents(ctx, &studentsv1.WatchStudentsRequest{Epoch: broadcaster.Epoch(), AfterSequence: 1, Resume: true})
+	if err != nil {
+		t.Fatalf("WatchStudents: %v", err)
+	}
+
+	got := recvTypes(t, stream, 3)
+	if got[0].GetType() != "reset" {
+		t.Errorf("first message = %v, want a reset", got[0])
+	}
+	if got[1].GetSequence() != 4 || got[2].GetSequence() != 5 {
+		t.Errorf("replay after reset = %v, %v, want sequences 4 and 5", got[1], got[2])
+	}
+}
+
+func TestWatchStudentsResetsAfterARestart(t *testing.T) {
+	before := events.NewBroadcaster(8, 8)
+	broadcaster := events.NewBroadcaster(8, 8)
+	for i := int64(1); i <= 3; i++ {
+		before.Publish(events.New(events.StudentCreated, i, nil))
+		broadcaster.Publish(events.New(events.StudentCreated, i, nil))
+	}
+
+	client := dial(t, tenant.NewResolver(config.Tenancy{}), staticBackend(newStore(t), broadcaster))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchStudents(ctx, &studentsv1.WatchStudentsRequest{Epoch: before.Epoch(), AfterSequence: 2, Resume: true})
+	if err != nil {
+		t.Fatalf("WatchStudents: %v", err)
+	}
+
+	got := recvTypes(t, stream, 1)
+	if got[0].GetType() != "reset" || got[0].GetEpoch() != broadcaster.Epoch() {
+		t.Errorf("first message = %v, want a reset carrying the current epoch", got[0])
+	}
+
+	broadcaster.Publish(events.New(events.StudentDeleted, 1, nil))
+
+	if live := recvTypes(t, stream, 1); live[0].GetSequence() != 4 {
+		t.Errorf("live = %v, want sequence 4 without a replay", live[0])
+	}
+}
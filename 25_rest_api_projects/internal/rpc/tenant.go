@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"strconv"
+
+	"github.com/faysal0x1/Go-Learn/internal/apikey"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// unaryTenant and streamTenant resolve the tenant of a call the way the
+// HTTP middleware does, reading the same headers from call metadata, and
+// likewise refuse an API key issued for another tenant.
+func unaryTenant(resolver *tenant.Resolver, limiter *tenant.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := resolveTenant(ctx, resolver, limiter)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamTenant(resolver *tenant.Resolver, limiter *tenant.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := resolveTenant(ss.Context(), resolver, limiter)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func resolveTenant(ctx context.Context, resolver *tenant.Resolver, limiter *tenant.Limiter) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	t, err := resolver.Resolve(metadataSource(md))
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrMissing):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, tenant.ErrUnknown):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, tenant.ErrInvalid):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := apikey.CheckTenant(ctx, t.Id); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if ok, retryAfter := limiter.Allow(t); !ok {
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	return tenant.WithTenant(ctx, t), nil
+}
+
+type metadataSource metadata.MD
+
+func (md metadataSource) Header(name string) string {
+	if values := metadata.MD(md).Get(name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (md metadataSource) Host() string {
+	host := md.Header(":authority")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+type tenantStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// unaryTrace and streamTrace start a server span per call, continuing the
+// trace of a traceparent entry in the call metadata.
+func unaryTrace(tracer *trace.Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := startCall(ctx, tracer, info.FullMethod)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		endCall(span, err)
+		return resp, err
+	}
+}
+
+func streamTrace(tracer *trace.Tracer) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startCall(ss.Context(), tracer, info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, &tenantStream{ServerStream: ss, ctx: ctx})
+		endCall(span, err)
+		return err
+	}
+}
+
+func startCall(ctx context.Context, tracer *trace.Tracer, method string) (context.Context, *trace.Span) {
+	md := metadataSource(nil)
+	if incoming, ok := metadata.FromIncomingContext(ctx); ok {
+		md = metadataSource(incoming)
+	}
+
+	requestId := md.Header("x-request-id")
+	if requestId == "" || len(requestId) > 128 {
+		requestId = trace.NewRequestId()
+	}
+	ctx = trace.WithRequestId(ctx, requestId)
+
+	if remote, err := trace.ParseTraceparent(md.Header("traceparent")); err == nil {
+		ctx = trace.WithRemote(ctx, remote)
+	}
+
+	ctx, span := tracer.StartSpan(ctx, method, trace.KindServer)
+	span.SetAttr("rpc.system", "grpc")
+	span.SetAttr("rpc.method", method)
+	span.SetAttr("request_id", requestId)
+
+	return ctx, span
+}
+
+func endCall(span *trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttr("rpc.grpc.status_code", int(code))
+	span.SetError(err)
+}
@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// Schedule gives the next time a job is due after t, or the zero time when
+// it never is again.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse reads a standard 5-field cron expression (minute, hour, day of
+// month, month, day of week) evaluated in loc, one of the @daily style
+// macros, or "@every <duration>".
+//
+// Fields accept *, lists, ranges and steps, e.g. "*/15 9-17 * * MON-FRI".
+// Months and weekdays may be given by their three-letter English names and
+// Sunday is 0 or 7. As in cron, when both day fields are restricted a day
+// matching either one is due. A time skipped by a daylight saving change
+// is not run that day.
+func Parse(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSchedule, spec, err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("%w: %q: interval must be at least 1s", ErrInvalidSchedule, spec)
+		}
+		return every(interval), nil
+	}
+
+	if expanded, ok := macros[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q: want 5 fields, got %d", ErrInvalidSchedule, spec, len(fields))
+	}
+
+	var c cron
+	var err error
+
+	for i, f := range []struct {
+		bits *uint64
+		r    fieldRange
+	}{
+		{&c.minute, minutes},
+		{&c.hour, hours},
+		{&c.dom, daysOfMonth},
+		{&c.month, months},
+		{&c.dow, daysOfWeek},
+	} {
+		if *f.bits, err = parseField(fields[i], f.r); err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSchedule, spec, err)
+		}
+	}
+
+	// Sunday may be written as 7.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	c.anyDom = strings.HasPrefix(fields[2], "*")
+	c.anyDow = strings.HasPrefix(fields[4], "*")
+	c.loc = loc
+
+	return c, nil
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cron holds one bit per allowed value of each field.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	anyDom, anyDow                bool
+	loc                           *time.Location
+}
+
+func (c cron) Next(t time.Time) time.Time {
+	t = t.In(c.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute)
+			continue
+		}
+
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.anyDom || c.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+type fieldRange struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minutes     = fieldRange{name: "minute", min: 0, max: 59}
+	hours       = fieldRange{name: "hour", min: 0, max: 23}
+	daysOfMonth = fieldRange{name: "day of month", min: 1, max: 31}
+	months      = fieldRange{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	daysOfWeek  = fieldRange{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+func parseField(field string, r fieldRange) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(field, ",") {
+		span, stepText, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", r.name, stepText)
+			}
+			step = n
+		}
+
+		low, high := r.min, r.max
+
+		if span != "*" {
+			lowText, highText, isRange := strings.Cut(span, "-")
+
+			var err error
+			if low, err = r.value(lowText); err != nil {
+				return 0, err
+			}
+
+			high = low
+			if isRange {
+				if high, err = r.value(highText); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/15" means from 5 to the end in steps of 15.
+				high = r.max
+			}
+
+			if low > high {
+				return 0, fmt.Errorf("%s: range %q runs backwards", r.name, span)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (r fieldRange) value(text string) (int, error) {
+	for i, name := range r.names {
+		if strings.EqualFold(text, name) {
+			return r.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(text)
+	if err != nil || v < r.min || v > r.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", r.name, text, r.min, r.max)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAndNext(t *testing.T) {
+	// Wednesday 15 January 2025, 10:07.
+	from := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.January, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.January, 15, 10, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2025, time.January, 15, 10, 25, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, time.January, 16, 2, 0, 0, 0, time.UTC)},
+		{"30 3 * * SUN", time.Date(2025, time.January, 19, 3, 30, 0, 0, time.UTC)},
+		{"30 3 * * 7", time.Date(2025, time.January, 19, 3, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 MAR *", time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matching is enough.
+		{"0 0 20 * FRI", time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec, time.UTC)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * JUNE *",
+		"@every 10ms",
+		"@every soon",
+		"@fortnightly",
+	} {
+		if _, err := Parse(spec, time.UTC); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidSchedule", spec, err)
+		}
+	}
+}
+
+func TestNextUsesTheLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	schedule, err := Parse("0 2 * * *", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+	if got, want := schedule.Next(from), time.Date(2025, time.January, 16, 7, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got.UTC(), want)
+	}
+
+	// 02:30 does not exist on 9 March 2025, so that day is skipped.
+	schedule, _ = Parse("30 2 * * *", loc)
+	from = time.Date(2025, time.March, 8, 12, 0, 0, 0, loc)
+
+	if got := schedule.Next(from); got.Day() != 10 || got.Hour() != 2 {
+		t.Errorf("Next across the spring change = %v, want 02:30 on 10 March", got)
+	}
+}
+
+func TestNeverDueScheduleIsZero(t *testing.T) {
+	schedule, err := Parse("0 0 31 2 *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next of 31 February = %v, want zero", got)
+	}
+}
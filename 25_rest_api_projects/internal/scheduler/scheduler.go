@@ -0,0 +1,279 @@
+// Package scheduler runs recurring maintenance jobs inside the server
+// process. A job never overlaps itself: when it is due while the previous
+// run is still going, that turn is skipped and counted.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrDuplicateJob = errors.New("job already registered")
+
+// Func is the work of a job. It should return soon after ctx is done,
+// which happens on timeout and when the scheduler stops.
+type Func func(ctx context.Context) error
+
+// Job describes a registered job. Each run starts a random delay of up to
+// Jitter after it is due, so several servers do not all start at once, and
+// is cancelled after Timeout unless that is zero.
+type Job struct {
+	Name     string
+	Schedule string
+	Timeout  time.Duration
+	Jitter   time.Duration
+	Run      Func
+}
+
+const (
+	RunOK       = "ok"
+	RunFailed   = "failed"
+	RunTimedOut = "timeout"
+	RunCanceled = "canceled"
+)
+
+type Run struct {
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Status is a job's schedule and the outcome of its latest run.
+type Status struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Running  bool       `json:"running"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	LastRun  *Run       `json:"last_run,omitempty"`
+	Runs     int        `json:"runs"`
+	Failures int        `json:"failures"`
+	Skipped  int        `json:"skipped"`
+}
+
+type entry struct {
+	job      Job
+	schedule Schedule
+
+	running  bool
+	next     time.Time
+	last     *Run
+	runs     int
+	failures int
+	skipped  int
+}
+
+type Scheduler struct {
+	loc *time.Location
+
+	mu     sync.Mutex
+	jobs   []*entry
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a scheduler reading cron expressions in loc.
+func New(loc *time.Location) *Scheduler {
+	return &Scheduler{loc: loc}
+}
+
+// Add registers job. Jobs must be added before Start.
+func (s *Scheduler) Add(job Job) error {
+	schedule, err := Parse(job.Schedule, s.loc)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("job %s: %w: %q is never due", job.Name, ErrInvalidSchedule, job.Schedule)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.jobs {
+		if e.job.Name == job.Name {
+			return fmt.Errorf("%w: %s", ErrDuplicateJob, job.Name)
+		}
+	}
+
+	s.jobs = append(s.jobs, &entry{job: job, schedule: schedule})
+	return nil
+}
+
+// Start runs every registered job on its schedule until Stop is called.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, e := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, e)
+		}()
+	}
+}
+
+// Stop ends the schedule loops, cancels runs in progress and waits for
+// them to return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	due := time.Now()
+
+	for {
+		due = e.schedule.Next(due)
+
+		// After a long pause, such as a suspended machine, pick up from
+		// now instead of running every missed turn back to back.
+		if now := time.Now(); !due.IsZero() && due.Before(now) {
+			due = e.schedule.Next(now)
+		}
+
+		if due.IsZero() {
+			return
+		}
+
+		start := due
+		if e.job.Jitter > 0 {
+			start = start.Add(rand.N(e.job.Jitter))
+		}
+
+		s.mu.Lock()
+		e.next = start
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(start))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.trigger(ctx, e)
+	}
+}
+
+// trigger starts a run of e unless the previous one is still going.
+func (s *Scheduler) trigger(ctx context.Context, e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.running {
+		e.skipped++
+		slog.Warn("job still running, skipping this turn", slog.String("job", e.job.Name))
+		return
+	}
+
+	e.running = true
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, e)
+	}()
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	if e.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.job.Timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	err := call(ctx, e.job.Run)
+	elapsed := time.Since(started)
+
+	run := &Run{StartedAt: started.UTC(), Duration: elapsed.Round(time.Millisecond).String(), Status: RunOK}
+
+	if err != nil {
+		run.Error = err.Error()
+
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			run.Status = RunTimedOut
+		case errors.Is(ctx.Err(), context.Canceled):
+			run.Status = RunCanceled
+		default:
+			run.Status = RunFailed
+		}
+
+		slog.Error("job failed", slog.String("job", e.job.Name), slog.String("status", run.Status), slog.String("error", run.Error), slog.Duration("duration", elapsed))
+	} else {
+		slog.Info("job finished", slog.String("job", e.job.Name), slog.Duration("duration", elapsed))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.running = false
+	e.last = run
+	e.runs++
+	if err != nil {
+		e.failures++
+	}
+}
+
+// call runs fn, turning a panic into an error so one broken job cannot
+// take the server down.
+func call(ctx context.Context, fn Func) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// Status reports every job, sorted by name.
+func (s *Scheduler) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+
+	for _, e := range s.jobs {
+		status := Status{
+			Name:     e.job.Name,
+			Schedule: e.job.Schedule,
+			Running:  e.running,
+			Runs:     e.runs,
+			Failures: e.failures,
+			Skipped:  e.skipped,
+		}
+
+		if !e.next.IsZero() {
+			next := e.next.UTC()
+			status.NextRun = &next
+		}
+
+		if e.last != nil {
+			last := *e.last
+			status.LastRun = &last
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAddRejectsBadJobs(t *testing.T) {
+	s := New(time.UTC)
+
+	noop := func(context.Context) error { return nil }
+
+	if err := s.Add(Job{Name: "backup", Schedule: "@daily", Run: noop}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	tests := []struct {
+		job  Job
+		want error
+	}{
+		{Job{Name: "backup", Schedule: "@hourly", Run: noop}, ErrDuplicateJob},
+		{Job{Name: "broken", Schedule: "every day", Run: noop}, ErrInvalidSchedule},
+		{Job{Name: "never", Schedule: "0 0 30 2 *", Run: noop}, ErrInvalidSchedule},
+	}
+
+	for _, tt := range tests {
+		if err := s.Add(tt.job); !errors.Is(err, tt.want) {
+			t.Errorf("Add(%s) error = %v, want %v", tt.job.Name, err, tt.want)
+		}
+	}
+}
+
+// runOnce triggers the only job of s and waits for the run to finish.
+func runOnce(t *testing.T, s *Scheduler) Status {
+	t.Helper()
+
+	s.trigger(context.Background(), s.jobs[0])
+	s.wg.Wait()
+
+	return s.Status()[0]
+}
+
+func TestRunOutcomes(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		run     Func
+		want    string
+	}{
+		{"ok", 0, func(context.Context) error { return nil }, RunOK},
+		{"failed", 0, func(context.Context) error { return errors.New("disk full") }, RunFailed},
+		{"panic", 0, func(context.Context) error { panic("boom") }, RunFailed},
+		{"timeout", 10 * time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, RunTimedOut},
+	}
+
+	for _, tt := range tests {
+		s := New(time.UTC)
+		if err := s.Add(Job{Name: tt.name, Schedule: "@daily", Timeout: tt.timeout, Run: tt.run}); err != nil {
+			t.Fatal(err)
+		}
+
+		status := runOnce(t, s)
+
+		if status.LastRun == nil || status.LastRun.Status != tt.want || status.Runs != 1 {
+			t.Errorf("%s: status = %+v, last run %+v, want %s", tt.name, status, status.LastRun, tt.want)
+			continue
+		}
+
+		if failed := tt.want != RunOK; (status.Failures == 1) != failed || (status.LastRun.Error != "") != failed {
+			t.Errorf("%s: failures = %d, error = %q", tt.name, status.Failures, status.LastRun.Error)
+		}
+	}
+}
+
+func TestOverlappingTurnsAreSkipped(t *testing.T) {
+	s := New(time.UTC)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	err := s.Add(Job{Name: "slow", Schedule: "@hourly", Run: func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.trigger(context.Background(), s.jobs[0])
+	<-started
+
+	s.trigger(context.Background(), s.jobs[0])
+
+	if status := s.Status()[0]; !status.Running || status.Skipped != 1 {
+		t.Errorf("status while running = %+v, want running with one skipped turn", status)
+	}
+
+	close(release)
+	s.wg.Wait()
+
+	if status := s.Status()[0]; status.Running || status.Runs != 1 {
+		t.Errorf("status after the run = %+v, want one finished run", status)
+	}
+}
+
+func TestStartSchedulesAndStopCancels(t *testing.T) {
+	s := New(time.UTC)
+
+	for _, name := range []string{"purge", "backup"} {
+		if err := s.Add(Job{Name: name, Schedule: "@daily", Run: func(context.Context) error { return nil }}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.Start()
+
+	deadline := time.Now().Add(time.Second)
+	for s.Status()[0].NextRun == nil || s.Status()[1].NextRun == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("jobs were not scheduled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	statuses := s.Status()
+	if statuses[0].Name != "backup" || statuses[1].Name != "purge" {
+		t.Errorf("statuses = %+v, want them sorted by name", statuses)
+	}
+
+	tomorrow := time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if next := *statuses[0].NextRun; !next.Equal(tomorrow) {
+		t.Errorf("next run = %v, want %v", next, tomorrow)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return")
+	}
+}
@@ -0,0 +1,75 @@
+package seed
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Named fixtures live in fixtures/<name>.yaml and are compiled in, so tests
+// can load them regardless of their working directory.
+//
+//go:embed fixtures/*.yaml
+var fixtures embed.FS
+
+// Fixture loads the named built-in fixture.
+func Fixture(name string) (Dataset, error) {
+	data, err := fixtures.ReadFile(path.Join("fixtures", name+".yaml"))
+	if err != nil {
+		return Dataset{}, fmt.Errorf("fixture %q: %w (available: %s)", name, fs.ErrNotExist, strings.Join(Fixtures(), ", "))
+	}
+
+	return parse(name, data)
+}
+
+// Fixtures lists the names of the built-in fixtures.
+func Fixtures() []string {
+	entries, _ := fixtures.ReadDir("fixtures")
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadFile loads a fixture from a YAML file on disk.
+func LoadFile(filename string) (Dataset, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Dataset{}, err
+	}
+
+	return parse(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)), data)
+}
+
+func parse(name string, data []byte) (Dataset, error) {
+	var dataset Dataset
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&dataset); err != nil {
+		return Dataset{}, fmt.Errorf("fixture %q: %w", name, err)
+	}
+
+	if dataset.Name == "" {
+		dataset.Name = name
+	}
+
+	if err := dataset.Validate(); err != nil {
+		return Dataset{}, fmt.Errorf("fixture %q: %w", name, err)
+	}
+
+	return dataset, nil
+}
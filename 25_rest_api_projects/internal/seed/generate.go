@@ -0,0 +1,143 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+)
+
+// Options sizes a generated dataset.
+type Options struct {
+	Seed uint64
+	// Students and Courses are how many of each to generate.
+	Students int
+	Courses  int
+	// MaxEnrollments caps the courses per student; each student takes
+	// between one and MaxEnrollments of them.
+	MaxEnrollments int
+	// GradedRatio is the share of enrollments, between 0 and 1, that get a
+	// grade.
+	GradedRatio float64
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Seed:           1,
+		Students:       50,
+		Courses:        12,
+		MaxEnrollments: 4,
+		GradedRatio:    0.6,
+	}
+}
+
+var (
+	firstNames = []string{"Alice", "Bob", "Carol", "David", "Eve", "Farhan", "Grace", "Hasan", "Ines", "Jamal", "Kira", "Liam", "Maya", "Nadia", "Omar", "Priya", "Rafi", "Sara", "Tariq", "Uma", "Victor", "Wen", "Yusuf", "Zara"}
+	lastNames  = []string{"Ahmed", "Brown", "Chowdhury", "Diaz", "Evans", "Fischer", "Garcia", "Hossain", "Ito", "Johnson", "Khan", "Lopez", "Miller", "Nguyen", "Okafor", "Patel", "Rahman", "Silva", "Tanaka", "Walker"}
+
+	catalog = []Course{
+		{Code: "CS101", Title: "Introduction to Programming", Credits: 4},
+		{Code: "CS201", Title: "Data Structures", Credits: 4},
+		{Code: "CS301", Title: "Algorithms", Credits: 3},
+		{Code: "CS340", Title: "Databases", Credits: 3},
+		{Code: "CS350", Title: "Operating Systems", Credits: 3},
+		{Code: "MATH101", Title: "Calculus I", Credits: 4},
+		{Code: "MATH201", Title: "Linear Algebra", Credits: 3},
+		{Code: "MATH220", Title: "Discrete Mathematics", Credits: 3},
+		{Code: "STAT210", Title: "Probability and Statistics", Credits: 3},
+		{Code: "PHYS101", Title: "Physics I", Credits: 4},
+		{Code: "ENG101", Title: "Academic Writing", Credits: 2},
+		{Code: "ECON101", Title: "Principles of Economics", Credits: 3},
+	}
+
+	// gradePoints are the values of letter grades A through F.
+	gradePoints = []float64{4.0, 3.7, 3.3, 3.0, 2.7, 2.3, 2.0, 1.7, 1.3, 1.0, 0}
+
+	// statusWeights makes most generated students enrolled, with a few in
+	// every other status.
+	statusWeights = []struct {
+		status lifecycle.Status
+		weight int
+	}{
+		{lifecycle.StatusEnrolled, 70},
+		{lifecycle.StatusApplicant, 12},
+		{lifecycle.StatusGraduated, 8},
+		{lifecycle.StatusSuspended, 5},
+		{lifecycle.StatusWithdrawn, 5},
+	}
+)
+
+// Generate builds a dataset from opts. Every record is drawn from its own
+// stream derived from the seed, so the first N students are the same
+// whatever the requested volume and growing a dataset only adds records.
+func Generate(opts Options) Dataset {
+	dataset := Dataset{Name: fmt.Sprintf("generated-%d", opts.Seed)}
+
+	for i := range opts.Courses {
+		dataset.Courses = append(dataset.Courses, course(i))
+	}
+
+	for i := range opts.Students {
+		r := rand.New(rand.NewPCG(opts.Seed, uint64(i)))
+
+		first := firstNames[r.IntN(len(firstNames))]
+		last := lastNames[r.IntN(len(lastNames))]
+
+		student := Student{
+			Name:   first + " " + last,
+			Email:  fmt.Sprintf("%s.%s%d@example.edu", strings.ToLower(first), strings.ToLower(last), i+1),
+			Age:    17 + r.IntN(12),
+			Status: pickStatus(r),
+		}
+
+		dataset.Students = append(dataset.Students, student)
+
+		if student.Status == lifecycle.StatusApplicant || opts.Courses == 0 || opts.MaxEnrollments <= 0 {
+			continue
+		}
+
+		taken := 1 + r.IntN(min(opts.MaxEnrollments, opts.Courses))
+
+		for _, c := range r.Perm(opts.Courses)[:taken] {
+			enrollment := Enrollment{Student: student.Email, Course: dataset.Courses[c].Code}
+
+			if r.Float64() < opts.GradedRatio {
+				grade := gradePoints[r.IntN(len(gradePoints))]
+				enrollment.Grade = &grade
+			}
+
+			dataset.Enrollments = append(dataset.Enrollments, enrollment)
+		}
+	}
+
+	return dataset
+}
+
+// course returns the i-th catalog entry, continuing with numbered electives
+// once the catalog runs out.
+func course(i int) Course {
+	if i < len(catalog) {
+		return catalog[i]
+	}
+
+	n := i - len(catalog) + 1
+	return Course{Code: fmt.Sprintf("ELEC%03d", n), Title: fmt.Sprintf("Elective %d", n), Credits: 2 + n%3}
+}
+
+func pickStatus(r *rand.Rand) lifecycle.Status {
+	total := 0
+	for _, w := range statusWeights {
+		total += w.weight
+	}
+
+	n := r.IntN(total)
+	for _, w := range statusWeights {
+		if n < w.weight {
+			return w.status
+		}
+		n -= w.weight
+	}
+
+	return lifecycle.Initial
+}
@@ -0,0 +1,90 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFixturesLoadAndValidate(t *testing.T) {
+	names := Fixtures()
+	if len(names) == 0 {
+		t.Fatal("no built-in fixtures")
+	}
+
+	for _, name := range names {
+		dataset, err := Fixture(name)
+		if err != nil {
+			t.Errorf("Fixture(%q): %v", name, err)
+			continue
+		}
+		if dataset.Name == "" || len(dataset.Students) == 0 {
+			t.Errorf("Fixture(%q) = %+v, want a named dataset with students", name, dataset)
+		}
+	}
+
+	if _, err := Fixture("missing"); err == nil {
+		t.Error("Fixture of an unknown name succeeded")
+	}
+}
+
+func TestLoadFileRejectsInvalidDatasets(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":  "students:\n  - name: Ada\n    email: ada@example.edu\n    age: 20\n    nickname: ada\n",
+		"bad email":      "students:\n  - name: Ada\n    email: ada\n    age: 20\n",
+		"unknown status": "students:\n  - name: Ada\n    email: ada@example.edu\n    age: 20\n    status: expelled\n",
+		"dangling ref":   "courses:\n  - code: CS101\n    title: Programming\n    credits: 4\nenrollments:\n  - student: ada@example.edu\n    course: CS101\n",
+	}
+
+	for name, yaml := range tests {
+		path := filepath.Join(t.TempDir(), "fixture.yaml")
+		if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("%s: LoadFile accepted the dataset", name)
+		}
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	opts := DefaultOptions()
+
+	first, second := Generate(opts), Generate(opts)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("the same options generated different datasets")
+	}
+
+	if err := first.Validate(); err != nil {
+		t.Fatalf("generated dataset is invalid: %v", err)
+	}
+
+	if len(first.Students) != opts.Students || len(first.Courses) != opts.Courses {
+		t.Errorf("generated %d students and %d courses, want %d and %d", len(first.Students), len(first.Courses), opts.Students, opts.Courses)
+	}
+
+	opts.Seed++
+	if reflect.DeepEqual(first.Students, Generate(opts).Students) {
+		t.Error("a different seed generated the same students")
+	}
+}
+
+func TestGrowingADatasetKeepsExistingStudents(t *testing.T) {
+	opts := DefaultOptions()
+	small := Generate(opts)
+
+	opts.Students *= 2
+	large := Generate(opts)
+
+	if !reflect.DeepEqual(small.Students, large.Students[:len(small.Students)]) {
+		t.Error("doubling the student count changed the first students")
+	}
+
+	opts.Courses = len(catalog) + 2
+	if code := Generate(opts).Courses[len(catalog)].Code; !strings.HasPrefix(code, "ELEC") {
+		t.Errorf("course past the catalog = %s, want an elective", code)
+	}
+}
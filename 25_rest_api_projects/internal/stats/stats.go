@@ -0,0 +1,52 @@
+package stats
+
+// Stats mirrors the summary computed by calculateStats in 13_variadic_functions.
+type Stats struct {
+	Count   int
+	Sum     float64
+	Average float64
+	Min     float64
+	Max     float64
+}
+
+func Calculate(values ...float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+
+	stats := Stats{
+		Count: len(values),
+		Min:   values[0],
+		Max:   values[0],
+	}
+
+	for _, value := range values {
+		stats.Sum += value
+		if value < stats.Min {
+			stats.Min = value
+		}
+		if value > stats.Max {
+			stats.Max = value
+		}
+	}
+
+	stats.Average = stats.Sum / float64(stats.Count)
+	return stats
+}
+
+// WeightedAverage returns the average of values weighted by the matching
+// entry in weights, e.g. grade points weighted by course credits.
+func WeightedAverage(values []float64, weights []float64) float64 {
+	var sum, total float64
+
+	for i, value := range values {
+		sum += value * weights[i]
+		total += weights[i]
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return sum / total
+}
@@ -0,0 +1,27 @@
+package stats
+
+import "testing"
+
+func TestCalculate(t *testing.T) {
+	got := Calculate(2, 4, 3)
+	want := Stats{Count: 3, Sum: 9, Average: 3, Min: 2, Max: 4}
+
+	if got != want {
+		t.Errorf("Calculate(2, 4, 3) = %+v, want %+v", got, want)
+	}
+
+	if got := Calculate(); got != (Stats{}) {
+		t.Errorf("Calculate() = %+v, want the zero value", got)
+	}
+}
+
+func TestWeightedAverage(t *testing.T) {
+	// A 4.0 in a 4-credit course and a 2.0 in a 2-credit one.
+	if got := WeightedAverage([]float64{4, 2}, []float64{4, 2}); got < 3.333 || got > 3.334 {
+		t.Errorf("WeightedAverage = %v, want 3.33", got)
+	}
+
+	if got := WeightedAverage([]float64{4}, []float64{0}); got != 0 {
+		t.Errorf("WeightedAverage with no weight = %v, want 0", got)
+	}
+}
@@ -0,0 +1,102 @@
+// Package cached puts a read-through cache in front of student reads.
+package cached
+
+import (
+	"slices"
+
+	"github.com/faysal0x1/Go-Learn/internal/cache"
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// cachedStorage serves GetStudentById and GetStudents from memory and
+// invalidates them on every student write that goes through it. Writes made
+// by other processes, such as the admin commands, show up once the entries
+// expire.
+type cachedStorage struct {
+	storage.Storage
+	students *cache.Cache[int64, types.Student]
+	lists    *cache.Cache[string, []types.Student]
+}
+
+const allStudents = "all"
+
+// Wrap returns s with student reads cached according to cfg, or s itself
+// when caching is disabled.
+func Wrap(s storage.Storage, cfg config.Cache) storage.Storage {
+	if !cfg.Enabled {
+		return s
+	}
+
+	return &cachedStorage{
+		Storage:  s,
+		students: cache.New[int64, types.Student](cfg.TTL, cfg.MaxEntries),
+		lists:    cache.New[string, []types.Student](cfg.TTL, 1),
+	}
+}
+
+func (s *cachedStorage) GetStudentById(id int64) (types.Student, error) {
+	return s.students.GetOrLoad(id, func() (types.Student, error) {
+		return s.Storage.GetStudentById(id)
+	})
+}
+
+// GetStudents hands out a copy so callers cannot modify the cached list.
+func (s *cachedStorage) GetStudents() ([]types.Student, error) {
+	students, err := s.lists.GetOrLoad(allStudents, s.Storage.GetStudents)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(students), nil
+}
+
+func (s *cachedStorage) CreateStudent(name string, email string, age int) (int64, error) {
+	id, err := s.Storage.CreateStudent(name, email, age)
+	s.lists.Clear()
+	return id, err
+}
+
+func (s *cachedStorage) UpdateStudent(student types.Student) error {
+	err := s.Storage.UpdateStudent(student)
+	s.invalidate(student.Id)
+	return err
+}
+
+func (s *cachedStorage) DeleteStudent(id int64) error {
+	err := s.Storage.DeleteStudent(id)
+	s.invalidate(id)
+	return err
+}
+
+func (s *cachedStorage) TransitionStudent(id int64, event string) (types.StudentTransition, error) {
+	transition, err := s.Storage.TransitionStudent(id, event)
+	s.invalidate(id)
+	return transition, err
+}
+
+// Batch implements storage.Batcher when the wrapped storage does. Calls in
+// the batch bypass the cache, so reads see the batch's own writes and
+// nothing uncommitted gets cached, and the whole cache is dropped after
+// it, committed or not.
+func (s *cachedStorage) Batch(fn func(tx storage.Storage) error) error {
+	batcher, ok := s.Storage.(storage.Batcher)
+	if !ok {
+		return storage.ErrNoBatch
+	}
+
+	defer func() {
+		s.students.Clear()
+		s.lists.Clear()
+	}()
+
+	return batcher.Batch(fn)
+}
+
+// invalidate runs whether or not the write succeeded; a failed write may
+// still have changed the row, and a spurious miss is cheap.
+func (s *cachedStorage) invalidate(id int64) {
+	s.students.Delete(id)
+	s.lists.Clear()
+}
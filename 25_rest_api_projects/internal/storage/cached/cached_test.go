@@ -0,0 +1,60 @@
+package cached
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+)
+
+func TestWritesInvalidateCachedReads(t *testing.T) {
+	db, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { db.Db.Close() })
+
+	s := Wrap(db, config.Cache{Enabled: true, TTL: time.Hour, MaxEntries: 10})
+
+	id, err := s.CreateStudent("Ada", "ada@example.edu", 20)
+	if err != nil {
+		t.Fatalf("CreateStudent: %v", err)
+	}
+
+	student, err := s.GetStudentById(id)
+	if err != nil {
+		t.Fatalf("GetStudentById: %v", err)
+	}
+	if list, _ := s.GetStudents(); len(list) != 1 {
+		t.Fatalf("GetStudents = %+v, want one student", list)
+	}
+
+	// A write behind the cache's back is not seen until invalidation.
+	if _, err := db.CreateStudent("Grace", "grace@example.edu", 30); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := s.GetStudents(); len(list) != 1 {
+		t.Errorf("GetStudents = %+v, want the cached list", list)
+	}
+
+	student.Name = "Ada Lovelace"
+	if err := s.UpdateStudent(student); err != nil {
+		t.Fatalf("UpdateStudent: %v", err)
+	}
+
+	if got, _ := s.GetStudentById(id); got.Name != "Ada Lovelace" {
+		t.Errorf("GetStudentById after update = %+v", got)
+	}
+	if list, _ := s.GetStudents(); len(list) != 2 {
+		t.Errorf("GetStudents after update = %+v, want a fresh list", list)
+	}
+
+	if err := s.DeleteStudent(id); err != nil {
+		t.Fatalf("DeleteStudent: %v", err)
+	}
+	if _, err := s.GetStudentById(id); err == nil {
+		t.Error("deleted student was served from the cache")
+	}
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+const apiKeyColumns = "id, tenant_id, name, prefix, created_at, last_used_at"
+
+// CreateAPIKey issues a key for this view's tenant. The key only opens
+// requests that resolve to that tenant.
+func (s *Sqlite) CreateAPIKey(name string, prefix string, hash string) (int64, error) {
+	result, err := s.Db.Exec("INSERT INTO api_keys (tenant_id, name, prefix, hash, created_at) VALUES (?, ?, ?, ?, ?)",
+		s.tenant, name, prefix, hash, time.Now().UTC())
+
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return result.LastInsertId()
+}
+
+// AuthenticateAPIKey looks up the key with the given hash, whatever its
+// tenant, and records that it was used. Callers compare key.TenantId with
+// the tenant of the request.
+func (s *Sqlite) AuthenticateAPIKey(hash string) (types.APIKey, error) {
+	var key types.APIKey
+
+	err := s.Db.QueryRow("UPDATE api_keys SET last_used_at = ? WHERE hash = ? RETURNING "+apiKeyColumns, time.Now().UTC(), hash).
+		Scan(&key.Id, &key.TenantId, &key.Name, &key.Prefix, &key.CreatedAt, &key.LastUsedAt)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.APIKey{}, fmt.Errorf("api key: %w", storage.ErrNotFound)
+		}
+		return types.APIKey{}, err
+	}
+
+	return key, nil
+}
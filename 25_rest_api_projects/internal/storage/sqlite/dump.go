@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// Export reads every student, course and enrollment of the tenant.
+func (s *Sqlite) Export() (types.Dump, error) {
+	dump := types.Dump{ExportedAt: time.Now().UTC()}
+
+	var err error
+
+	if dump.Students, err = s.GetStudents(); err != nil {
+		return types.Dump{}, err
+	}
+
+	if dump.Courses, err = s.GetCourses(); err != nil {
+		return types.Dump{}, err
+	}
+
+	dump.Enrollments, err = s.queryEnrollments("SELECT id, student_id, course_id, grade, enrolled_at FROM enrollments WHERE tenant_id = ? ORDER BY id", s.tenant)
+	if err != nil {
+		return types.Dump{}, err
+	}
+
+	return dump, nil
+}
+
+// Import inserts a dump into the tenant with its original ids in one
+// transaction. Rows
+// that clash with existing ones fail the whole import with
+// storage.ErrAlreadyExists.
+func (s *Sqlite) Import(dump types.Dump) error {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, student := range dump.Students {
+		_, err := tx.Exec("INSERT INTO students (tenant_id, "+studentColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+			s.tenant, student.Id, student.Name, student.Email, student.Age, student.Status, student.StatusChangedAt)
+		if err != nil {
+			return translateError(err)
+		}
+	}
+
+	for _, course := range dump.Courses {
+		_, err := tx.Exec("INSERT INTO courses (tenant_id, id, code, title, credits) VALUES (?, ?, ?, ?, ?)",
+			s.tenant, course.Id, course.Code, course.Title, course.Credits)
+		if err != nil {
+			return translateError(err)
+		}
+	}
+
+	for _, enrollment := range dump.Enrollments {
+		_, err := tx.Exec("INSERT INTO enrollments (tenant_id, id, student_id, course_id, grade, enrolled_at) VALUES (?, ?, ?, ?, ?, ?)",
+			s.tenant, enrollment.Id, enrollment.StudentId, enrollment.CourseId, enrollment.Grade, enrollment.EnrolledAt)
+		if err != nil {
+			return translateError(err)
+		}
+	}
+
+	return tx.Commit()
+}
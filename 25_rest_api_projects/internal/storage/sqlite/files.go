@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+const studentFileColumns = "id, student_id, kind, filename, content_type, size, sha256, thumbnail_sha256, created_at"
+
+func scanStudentFile(scan func(dest ...any) error) (types.StudentFile, error) {
+	var file types.StudentFile
+
+	err := scan(&file.Id, &file.StudentId, &file.Kind, &file.Filename, &file.ContentType,
+		&file.Size, &file.SHA256, &file.ThumbnailSHA256, &file.CreatedAt)
+
+	return file, err
+}
+
+func (s *Sqlite) CreateStudentFile(file types.StudentFile) (int64, error) {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := s.expectOwned(tx, "students", file.StudentId); err != nil {
+		return 0, fmt.Errorf("student %d: %w", file.StudentId, storage.ErrNotFound)
+	}
+
+	result, err := tx.Exec(`INSERT INTO student_files
+		(tenant_id, student_id, kind, filename, content_type, size, sha256, thumbnail_sha256, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.tenant, file.StudentId, file.Kind, file.Filename, file.ContentType, file.Size,
+		file.SHA256, file.ThumbnailSHA256, time.Now().UTC())
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (s *Sqlite) GetStudentFile(studentId int64, id int64) (types.StudentFile, error) {
+	file, err := scanStudentFile(s.Db.QueryRow("SELECT "+studentFileColumns+" FROM student_files WHERE id = ? AND student_id = ? AND tenant_id = ?",
+		id, studentId, s.tenant).Scan)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.StudentFile{}, fmt.Errorf("file %d: %w", id, storage.ErrNotFound)
+		}
+		return types.StudentFile{}, err
+	}
+
+	return file, nil
+}
+
+func (s *Sqlite) GetStudentFiles(studentId int64) ([]types.StudentFile, error) {
+	rows, err := s.Db.Query("SELECT "+studentFileColumns+" FROM student_files WHERE student_id = ? AND tenant_id = ? ORDER BY id",
+		studentId, s.tenant)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []types.StudentFile{}
+
+	for rows.Next() {
+		file, err := scanStudentFile(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+func (s *Sqlite) DeleteStudentFile(studentId int64, id int64) error {
+	result, err := s.Db.Exec("DELETE FROM student_files WHERE id = ? AND student_id = ? AND tenant_id = ?", id, studentId, s.tenant)
+	if err != nil {
+		return translateError(err)
+	}
+
+	return expectAffected(result, fmt.Errorf("file %d: %w", id, storage.ErrNotFound))
+}
+
+// BlobReferenced reports whether any file of any tenant still uses hash as
+// its content or thumbnail, since identical uploads share one blob.
+func (s *Sqlite) BlobReferenced(hash string) (bool, error) {
+	var referenced bool
+
+	err := s.Db.QueryRow("SELECT EXISTS (SELECT 1 FROM student_files WHERE sha256 = ? OR thumbnail_sha256 = ?)", hash, hash).
+		Scan(&referenced)
+
+	return referenced, err
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// QueueMail adds mail to the queue unless the same event already queued a
+// message for the recipient. It reports whether a row was added.
+func (s *Sqlite) QueueMail(mail types.Mail) (bool, error) {
+	result, err := s.Db.Exec(`INSERT INTO mail_queue
+		(tenant_id, event_id, template, recipient, subject, text_body, html_body, status, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (event_id, recipient) DO NOTHING`,
+		mail.Tenant, mail.EventId, mail.Template, mail.Recipient, mail.Subject, mail.Text, mail.HTML,
+		types.MailPending, mail.NextAttemptAt, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("queue mail: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// DueMails returns pending mail of every tenant whose next attempt is at or
+// before now, oldest first.
+func (s *Sqlite) DueMails(now time.Time, limit int) ([]types.Mail, error) {
+	rows, err := s.Db.Query(`SELECT id, tenant_id, event_id, template, recipient, subject, text_body, html_body,
+		status, attempts, last_error, next_attempt_at, created_at, sent_at
+		FROM mail_queue WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id LIMIT ?`, types.MailPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mails := []types.Mail{}
+
+	for rows.Next() {
+		var mail types.Mail
+		var sentAt sql.NullTime
+
+		err := rows.Scan(&mail.Id, &mail.Tenant, &mail.EventId, &mail.Template, &mail.Recipient, &mail.Subject,
+			&mail.Text, &mail.HTML, &mail.Status, &mail.Attempts, &mail.LastError, &mail.NextAttemptAt,
+			&mail.CreatedAt, &sentAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if sentAt.Valid {
+			mail.SentAt = &sentAt.Time
+		}
+
+		mails = append(mails, mail)
+	}
+
+	return mails, rows.Err()
+}
+
+// UpdateMail stores the outcome of a send attempt.
+func (s *Sqlite) UpdateMail(mail types.Mail) error {
+	_, err := s.Db.Exec("UPDATE mail_queue SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, sent_at = ? WHERE id = ?",
+		mail.Status, mail.Attempts, mail.LastError, mail.NextAttemptAt, mail.SentAt, mail.Id)
+	return err
+}
+
+// PruneMail deletes sent and failed mail of every tenant created before
+// the cutoff.
+func (s *Sqlite) PruneMail(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.Db.ExecContext(ctx, "DELETE FROM mail_queue WHERE status != ? AND created_at < ?", types.MailPending, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune mail: %w", err)
+	}
+
+	return result.RowsAffected()
+}
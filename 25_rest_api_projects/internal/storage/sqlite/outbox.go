@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// SetOutboxNotifier registers fn to be called after every commit that
+// wrote to the outbox, so the relay does not have to wait for its next poll.
+func (s *Sqlite) SetOutboxNotifier(fn func()) {
+	s.outboxNotify = fn
+}
+
+// inTx runs fn in a transaction and commits it. Every mutation that
+// produces an event goes through here so the event is written atomically
+// with the change. On a view from Batch fn joins the batch's transaction,
+// which Batch commits.
+func (s *Sqlite) inTx(fn func(tx *sql.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if s.outboxNotify != nil {
+		s.outboxNotify()
+	}
+
+	return nil
+}
+
+// Batch implements storage.Batcher. The view fn gets runs every student,
+// course and enrollment call in one transaction, events included, so the
+// relay sees the batch's events only once it is committed. The view must
+// not be used after fn returns.
+func (s *Sqlite) Batch(fn func(tx storage.Storage) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	view := *s
+	view.tx = tx
+
+	if err := fn(&view); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if s.outboxNotify != nil {
+		s.outboxNotify()
+	}
+
+	return nil
+}
+
+// enqueue stamps event with this view's tenant so consumers can route it.
+func (s *Sqlite) enqueue(tx *sql.Tx, event events.Event) error {
+	event.Tenant = s.tenant
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO outbox (event_id, event_type, payload, created_at) VALUES (?, ?, ?, ?)",
+		event.Id, event.Type, payload, event.OccurredAt)
+	return err
+}
+
+// enqueueStudent records the student as it is after the change.
+func (s *Sqlite) enqueueStudent(tx *sql.Tx, eventType events.Type, id int64) error {
+	student, err := s.getStudent(tx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.enqueue(tx, events.New(eventType, id, student))
+}
+
+func (s *Sqlite) enqueueEnrollment(tx *sql.Tx, eventType events.Type, studentId int64, courseId int64) error {
+	var enrollment types.Enrollment
+	var grade sql.NullFloat64
+
+	err := tx.QueryRow("SELECT id, student_id, course_id, grade, enrolled_at FROM enrollments WHERE student_id = ? AND course_id = ? AND tenant_id = ?", studentId, courseId, s.tenant).
+		Scan(&enrollment.Id, &enrollment.StudentId, &enrollment.CourseId, &grade, &enrollment.EnrolledAt)
+	if err != nil {
+		return err
+	}
+
+	if grade.Valid {
+		enrollment.Grade = &grade.Float64
+	}
+
+	return s.enqueue(tx, events.NewEnrollment(eventType, studentId, courseId, enrollment))
+}
+
+// OutboxAfter returns up to limit messages with an id above afterId, oldest
+// first.
+func (s *Sqlite) OutboxAfter(afterId int64, limit int) ([]types.OutboxMessage, error) {
+	rows, err := s.Db.Query("SELECT id, event_id, event_type, payload, created_at FROM outbox WHERE id > ? ORDER BY id LIMIT ?", afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []types.OutboxMessage{}
+
+	for rows.Next() {
+		var message types.OutboxMessage
+
+		if err := rows.Scan(&message.Id, &message.EventId, &message.EventType, &message.Payload, &message.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// OutboxOffset is the id of the last message sink has accepted, or zero.
+func (s *Sqlite) OutboxOffset(sink string) (int64, error) {
+	var id int64
+
+	err := s.Db.QueryRow("SELECT last_id FROM outbox_offsets WHERE sink = ?", sink).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return id, err
+}
+
+func (s *Sqlite) SetOutboxOffset(sink string, id int64) error {
+	_, err := s.Db.Exec("INSERT INTO outbox_offsets (sink, last_id, updated_at) VALUES (?, ?, ?) ON CONFLICT (sink) DO UPDATE SET last_id = excluded.last_id, updated_at = excluded.updated_at",
+		sink, id, time.Now().UTC())
+	return err
+}
+
+// PruneOutbox deletes messages created before the cutoff that every one of
+// sinks has already accepted.
+func (s *Sqlite) PruneOutbox(sinks []string, before time.Time) (int64, error) {
+	if len(sinks) == 0 {
+		return 0, nil
+	}
+
+	var delivered int64 = -1
+
+	for _, sink := range sinks {
+		id, err := s.OutboxOffset(sink)
+		if err != nil {
+			return 0, err
+		}
+
+		if delivered < 0 || id < delivered {
+			delivered = id
+		}
+	}
+
+	result, err := s.Db.Exec("DELETE FROM outbox WHERE id <= ? AND created_at < ?", delivered, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune outbox: %w", err)
+	}
+
+	return result.RowsAffected()
+}
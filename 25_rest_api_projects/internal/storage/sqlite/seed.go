@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/seed"
+)
+
+// Seed inserts the records of dataset that are not stored yet, all in one
+// transaction. Students are matched by email, courses by code and
+// enrollments by student and course; existing rows are left as they are.
+func (s *Sqlite) Seed(dataset seed.Dataset) (seed.Result, error) {
+	var result seed.Result
+
+	if err := dataset.Validate(); err != nil {
+		return result, err
+	}
+
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	studentIds := map[string]int64{}
+
+	for _, student := range dataset.Students {
+		id, created, err := findOrInsert(tx,
+			"SELECT id FROM students WHERE tenant_id = ? AND email = ? ORDER BY id LIMIT 1", []any{s.tenant, student.Email},
+			"INSERT INTO students (tenant_id, name, email, age, status, status_changed_at) VALUES (?, ?, ?, ?, ?, ?)",
+			[]any{s.tenant, student.Name, student.Email, student.Age, statusOrInitial(student.Status), now})
+		if err != nil {
+			return result, fmt.Errorf("student %s: %w", student.Email, err)
+		}
+
+		studentIds[student.Email] = id
+		if created {
+			result.StudentsCreated++
+		} else {
+			result.StudentsExisting++
+		}
+	}
+
+	courseIds := map[string]int64{}
+
+	for _, course := range dataset.Courses {
+		id, created, err := findOrInsert(tx,
+			"SELECT id FROM courses WHERE tenant_id = ? AND code = ?", []any{s.tenant, course.Code},
+			"INSERT INTO courses (tenant_id, code, title, credits) VALUES (?, ?, ?, ?)",
+			[]any{s.tenant, course.Code, course.Title, course.Credits})
+		if err != nil {
+			return result, fmt.Errorf("course %s: %w", course.Code, err)
+		}
+
+		courseIds[course.Code] = id
+		if created {
+			result.CoursesCreated++
+		} else {
+			result.CoursesExisting++
+		}
+	}
+
+	for _, enrollment := range dataset.Enrollments {
+		res, err := tx.Exec("INSERT INTO enrollments (tenant_id, student_id, course_id, grade, enrolled_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (student_id, course_id) DO NOTHING",
+			s.tenant, studentIds[enrollment.Student], courseIds[enrollment.Course], enrollment.Grade, now)
+		if err != nil {
+			return result, fmt.Errorf("enrollment %s in %s: %w", enrollment.Student, enrollment.Course, translateError(err))
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return result, err
+		}
+
+		if n > 0 {
+			result.EnrollmentsCreated++
+		} else {
+			result.EnrollmentsExisting++
+		}
+	}
+
+	return result, tx.Commit()
+}
+
+func findOrInsert(tx *sql.Tx, find string, findArgs []any, insert string, insertArgs []any) (int64, bool, error) {
+	var id int64
+
+	err := tx.QueryRow(find, findArgs...).Scan(&id)
+	if err == nil {
+		return id, false, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, err
+	}
+
+	res, err := tx.Exec(insert, insertArgs...)
+	if err != nil {
+		return 0, false, translateError(err)
+	}
+
+	id, err = res.LastInsertId()
+	return id, true, err
+}
+
+func statusOrInitial(status lifecycle.Status) lifecycle.Status {
+	if status == "" {
+		return lifecycle.Initial
+	}
+	return status
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/seed"
+)
+
+func TestSeedingTwiceChangesNothing(t *testing.T) {
+	s := newTestStorage(t)
+
+	dataset, err := seed.Fixture("small")
+	if err != nil {
+		t.Fatalf("loading fixture: %v", err)
+	}
+
+	first, err := s.Seed(dataset)
+	if err != nil {
+		t.Fatalf("first Seed: %v", err)
+	}
+
+	if first.StudentsCreated != len(dataset.Students) || first.CoursesCreated != len(dataset.Courses) || first.EnrollmentsCreated != len(dataset.Enrollments) {
+		t.Errorf("first run = %+v, want every record created", first)
+	}
+
+	before, err := s.Export()
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	second, err := s.Seed(dataset)
+	if err != nil {
+		t.Fatalf("second Seed: %v", err)
+	}
+
+	want := seed.Result{
+		StudentsExisting:    len(dataset.Students),
+		CoursesExisting:     len(dataset.Courses),
+		EnrollmentsExisting: len(dataset.Enrollments),
+	}
+	if second != want {
+		t.Errorf("second run = %+v, want %+v", second, want)
+	}
+
+	after, err := s.Export()
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	if len(after.Students) != len(before.Students) || len(after.Courses) != len(before.Courses) || len(after.Enrollments) != len(before.Enrollments) {
+		t.Errorf("seeding twice changed the row counts from %d/%d/%d to %d/%d/%d",
+			len(before.Students), len(before.Courses), len(before.Enrollments),
+			len(after.Students), len(after.Courses), len(after.Enrollments))
+	}
+}
+
+func TestSeedIsScopedToTheTenant(t *testing.T) {
+	s := newTestStorage(t)
+
+	dataset := seed.Generate(seed.Options{Seed: 7, Students: 5, Courses: 3, MaxEnrollments: 2, GradedRatio: 0.5})
+
+	if _, err := s.ForTenant("north").Seed(dataset); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+
+	result, err := s.ForTenant("south").Seed(dataset)
+	if err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+
+	if result.StudentsCreated != 5 || result.StudentsExisting != 0 {
+		t.Errorf("seeding a second tenant = %+v, want its own students", result)
+	}
+}
@@ -0,0 +1,821 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Sqlite stores every tenant's data in one database. Each value is bound
+// to one tenant and all its queries filter on that tenant's id; use
+// ForTenant to get a view of another tenant.
+type Sqlite struct {
+	Db *sql.DB
+
+	tenant       string
+	outboxNotify func()
+
+	// tx is set on the views Batch hands out.
+	tx *sql.Tx
+}
+
+// New opens the database and brings its schema up to date.
+func New(cfg *config.Config) (*Sqlite, error) {
+	s, err := Open(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Migrate(); err != nil {
+		s.Db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Open connects to the database at cfg.StoragePath without touching the
+// schema.
+func Open(cfg *config.Config) (*Sqlite, error) {
+	db, err := sql.Open("sqlite3", cfg.StoragePath+"?_foreign_keys=on")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Sqlite{
+		Db:     db,
+		tenant: tenant.Default,
+	}, nil
+}
+
+// ForTenant returns a view of the same database confined to tenant. It
+// shares the connection pool and the outbox notifier, so call
+// SetOutboxNotifier before creating views.
+func (s *Sqlite) ForTenant(tenant string) *Sqlite {
+	view := *s
+	view.tenant = tenant
+	return &view
+}
+
+// Tenant is the tenant this view is confined to.
+func (s *Sqlite) Tenant() string {
+	return s.tenant
+}
+
+// Migrate creates missing tables, indexes and columns. It is safe to run
+// against a database that is already up to date.
+func (s *Sqlite) Migrate() error {
+	_, err := s.Db.Exec(`
+	CREATE TABLE IF NOT EXISTS students (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		name TEXT NOT NULL,
+		email TEXT NOT NULL,
+		age INTEGER NOT NULL,
+		status TEXT NOT NULL DEFAULT 'applicant',
+		status_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS student_transitions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+		event TEXT NOT NULL,
+		from_status TEXT NOT NULL,
+		to_status TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS courses (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		code TEXT NOT NULL,
+		title TEXT NOT NULL,
+		credits INTEGER NOT NULL CHECK (credits > 0),
+		UNIQUE (tenant_id, code)
+	);
+
+	CREATE TABLE IF NOT EXISTS enrollments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+		course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+		grade REAL CHECK (grade IS NULL OR (grade >= 0 AND grade <= 4)),
+		enrolled_at TIMESTAMP NOT NULL,
+		UNIQUE (student_id, course_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT NOT NULL DEFAULT '',
+		enabled INTEGER NOT NULL DEFAULT 1,
+		consecutive_failures INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+		event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		payload BLOB NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		response_code INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		next_attempt_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		delivered_at TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		prefix TEXT NOT NULL,
+		hash TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMP NOT NULL,
+		last_used_at TIMESTAMP,
+		tenant_id TEXT NOT NULL DEFAULT 'default'
+	);
+
+	CREATE TABLE IF NOT EXISTS outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		payload BLOB NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS outbox_offsets (
+		sink TEXT PRIMARY KEY,
+		last_id INTEGER NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS student_files (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		student_id INTEGER NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+		kind TEXT NOT NULL,
+		filename TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		sha256 TEXT NOT NULL,
+		thumbnail_sha256 TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_student_files_student ON student_files (tenant_id, student_id);
+	CREATE INDEX IF NOT EXISTS idx_student_files_sha256 ON student_files (sha256);
+	CREATE INDEX IF NOT EXISTS idx_student_files_thumbnail ON student_files (thumbnail_sha256);
+
+	CREATE TABLE IF NOT EXISTS mail_queue (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		event_id TEXT NOT NULL,
+		template TEXT NOT NULL,
+		recipient TEXT NOT NULL,
+		subject TEXT NOT NULL,
+		text_body TEXT NOT NULL,
+		html_body TEXT NOT NULL,
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		next_attempt_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		sent_at TIMESTAMP,
+		UNIQUE (event_id, recipient)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_mail_queue_due ON mail_queue (status, next_attempt_at);`)
+
+	if err != nil {
+		return err
+	}
+
+	// Databases created before the lifecycle and tenant columns existed
+	// only get the new columns added; CREATE TABLE IF NOT EXISTS leaves
+	// them untouched. Existing rows belong to the default tenant.
+	err = addColumns(s.Db, "students", map[string]string{
+		"status":            "TEXT NOT NULL DEFAULT 'applicant'",
+		"status_changed_at": "TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00'",
+		"tenant_id":         "TEXT NOT NULL DEFAULT 'default'",
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"student_transitions", "enrollments", "webhooks", "api_keys"} {
+		if err := addColumns(s.Db, table, map[string]string{"tenant_id": "TEXT NOT NULL DEFAULT 'default'"}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.rebuildCourses(); err != nil {
+		return err
+	}
+
+	_, err = s.Db.Exec(`
+	CREATE INDEX IF NOT EXISTS idx_students_tenant ON students (tenant_id, id);
+	CREATE INDEX IF NOT EXISTS idx_enrollments_tenant ON enrollments (tenant_id, student_id);
+	CREATE INDEX IF NOT EXISTS idx_webhooks_tenant ON webhooks (tenant_id);`)
+
+	if err != nil {
+		return err
+	}
+
+	// An event redelivered by the outbox must not queue a second delivery.
+	// Databases from before the index may already hold duplicates; the
+	// oldest of each is kept.
+	_, err = s.Db.Exec(`
+	DELETE FROM webhook_deliveries WHERE id NOT IN (SELECT MIN(id) FROM webhook_deliveries GROUP BY webhook_id, event_id);
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id);`)
+
+	return err
+}
+
+// rebuildCourses moves a courses table from before tenancy, where codes
+// were unique across the whole database, to one where they are unique per
+// tenant. SQLite cannot change constraints in place, so the table is
+// copied with foreign keys off to keep enrollments from cascading away.
+func (s *Sqlite) rebuildCourses() error {
+	var schema string
+
+	if err := s.Db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'courses'").Scan(&schema); err != nil {
+		return err
+	}
+
+	if strings.Contains(schema, "UNIQUE (tenant_id, code)") {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	conn, err := s.Db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+	CREATE TABLE courses_new (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant_id TEXT NOT NULL DEFAULT 'default',
+		code TEXT NOT NULL,
+		title TEXT NOT NULL,
+		credits INTEGER NOT NULL CHECK (credits > 0),
+		UNIQUE (tenant_id, code)
+	);
+
+	INSERT INTO courses_new (id, code, title, credits) SELECT id, code, title, credits FROM courses;
+	DROP TABLE courses;
+	ALTER TABLE courses_new RENAME TO courses;`)
+	if err != nil {
+		return fmt.Errorf("rebuild courses: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func addColumns(db *sql.DB, table string, columns map[string]string) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]bool{}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for column, definition := range columns {
+		if existing[column] {
+			continue
+		}
+
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// translateError maps SQLite constraint failures onto the storage sentinel
+// errors so handlers can pick a status code without knowing the driver.
+func translateError(err error) error {
+	var sqliteErr sqlite3.Error
+
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintForeignKey:
+			return fmt.Errorf("%w: %s", storage.ErrInvalidRef, sqliteErr.Error())
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return fmt.Errorf("%w: %s", storage.ErrAlreadyExists, sqliteErr.Error())
+		}
+	}
+
+	return err
+}
+
+func (s *Sqlite) CreateStudent(name string, email string, age int) (int64, error) {
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec("INSERT INTO students (tenant_id, name, email, age, status, status_changed_at) VALUES (?, ?, ?, ?, ?, ?)",
+			s.tenant, name, email, age, lifecycle.Initial, time.Now().UTC())
+		if err != nil {
+			return translateError(err)
+		}
+
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+
+		return s.enqueueStudent(tx, events.StudentCreated, id)
+	})
+
+	return id, err
+}
+
+func (s *Sqlite) GetStudentById(id int64) (types.Student, error) {
+	return s.getStudent(s.conn(), id)
+}
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	queryRower
+	Query(query string, args ...any) (*sql.Rows, error)
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// conn is where queries run: the batch's transaction on a view from Batch,
+// the connection pool otherwise.
+func (s *Sqlite) conn() querier {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.Db
+}
+
+func (s *Sqlite) getStudent(q queryRower, id int64) (types.Student, error) {
+	var student types.Student
+
+	err := q.QueryRow("SELECT "+studentColumns+" FROM students WHERE id = ? AND tenant_id = ? LIMIT 1", id, s.tenant).
+		Scan(studentFields(&student)...)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.Student{}, fmt.Errorf("student %d: %w", id, storage.ErrNotFound)
+		}
+		return types.Student{}, err
+	}
+
+	return student, nil
+}
+
+func (s *Sqlite) GetStudents() ([]types.Student, error) {
+	return s.queryStudents("SELECT "+studentColumns+" FROM students WHERE tenant_id = ? ORDER BY id", s.tenant)
+}
+
+// GetStudentsPage returns up to limit students with an id above afterId, in
+// id order, for keyset pagination.
+func (s *Sqlite) GetStudentsPage(afterId int64, limit int) ([]types.Student, error) {
+	return s.queryStudents("SELECT "+studentColumns+" FROM students WHERE tenant_id = ? AND id > ? ORDER BY id LIMIT ?", s.tenant, afterId, limit)
+}
+
+func (s *Sqlite) GetStudentsByIds(ids []int64) ([]types.Student, error) {
+	if len(ids) == 0 {
+		return []types.Student{}, nil
+	}
+
+	placeholders, args := inClause(ids)
+	return s.queryStudents("SELECT "+studentColumns+" FROM students WHERE tenant_id = ? AND id IN ("+placeholders+") ORDER BY id", append([]any{s.tenant}, args...)...)
+}
+
+func (s *Sqlite) queryStudents(query string, args ...any) ([]types.Student, error) {
+	rows, err := s.conn().Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	students := []types.Student{}
+
+	for rows.Next() {
+		var student types.Student
+
+		if err := rows.Scan(studentFields(&student)...); err != nil {
+			return nil, err
+		}
+
+		students = append(students, student)
+	}
+
+	return students, rows.Err()
+}
+
+func (s *Sqlite) UpdateStudent(student types.Student) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec("UPDATE students SET name = ?, email = ?, age = ? WHERE id = ? AND tenant_id = ?",
+			student.Name, student.Email, student.Age, student.Id, s.tenant)
+		if err != nil {
+			return translateError(err)
+		}
+
+		if err := expectAffected(result, fmt.Errorf("student %d: %w", student.Id, storage.ErrNotFound)); err != nil {
+			return err
+		}
+
+		return s.enqueueStudent(tx, events.StudentUpdated, student.Id)
+	})
+}
+
+func (s *Sqlite) DeleteStudent(id int64) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec("DELETE FROM students WHERE id = ? AND tenant_id = ?", id, s.tenant)
+		if err != nil {
+			return translateError(err)
+		}
+
+		if err := expectAffected(result, fmt.Errorf("student %d: %w", id, storage.ErrNotFound)); err != nil {
+			return err
+		}
+
+		return s.enqueue(tx, events.New(events.StudentDeleted, id, nil))
+	})
+}
+
+// TransitionStudent applies a lifecycle event and records it, reading and
+// updating the status in one transaction so concurrent transitions cannot
+// both succeed from the same starting status.
+func (s *Sqlite) TransitionStudent(id int64, event string) (types.StudentTransition, error) {
+	var transition types.StudentTransition
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		var from lifecycle.Status
+
+		err := tx.QueryRow("SELECT status FROM students WHERE id = ? AND tenant_id = ?", id, s.tenant).Scan(&from)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("student %d: %w", id, storage.ErrNotFound)
+			}
+			return err
+		}
+
+		to, err := lifecycle.Next(from, event)
+		if err != nil {
+			return err
+		}
+
+		transition = types.StudentTransition{
+			StudentId: id,
+			Event:     event,
+			From:      from,
+			To:        to,
+			CreatedAt: time.Now().UTC(),
+		}
+
+		result, err := tx.Exec("UPDATE students SET status = ?, status_changed_at = ? WHERE id = ? AND status = ?",
+			to, transition.CreatedAt, id, from)
+		if err != nil {
+			return translateError(err)
+		}
+
+		err = expectAffected(result, fmt.Errorf("%w: student %d is no longer %s", lifecycle.ErrIllegalTransition, id, from))
+		if err != nil {
+			return err
+		}
+
+		result, err = tx.Exec("INSERT INTO student_transitions (tenant_id, student_id, event, from_status, to_status, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+			s.tenant, id, event, from, to, transition.CreatedAt)
+		if err != nil {
+			return translateError(err)
+		}
+
+		if transition.Id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+
+		return s.enqueue(tx, events.New(events.StudentTransitioned, id, transition))
+	})
+
+	if err != nil {
+		return types.StudentTransition{}, err
+	}
+
+	return transition, nil
+}
+
+func (s *Sqlite) GetStudentTransitions(id int64) ([]types.StudentTransition, error) {
+	rows, err := s.conn().Query("SELECT id, student_id, event, from_status, to_status, created_at FROM student_transitions WHERE student_id = ? AND tenant_id = ? ORDER BY id", id, s.tenant)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []types.StudentTransition{}
+
+	for rows.Next() {
+		var transition types.StudentTransition
+
+		if err := rows.Scan(&transition.Id, &transition.StudentId, &transition.Event, &transition.From, &transition.To, &transition.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		transitions = append(transitions, transition)
+	}
+
+	return transitions, rows.Err()
+}
+
+func (s *Sqlite) CreateCourse(code string, title string, credits int) (int64, error) {
+	result, err := s.conn().Exec("INSERT INTO courses (tenant_id, code, title, credits) VALUES (?, ?, ?, ?)", s.tenant, code, title, credits)
+
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetCourseById(id int64) (types.Course, error) {
+	var course types.Course
+
+	err := s.conn().QueryRow("SELECT id, code, title, credits FROM courses WHERE id = ? AND tenant_id = ? LIMIT 1", id, s.tenant).
+		Scan(&course.Id, &course.Code, &course.Title, &course.Credits)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.Course{}, fmt.Errorf("course %d: %w", id, storage.ErrNotFound)
+		}
+		return types.Course{}, err
+	}
+
+	return course, nil
+}
+
+func (s *Sqlite) GetCourses() ([]types.Course, error) {
+	return s.queryCourses("SELECT id, code, title, credits FROM courses WHERE tenant_id = ? ORDER BY id", s.tenant)
+}
+
+func (s *Sqlite) GetCoursesPage(afterId int64, limit int) ([]types.Course, error) {
+	return s.queryCourses("SELECT id, code, title, credits FROM courses WHERE tenant_id = ? AND id > ? ORDER BY id LIMIT ?", s.tenant, afterId, limit)
+}
+
+func (s *Sqlite) GetCoursesByIds(ids []int64) ([]types.Course, error) {
+	if len(ids) == 0 {
+		return []types.Course{}, nil
+	}
+
+	placeholders, args := inClause(ids)
+	return s.queryCourses("SELECT id, code, title, credits FROM courses WHERE tenant_id = ? AND id IN ("+placeholders+") ORDER BY id", append([]any{s.tenant}, args...)...)
+}
+
+func (s *Sqlite) queryCourses(query string, args ...any) ([]types.Course, error) {
+	rows, err := s.conn().Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses := []types.Course{}
+
+	for rows.Next() {
+		var course types.Course
+
+		if err := rows.Scan(&course.Id, &course.Code, &course.Title, &course.Credits); err != nil {
+			return nil, err
+		}
+
+		courses = append(courses, course)
+	}
+
+	return courses, rows.Err()
+}
+
+func (s *Sqlite) Enroll(studentId int64, courseId int64) (int64, error) {
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		// Foreign keys only prove the rows exist somewhere; both must also
+		// belong to this tenant.
+		if err := s.expectOwned(tx, "students", studentId); err != nil {
+			return fmt.Errorf("student %d: %w", studentId, err)
+		}
+
+		if err := s.expectOwned(tx, "courses", courseId); err != nil {
+			return fmt.Errorf("course %d: %w", courseId, err)
+		}
+
+		result, err := tx.Exec("INSERT INTO enrollments (tenant_id, student_id, course_id, enrolled_at) VALUES (?, ?, ?, ?)",
+			s.tenant, studentId, courseId, time.Now().UTC())
+		if err != nil {
+			return translateError(err)
+		}
+
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+
+		return s.enqueueEnrollment(tx, events.StudentEnrolled, studentId, courseId)
+	})
+
+	return id, err
+}
+
+func (s *Sqlite) Unenroll(studentId int64, courseId int64) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec("DELETE FROM enrollments WHERE student_id = ? AND course_id = ? AND tenant_id = ?", studentId, courseId, s.tenant)
+		if err != nil {
+			return translateError(err)
+		}
+
+		err = expectAffected(result, fmt.Errorf("enrollment of student %d in course %d: %w", studentId, courseId, storage.ErrNotFound))
+		if err != nil {
+			return err
+		}
+
+		return s.enqueue(tx, events.NewEnrollment(events.StudentUnenrolled, studentId, courseId, nil))
+	})
+}
+
+func (s *Sqlite) RecordGrade(studentId int64, courseId int64, grade float64) error {
+	return s.inTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec("UPDATE enrollments SET grade = ? WHERE student_id = ? AND course_id = ? AND tenant_id = ?", grade, studentId, courseId, s.tenant)
+		if err != nil {
+			return translateError(err)
+		}
+
+		err = expectAffected(result, fmt.Errorf("enrollment of student %d in course %d: %w", studentId, courseId, storage.ErrNotFound))
+		if err != nil {
+			return err
+		}
+
+		return s.enqueueEnrollment(tx, events.GradeRecorded, studentId, courseId)
+	})
+}
+
+func (s *Sqlite) GetEnrollmentsByStudent(studentId int64) ([]types.Enrollment, error) {
+	return s.queryEnrollments("SELECT id, student_id, course_id, grade, enrolled_at FROM enrollments WHERE tenant_id = ? AND student_id = ? ORDER BY id", s.tenant, studentId)
+}
+
+func (s *Sqlite) GetEnrollmentsByCourse(courseId int64) ([]types.Enrollment, error) {
+	return s.queryEnrollments("SELECT id, student_id, course_id, grade, enrolled_at FROM enrollments WHERE tenant_id = ? AND course_id = ? ORDER BY id", s.tenant, courseId)
+}
+
+func (s *Sqlite) GetEnrollmentsByStudentIds(studentIds []int64) ([]types.Enrollment, error) {
+	if len(studentIds) == 0 {
+		return []types.Enrollment{}, nil
+	}
+
+	placeholders, args := inClause(studentIds)
+	return s.queryEnrollments("SELECT id, student_id, course_id, grade, enrolled_at FROM enrollments WHERE tenant_id = ? AND student_id IN ("+placeholders+") ORDER BY id", append([]any{s.tenant}, args...)...)
+}
+
+func (s *Sqlite) GetEnrollmentsByCourseIds(courseIds []int64) ([]types.Enrollment, error) {
+	if len(courseIds) == 0 {
+		return []types.Enrollment{}, nil
+	}
+
+	placeholders, args := inClause(courseIds)
+	return s.queryEnrollments("SELECT id, student_id, course_id, grade, enrolled_at FROM enrollments WHERE tenant_id = ? AND course_id IN ("+placeholders+") ORDER BY id", append([]any{s.tenant}, args...)...)
+}
+
+func (s *Sqlite) queryEnrollments(query string, args ...any) ([]types.Enrollment, error) {
+	rows, err := s.conn().Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	enrollments := []types.Enrollment{}
+
+	for rows.Next() {
+		var enrollment types.Enrollment
+		var grade sql.NullFloat64
+
+		if err := rows.Scan(&enrollment.Id, &enrollment.StudentId, &enrollment.CourseId, &grade, &enrollment.EnrolledAt); err != nil {
+			return nil, err
+		}
+
+		if grade.Valid {
+			enrollment.Grade = &grade.Float64
+		}
+
+		enrollments = append(enrollments, enrollment)
+	}
+
+	return enrollments, rows.Err()
+}
+
+const studentColumns = "id, name, email, age, status, status_changed_at"
+
+func studentFields(student *types.Student) []any {
+	return []any{&student.Id, &student.Name, &student.Email, &student.Age, &student.Status, &student.StatusChangedAt}
+}
+
+// inClause returns "?, ?, ..." for ids along with the matching arguments.
+func inClause(ids []int64) (string, []any) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
+
+// expectOwned fails with storage.ErrInvalidRef unless the row with id in
+// table belongs to this tenant.
+func (s *Sqlite) expectOwned(tx *sql.Tx, table string, id int64) error {
+	var exists bool
+
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = ? AND tenant_id = ?)", id, s.tenant).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrInvalidRef
+	}
+
+	return nil
+}
+
+func expectAffected(result sql.Result, notFound error) error {
+	n, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// Snapshot writes a consistent copy of the database to path with VACUUM
+// INTO, which reads inside a single transaction and so can run while the
+// database is in use. path must not exist yet. Cancelling ctx interrupts
+// the copy.
+func (s *Sqlite) Snapshot(ctx context.Context, path string) error {
+	_, err := s.Db.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
+}
@@ -0,0 +1,173 @@
+package sqlite
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+)
+
+// newTestStorage opens a fresh database in a temporary directory.
+func newTestStorage(t *testing.T) *Sqlite {
+	t.Helper()
+
+	s, err := New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { s.Db.Close() })
+
+	return s
+}
+
+func mustCreateStudent(t *testing.T, s storage.Storage, name string, email string) int64 {
+	t.Helper()
+
+	id, err := s.CreateStudent(name, email, 20)
+	if err != nil {
+		t.Fatalf("creating student %s: %v", name, err)
+	}
+	return id
+}
+
+func mustCreateCourse(t *testing.T, s storage.Storage, code string) int64 {
+	t.Helper()
+
+	id, err := s.CreateCourse(code, "Course "+code, 3)
+	if err != nil {
+		t.Fatalf("creating course %s: %v", code, err)
+	}
+	return id
+}
+
+func TestStudentCRUD(t *testing.T) {
+	s := newTestStorage(t)
+
+	id := mustCreateStudent(t, s, "Ada", "ada@example.edu")
+
+	student, err := s.GetStudentById(id)
+	if err != nil {
+		t.Fatalf("GetStudentById: %v", err)
+	}
+	if student.Name != "Ada" || student.Status != "applicant" {
+		t.Errorf("created student = %+v", student)
+	}
+
+	student.Name = "Ada Lovelace"
+	if err := s.UpdateStudent(student); err != nil {
+		t.Fatalf("UpdateStudent: %v", err)
+	}
+
+	if got, _ := s.GetStudentById(id); got.Name != "Ada Lovelace" {
+		t.Errorf("name after update = %q", got.Name)
+	}
+
+	if err := s.DeleteStudent(id); err != nil {
+		t.Fatalf("DeleteStudent: %v", err)
+	}
+
+	if _, err := s.GetStudentById(id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetStudentById after delete error = %v, want ErrNotFound", err)
+	}
+
+	if err := s.DeleteStudent(id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("second DeleteStudent error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCourseCodesAreUnique(t *testing.T) {
+	s := newTestStorage(t)
+
+	mustCreateCourse(t, s, "CS101")
+
+	if _, err := s.CreateCourse("CS101", "Again", 3); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Errorf("duplicate course error = %v, want ErrAlreadyExists", err)
+	}
+}
+
+func TestEnrollmentsAndGrades(t *testing.T) {
+	s := newTestStorage(t)
+
+	studentId := mustCreateStudent(t, s, "Ada", "ada@example.edu")
+	courseId := mustCreateCourse(t, s, "CS101")
+
+	if _, err := s.Enroll(studentId, courseId); err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+
+	if _, err := s.Enroll(studentId, courseId); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Errorf("second Enroll error = %v, want ErrAlreadyExists", err)
+	}
+
+	if _, err := s.Enroll(studentId+100, courseId); !errors.Is(err, storage.ErrInvalidRef) {
+		t.Errorf("Enroll of a missing student error = %v, want ErrInvalidRef", err)
+	}
+
+	if _, err := s.Enroll(studentId, courseId+100); !errors.Is(err, storage.ErrInvalidRef) {
+		t.Errorf("Enroll in a missing course error = %v, want ErrInvalidRef", err)
+	}
+
+	if err := s.RecordGrade(studentId, courseId, 3.5); err != nil {
+		t.Fatalf("RecordGrade: %v", err)
+	}
+
+	enrollments, err := s.GetEnrollmentsByCourse(courseId)
+	if err != nil {
+		t.Fatalf("GetEnrollmentsByCourse: %v", err)
+	}
+	if len(enrollments) != 1 || enrollments[0].Grade == nil || *enrollments[0].Grade != 3.5 {
+		t.Fatalf("enrollments = %+v, want one graded 3.5", enrollments)
+	}
+
+	if err := s.RecordGrade(studentId, courseId, 5); err == nil {
+		t.Error("RecordGrade accepted a grade above 4")
+	}
+
+	if err := s.Unenroll(studentId, courseId); err != nil {
+		t.Fatalf("Unenroll: %v", err)
+	}
+
+	if err := s.RecordGrade(studentId, courseId, 3); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("RecordGrade after Unenroll error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeletingAStudentRemovesTheirEnrollments(t *testing.T) {
+	s := newTestStorage(t)
+
+	studentId := mustCreateStudent(t, s, "Ada", "ada@example.edu")
+	courseId := mustCreateCourse(t, s, "CS101")
+
+	if _, err := s.Enroll(studentId, courseId); err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+
+	if err := s.DeleteStudent(studentId); err != nil {
+		t.Fatalf("DeleteStudent: %v", err)
+	}
+
+	enrollments, err := s.GetEnrollmentsByCourse(courseId)
+	if err != nil {
+		t.Fatalf("GetEnrollmentsByCourse: %v", err)
+	}
+	if len(enrollments) != 0 {
+		t.Errorf("enrollments after delete = %+v, want none", enrollments)
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	s := newTestStorage(t)
+
+	mustCreateStudent(t, s, "Ada", "ada@example.edu")
+
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	students, err := s.GetStudents()
+	if err != nil || len(students) != 1 {
+		t.Errorf("students after second Migrate = %v, %v", students, err)
+	}
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// TestTenantsAreIsolated seeds two tenants and checks that every read and
+// write made through north's view misses south's rows and leaves them as
+// they were.
+func TestTenantsAreIsolated(t *testing.T) {
+	db := newTestStorage(t)
+	north, south := db.ForTenant("north"), db.ForTenant("south")
+
+	mustCreateStudent(t, north, "Ada", "ada@north.edu")
+	theirs := mustCreateStudent(t, south, "Grace", "grace@south.edu")
+	theirCourse := mustCreateCourse(t, south, "CS101")
+
+	if _, err := south.Enroll(theirs, theirCourse); err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+	if _, err := south.TransitionStudent(theirs, "enroll"); err != nil {
+		t.Fatalf("TransitionStudent: %v", err)
+	}
+
+	fileId, err := south.CreateStudentFile(types.StudentFile{StudentId: theirs, Kind: "document", Filename: "id.pdf", ContentType: "application/pdf", Size: 1, SHA256: "abc"})
+	if err != nil {
+		t.Fatalf("CreateStudentFile: %v", err)
+	}
+
+	wantNotFound := func(op string, err error) {
+		t.Helper()
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s across tenants = %v, want ErrNotFound", op, err)
+		}
+	}
+
+	_, err = north.GetStudentById(theirs)
+	wantNotFound("GetStudentById", err)
+
+	wantNotFound("UpdateStudent", north.UpdateStudent(types.Student{Id: theirs, Name: "Mallory", Email: "m@north.edu", Age: 30}))
+	wantNotFound("DeleteStudent", north.DeleteStudent(theirs))
+
+	_, err = north.TransitionStudent(theirs, "suspend")
+	wantNotFound("TransitionStudent", err)
+
+	_, err = north.GetCourseById(theirCourse)
+	wantNotFound("GetCourseById", err)
+
+	_, err = north.GetStudentFile(theirs, fileId)
+	wantNotFound("GetStudentFile", err)
+	wantNotFound("DeleteStudentFile", north.DeleteStudentFile(theirs, fileId))
+
+	if _, err := north.Enroll(theirs, theirCourse); err == nil {
+		t.Error("Enroll across tenants succeeded")
+	}
+
+	lists := map[string]func() (int, error){
+		"GetStudents": func() (int, error) {
+			s, err := north.GetStudents()
+			return len(s), err
+		},
+		"GetStudentsPage": func() (int, error) {
+			s, err := north.GetStudentsPage(0, 10)
+			return len(s), err
+		},
+		"GetStudentsByIds": func() (int, error) {
+			s, err := north.GetStudentsByIds([]int64{theirs})
+			return len(s), err
+		},
+		"GetCourses": func() (int, error) {
+			c, err := north.GetCourses()
+			return len(c), err
+		},
+		"GetEnrollmentsByStudent": func() (int, error) {
+			e, err := north.GetEnrollmentsByStudent(theirs)
+			return len(e), err
+		},
+		"GetStudentTransitions": func() (int, error) {
+			tr, err := north.GetStudentTransitions(theirs)
+			return len(tr), err
+		},
+		"GetStudentFiles": func() (int, error) {
+			f, err := north.GetStudentFiles(theirs)
+			return len(f), err
+		},
+	}
+
+	want := map[string]int{"GetStudents": 1, "GetStudentsPage": 1}
+
+	for name, list := range lists {
+		n, err := list()
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s: %v", name, err)
+		}
+		if n != want[name] {
+			t.Errorf("%s through north returned %d rows, want %d", name, n, want[name])
+		}
+	}
+
+	student, err := south.GetStudentById(theirs)
+	if err != nil || student.Name != "Grace" || student.Status != "enrolled" {
+		t.Errorf("south's student after north's writes = %+v, %v", student, err)
+	}
+	if files, _ := south.GetStudentFiles(theirs); len(files) != 1 {
+		t.Errorf("south's files = %+v, want the file kept", files)
+	}
+}
@@ -0,0 +1,244 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+const webhookColumns = "id, url, secret, events, enabled, consecutive_failures, created_at"
+
+func scanWebhook(scan func(dest ...any) error) (types.Webhook, error) {
+	var webhook types.Webhook
+	var events string
+
+	err := scan(&webhook.Id, &webhook.URL, &webhook.Secret, &events, &webhook.Enabled, &webhook.ConsecutiveFailures, &webhook.CreatedAt)
+	if err != nil {
+		return types.Webhook{}, err
+	}
+
+	webhook.Events = []string{}
+	if events != "" {
+		webhook.Events = strings.Split(events, ",")
+	}
+
+	return webhook, nil
+}
+
+func (s *Sqlite) CreateWebhook(webhook types.Webhook) (int64, error) {
+	result, err := s.Db.Exec("INSERT INTO webhooks (tenant_id, url, secret, events, enabled, created_at) VALUES (?, ?, ?, ?, 1, ?)",
+		s.tenant, webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), time.Now().UTC())
+
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	return result.LastInsertId()
+}
+
+func (s *Sqlite) GetWebhookById(id int64) (types.Webhook, error) {
+	return s.getWebhook("SELECT "+webhookColumns+" FROM webhooks WHERE id = ? AND tenant_id = ?", id, s.tenant)
+}
+
+// LookupWebhook finds a webhook by id whichever tenant owns it. Only the
+// dispatcher uses it: deliveries are processed for all tenants at once.
+func (s *Sqlite) LookupWebhook(id int64) (types.Webhook, error) {
+	return s.getWebhook("SELECT "+webhookColumns+" FROM webhooks WHERE id = ?", id)
+}
+
+func (s *Sqlite) getWebhook(query string, id int64, args ...any) (types.Webhook, error) {
+	webhook, err := scanWebhook(s.Db.QueryRow(query, append([]any{id}, args...)...).Scan)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return types.Webhook{}, fmt.Errorf("webhook %d: %w", id, storage.ErrNotFound)
+		}
+		return types.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (s *Sqlite) GetWebhooks() ([]types.Webhook, error) {
+	return s.GetTenantWebhooks(s.tenant)
+}
+
+// GetTenantWebhooks lists the webhooks of any tenant, so the dispatcher can
+// fan an event out to the subscriptions of the tenant it belongs to.
+func (s *Sqlite) GetTenantWebhooks(tenant string) ([]types.Webhook, error) {
+	rows, err := s.Db.Query("SELECT "+webhookColumns+" FROM webhooks WHERE tenant_id = ? ORDER BY id", tenant)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []types.Webhook{}
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (s *Sqlite) DeleteWebhook(id int64) error {
+	result, err := s.Db.Exec("DELETE FROM webhooks WHERE id = ? AND tenant_id = ?", id, s.tenant)
+
+	if err != nil {
+		return translateError(err)
+	}
+
+	return expectAffected(result, fmt.Errorf("webhook %d: %w", id, storage.ErrNotFound))
+}
+
+// SetWebhookEnabled toggles a webhook and clears its failure streak so a
+// re-enabled endpoint gets a fresh allowance.
+func (s *Sqlite) SetWebhookEnabled(id int64, enabled bool) error {
+	result, err := s.Db.Exec("UPDATE webhooks SET enabled = ?, consecutive_failures = 0 WHERE id = ? AND tenant_id = ?", enabled, id, s.tenant)
+
+	if err != nil {
+		return translateError(err)
+	}
+
+	return expectAffected(result, fmt.Errorf("webhook %d: %w", id, storage.ErrNotFound))
+}
+
+// RecordWebhookResult tracks consecutive delivery failures and disables the
+// webhook once disableAfter is reached. It reports whether it was disabled.
+func (s *Sqlite) RecordWebhookResult(id int64, ok bool, disableAfter int) (bool, error) {
+	if ok {
+		_, err := s.Db.Exec("UPDATE webhooks SET consecutive_failures = 0 WHERE id = ?", id)
+		return false, err
+	}
+
+	var failures int
+
+	err := s.Db.QueryRow("UPDATE webhooks SET consecutive_failures = consecutive_failures + 1 WHERE id = ? RETURNING consecutive_failures", id).
+		Scan(&failures)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if disableAfter <= 0 || failures < disableAfter {
+		return false, nil
+	}
+
+	result, err := s.Db.Exec("UPDATE webhooks SET enabled = 0 WHERE id = ? AND enabled = 1", id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (s *Sqlite) CreateDelivery(delivery types.WebhookDelivery) (bool, error) {
+	result, err := s.Db.Exec(`INSERT INTO webhook_deliveries
+		(webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`,
+		delivery.WebhookId, delivery.EventId, delivery.EventType, delivery.Payload, types.DeliveryPending,
+		delivery.NextAttemptAt, time.Now().UTC())
+
+	if err != nil {
+		return false, translateError(err)
+	}
+
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.response_code, d.last_error, d.next_attempt_at, d.created_at, d.delivered_at`
+
+func scanDelivery(scan func(dest ...any) error) (types.WebhookDelivery, error) {
+	var delivery types.WebhookDelivery
+	var deliveredAt sql.NullTime
+
+	err := scan(&delivery.Id, &delivery.WebhookId, &delivery.EventId, &delivery.EventType, &delivery.Payload,
+		&delivery.Status, &delivery.Attempts, &delivery.ResponseCode, &delivery.LastError,
+		&delivery.NextAttemptAt, &delivery.CreatedAt, &deliveredAt)
+	if err != nil {
+		return types.WebhookDelivery{}, err
+	}
+
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return delivery, nil
+}
+
+func (s *Sqlite) queryDeliveries(query string, args ...any) ([]types.WebhookDelivery, error) {
+	rows, err := s.Db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []types.WebhookDelivery{}
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// DueDeliveries returns pending deliveries of enabled webhooks whose next
+// attempt is at or before now, oldest first.
+func (s *Sqlite) DueDeliveries(now time.Time, limit int) ([]types.WebhookDelivery, error) {
+	return s.queryDeliveries(`SELECT `+deliveryColumns+` FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND w.enabled = 1
+		ORDER BY d.next_attempt_at, d.id LIMIT ?`, types.DeliveryPending, now, limit)
+}
+
+func (s *Sqlite) GetDeliveries(webhookId int64) ([]types.WebhookDelivery, error) {
+	return s.queryDeliveries(`SELECT `+deliveryColumns+` FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.webhook_id = ? AND w.tenant_id = ? ORDER BY d.id DESC LIMIT 100`, webhookId, s.tenant)
+}
+
+// UpdateDelivery stores the outcome of an attempt: status, attempt count,
+// response details and when to try next.
+func (s *Sqlite) UpdateDelivery(delivery types.WebhookDelivery) error {
+	_, err := s.Db.Exec(`UPDATE webhook_deliveries SET status = ?, attempts = ?, response_code = ?,
+		last_error = ?, next_attempt_at = ?, delivered_at = ? WHERE id = ?`,
+		delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt, delivery.Id)
+
+	return err
+}
+
+// PruneDeliveries deletes finished deliveries of every tenant created
+// before the cutoff. Pending ones are kept whatever their age.
+func (s *Sqlite) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.Db.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE status != ? AND created_at < ?", types.DeliveryPending, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune deliveries: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+func TestDeliveriesAreUniquePerWebhookAndEvent(t *testing.T) {
+	s := newTestStorage(t)
+
+	webhookId, err := s.CreateWebhook(types.Webhook{URL: "http://127.0.0.1:1", Secret: "secret"})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	delivery := types.WebhookDelivery{WebhookId: webhookId, EventId: "e1", EventType: "student.created", Payload: []byte("{}"), NextAttemptAt: time.Now().UTC()}
+
+	for i, want := range []bool{true, false} {
+		created, err := s.CreateDelivery(delivery)
+		if err != nil || created != want {
+			t.Errorf("CreateDelivery #%d = %v, %v, want %v", i+1, created, err, want)
+		}
+	}
+
+	// A database from before the unique index may hold duplicates already;
+	// migrating keeps one of them.
+	if _, err := s.Db.Exec("DROP INDEX idx_webhook_deliveries_event"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Db.Exec("INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at) SELECT webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at FROM webhook_deliveries"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	deliveries, err := s.GetDeliveries(webhookId)
+	if err != nil || len(deliveries) != 1 {
+		t.Errorf("deliveries after Migrate = %+v, %v, want one", deliveries, err)
+	}
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrInvalidRef    = errors.New("referenced record does not exist")
+	ErrNoBatch       = errors.New("storage does not support batches")
+)
+
+type Storage interface {
+	CreateStudent(name string, email string, age int) (int64, error)
+	GetStudentById(id int64) (types.Student, error)
+	GetStudents() ([]types.Student, error)
+	GetStudentsPage(afterId int64, limit int) ([]types.Student, error)
+	GetStudentsByIds(ids []int64) ([]types.Student, error)
+	UpdateStudent(student types.Student) error
+	DeleteStudent(id int64) error
+	TransitionStudent(id int64, event string) (types.StudentTransition, error)
+	GetStudentTransitions(id int64) ([]types.StudentTransition, error)
+
+	CreateCourse(code string, title string, credits int) (int64, error)
+	GetCourseById(id int64) (types.Course, error)
+	GetCourses() ([]types.Course, error)
+	GetCoursesPage(afterId int64, limit int) ([]types.Course, error)
+	GetCoursesByIds(ids []int64) ([]types.Course, error)
+
+	Enroll(studentId int64, courseId int64) (int64, error)
+	Unenroll(studentId int64, courseId int64) error
+	RecordGrade(studentId int64, courseId int64, grade float64) error
+	GetEnrollmentsByStudent(studentId int64) ([]types.Enrollment, error)
+	GetEnrollmentsByCourse(courseId int64) ([]types.Enrollment, error)
+	GetEnrollmentsByStudentIds(studentIds []int64) ([]types.Enrollment, error)
+	GetEnrollmentsByCourseIds(courseIds []int64) ([]types.Enrollment, error)
+}
+
+// Binder is implemented by decorators that need the caller's context, such
+// as the tracing one, since Storage methods do not take one.
+type Binder interface {
+	WithContext(ctx context.Context) Storage
+}
+
+// Batcher is implemented by storages that can run a series of calls as one
+// transaction. fn gets a Storage whose writes are committed together when
+// it returns nil and rolled back when it returns an error.
+type Batcher interface {
+	Batch(fn func(tx Storage) error) error
+}
+
+// WithContext binds s to ctx when s supports it and returns s unchanged
+// otherwise.
+func WithContext(ctx context.Context, s Storage) Storage {
+	if binder, ok := s.(Binder); ok {
+		return binder.WithContext(ctx)
+	}
+	return s
+}
@@ -0,0 +1,166 @@
+// Package traced records a span for every storage call.
+package traced
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+// tracedStorage parents its spans on the span in ctx. Handlers get a copy
+// bound to their request context through storage.WithContext; calls on the
+// unbound value start root spans.
+type tracedStorage struct {
+	next   storage.Storage
+	tracer *trace.Tracer
+	ctx    context.Context
+}
+
+// Wrap returns s with its calls traced, or s itself when tracer is nil.
+func Wrap(s storage.Storage, tracer *trace.Tracer) storage.Storage {
+	if tracer == nil {
+		return s
+	}
+
+	return &tracedStorage{next: s, tracer: tracer, ctx: context.Background()}
+}
+
+func (s *tracedStorage) WithContext(ctx context.Context) storage.Storage {
+	bound := *s
+	bound.ctx = ctx
+	return &bound
+}
+
+func call[T any](s *tracedStorage, operation string, fn func() (T, error)) (T, error) {
+	_, span := s.tracer.StartSpan(s.ctx, "storage."+operation, trace.KindInternal)
+	span.SetAttr("db.system", "sqlite")
+	span.SetAttr("db.operation", operation)
+
+	result, err := fn()
+
+	// A missing row is an answer, not a failure of the call.
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		span.SetError(err)
+	}
+	span.End()
+
+	return result, err
+}
+
+func exec(s *tracedStorage, operation string, fn func() error) error {
+	_, err := call(s, operation, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+func (s *tracedStorage) CreateStudent(name string, email string, age int) (int64, error) {
+	return call(s, "CreateStudent", func() (int64, error) { return s.next.CreateStudent(name, email, age) })
+}
+
+func (s *tracedStorage) GetStudentById(id int64) (types.Student, error) {
+	return call(s, "GetStudentById", func() (types.Student, error) { return s.next.GetStudentById(id) })
+}
+
+func (s *tracedStorage) GetStudents() ([]types.Student, error) {
+	return call(s, "GetStudents", s.next.GetStudents)
+}
+
+func (s *tracedStorage) GetStudentsPage(afterId int64, limit int) ([]types.Student, error) {
+	return call(s, "GetStudentsPage", func() ([]types.Student, error) { return s.next.GetStudentsPage(afterId, limit) })
+}
+
+func (s *tracedStorage) GetStudentsByIds(ids []int64) ([]types.Student, error) {
+	return call(s, "GetStudentsByIds", func() ([]types.Student, error) { return s.next.GetStudentsByIds(ids) })
+}
+
+func (s *tracedStorage) UpdateStudent(student types.Student) error {
+	return exec(s, "UpdateStudent", func() error { return s.next.UpdateStudent(student) })
+}
+
+func (s *tracedStorage) DeleteStudent(id int64) error {
+	return exec(s, "DeleteStudent", func() error { return s.next.DeleteStudent(id) })
+}
+
+func (s *tracedStorage) TransitionStudent(id int64, event string) (types.StudentTransition, error) {
+	return call(s, "TransitionStudent", func() (types.StudentTransition, error) { return s.next.TransitionStudent(id, event) })
+}
+
+func (s *tracedStorage) GetStudentTransitions(id int64) ([]types.StudentTransition, error) {
+	return call(s, "GetStudentTransitions", func() ([]types.StudentTransition, error) { return s.next.GetStudentTransitions(id) })
+}
+
+func (s *tracedStorage) CreateCourse(code string, title string, credits int) (int64, error) {
+	return call(s, "CreateCourse", func() (int64, error) { return s.next.CreateCourse(code, title, credits) })
+}
+
+func (s *tracedStorage) GetCourseById(id int64) (types.Course, error) {
+	return call(s, "GetCourseById", func() (types.Course, error) { return s.next.GetCourseById(id) })
+}
+
+func (s *tracedStorage) GetCourses() ([]types.Course, error) {
+	return call(s, "GetCourses", s.next.GetCourses)
+}
+
+func (s *tracedStorage) GetCoursesPage(afterId int64, limit int) ([]types.Course, error) {
+	return call(s, "GetCoursesPage", func() ([]types.Course, error) { return s.next.GetCoursesPage(afterId, limit) })
+}
+
+func (s *tracedStorage) GetCoursesByIds(ids []int64) ([]types.Course, error) {
+	return call(s, "GetCoursesByIds", func() ([]types.Course, error) { return s.next.GetCoursesByIds(ids) })
+}
+
+func (s *tracedStorage) Enroll(studentId int64, courseId int64) (int64, error) {
+	return call(s, "Enroll", func() (int64, error) { return s.next.Enroll(studentId, courseId) })
+}
+
+func (s *tracedStorage) Unenroll(studentId int64, courseId int64) error {
+	return exec(s, "Unenroll", func() error { return s.next.Unenroll(studentId, courseId) })
+}
+
+func (s *tracedStorage) RecordGrade(studentId int64, courseId int64, grade float64) error {
+	return exec(s, "RecordGrade", func() error { return s.next.RecordGrade(studentId, courseId, grade) })
+}
+
+func (s *tracedStorage) GetEnrollmentsByStudent(studentId int64) ([]types.Enrollment, error) {
+	return call(s, "GetEnrollmentsByStudent", func() ([]types.Enrollment, error) { return s.next.GetEnrollmentsByStudent(studentId) })
+}
+
+func (s *tracedStorage) GetEnrollmentsByCourse(courseId int64) ([]types.Enrollment, error) {
+	return call(s, "GetEnrollmentsByCourse", func() ([]types.Enrollment, error) { return s.next.GetEnrollmentsByCourse(courseId) })
+}
+
+func (s *tracedStorage) GetEnrollmentsByStudentIds(studentIds []int64) ([]types.Enrollment, error) {
+	return call(s, "GetEnrollmentsByStudentIds", func() ([]types.Enrollment, error) { return s.next.GetEnrollmentsByStudentIds(studentIds) })
+}
+
+func (s *tracedStorage) GetEnrollmentsByCourseIds(courseIds []int64) ([]types.Enrollment, error) {
+	return call(s, "GetEnrollmentsByCourseIds", func() ([]types.Enrollment, error) { return s.next.GetEnrollmentsByCourseIds(courseIds) })
+}
+
+// Batch implements storage.Batcher when the wrapped storage does, with the
+// calls fn makes recorded under one span for the whole batch.
+func (s *tracedStorage) Batch(fn func(tx storage.Storage) error) error {
+	batcher, ok := s.next.(storage.Batcher)
+	if !ok {
+		return storage.ErrNoBatch
+	}
+
+	ctx, span := s.tracer.StartSpan(s.ctx, "storage.Batch", trace.KindInternal)
+	span.SetAttr("db.system", "sqlite")
+	span.SetAttr("db.operation", "Batch")
+
+	err := batcher.Batch(func(tx storage.Storage) error {
+		return fn(&tracedStorage{next: tx, tracer: s.tracer, ctx: ctx})
+	})
+
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		span.SetError(err)
+	}
+	span.End()
+
+	return err
+}
@@ -0,0 +1,83 @@
+package tenant
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// verifyJWT checks an HS256 token against secret and returns the string in
+// claim. Only HS256 is accepted, so a token cannot pick a weaker algorithm
+// or "none".
+func verifyJWT(token string, secret []byte, claim string) (string, error) {
+	if len(secret) == 0 {
+		return "", fmt.Errorf("%w: jwt secret is not configured", ErrInvalid)
+	}
+
+	parts := strings.Split(token, ".")
+
+	header, err := decodeSegment(parts[0])
+	if err != nil {
+		return "", err
+	}
+
+	var h struct {
+		Alg string `json:"alg"`
+	}
+
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return "", fmt.Errorf("%w: unsupported algorithm", ErrInvalid)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return "", err
+	}
+
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", fmt.Errorf("%w: bad signature", ErrInvalid)
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	var claims map[string]any
+
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	now := float64(time.Now().Unix())
+
+	if exp, ok := claims["exp"].(float64); ok && now >= exp {
+		return "", fmt.Errorf("%w: token expired", ErrInvalid)
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now < nbf {
+		return "", fmt.Errorf("%w: token not yet valid", ErrInvalid)
+	}
+
+	value, ok := claims[claim].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%w: missing %q claim", ErrInvalid, claim)
+	}
+
+	return value, nil
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,55 @@
+package tenant
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket per tenant, refilled at the tenant's RateLimit
+// per second up to its Burst.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: map[string]*bucket{}}
+}
+
+// Allow takes a token for t. When none is left it returns how long until
+// the next one; a RateLimit of zero means unlimited.
+func (l *Limiter) Allow(t Tenant) (bool, time.Duration) {
+	rate := t.Config.RateLimit
+	if rate <= 0 {
+		return true, 0
+	}
+
+	burst := float64(max(t.Config.Burst, 1))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	b, ok := l.buckets[t.Id]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[t.Id] = b
+	}
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
@@ -0,0 +1,189 @@
+// Package tenant works out which school a request belongs to and carries
+// that through the request context. Storage is opened per tenant, so once
+// the tenant is known every query is confined to it.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+// Default is the tenant used when tenancy is disabled, and for data
+// created before tenancy existed.
+const Default = "default"
+
+var (
+	ErrMissing = errors.New("tenant is required")
+	ErrUnknown = errors.New("unknown tenant")
+	ErrInvalid = errors.New("invalid tenant credentials")
+)
+
+var validId = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+type contextKey struct{}
+
+type Tenant struct {
+	Id     string
+	Config config.Tenant
+}
+
+func WithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant of the request, or the default tenant
+// when none was resolved.
+func FromContext(ctx context.Context) Tenant {
+	if t, ok := ctx.Value(contextKey{}).(Tenant); ok {
+		return t
+	}
+	return Tenant{Id: Default}
+}
+
+// Source gives the resolver access to the parts of an HTTP request or gRPC
+// call a tenant can be read from.
+type Source interface {
+	// Header returns the named header or metadata value.
+	Header(name string) string
+	// Host is the host the client addressed, without port.
+	Host() string
+}
+
+type Resolver struct {
+	cfg config.Tenancy
+}
+
+func NewResolver(cfg config.Tenancy) *Resolver {
+	return &Resolver{cfg: cfg}
+}
+
+// Enabled reports whether requests are resolved to tenants at all.
+func (r *Resolver) Enabled() bool {
+	return r.cfg.Enabled
+}
+
+// Resolve tries the configured sources in order. The first one that names
+// a tenant wins; a JWT that is present but invalid is an error rather than
+// a fall-through, so a forged token cannot land in the default tenant.
+func (r *Resolver) Resolve(src Source) (Tenant, error) {
+	if !r.cfg.Enabled {
+		return r.tenant(Default), nil
+	}
+
+	for _, source := range r.cfg.Sources {
+		var id string
+
+		switch source {
+		case "header":
+			id = strings.TrimSpace(src.Header(r.cfg.Header))
+		case "subdomain":
+			id = r.subdomain(src.Host())
+		case "jwt":
+			token, ok := strings.CutPrefix(src.Header("Authorization"), "Bearer ")
+			if !ok || strings.Count(token, ".") != 2 {
+				continue
+			}
+
+			claim, err := verifyJWT(token, []byte(r.cfg.JWT.Secret), r.cfg.JWT.Claim)
+			if err != nil {
+				return Tenant{}, err
+			}
+			id = claim
+		}
+
+		if id == "" {
+			continue
+		}
+
+		return r.lookup(strings.ToLower(id))
+	}
+
+	if r.cfg.Default != "" {
+		return r.lookup(r.cfg.Default)
+	}
+
+	return Tenant{}, ErrMissing
+}
+
+func (r *Resolver) subdomain(host string) string {
+	if r.cfg.Domain == "" {
+		return ""
+	}
+
+	sub, ok := strings.CutSuffix(strings.ToLower(host), "."+r.cfg.Domain)
+	if !ok || strings.Contains(sub, ".") {
+		return ""
+	}
+
+	return sub
+}
+
+// Lookup returns a configured tenant by id, for admin tools that name a
+// school directly rather than through a request.
+func (r *Resolver) Lookup(id string) (Tenant, error) {
+	if !r.cfg.Enabled {
+		if id != Default {
+			return Tenant{}, ErrUnknown
+		}
+		return r.tenant(Default), nil
+	}
+
+	return r.lookup(strings.ToLower(id))
+}
+
+// lookup only accepts tenants listed in the configuration.
+func (r *Resolver) lookup(id string) (Tenant, error) {
+	if !validId.MatchString(id) {
+		return Tenant{}, ErrUnknown
+	}
+
+	if _, ok := r.cfg.Tenants[id]; !ok && id != r.cfg.Default {
+		return Tenant{}, ErrUnknown
+	}
+
+	return r.tenant(id), nil
+}
+
+// tenant merges the tenant's overrides over the shared defaults.
+func (r *Resolver) tenant(id string) Tenant {
+	cfg := config.Tenant{
+		RateLimit: r.cfg.RateLimit,
+		Burst:     r.cfg.Burst,
+	}
+
+	if override, ok := r.cfg.Tenants[id]; ok {
+		if override.RateLimit != 0 {
+			cfg.RateLimit = override.RateLimit
+		}
+		if override.Burst != 0 {
+			cfg.Burst = override.Burst
+		}
+		cfg.Features = override.Features
+	}
+
+	return Tenant{Id: id, Config: cfg}
+}
+
+// Ids lists the configured tenants, including the default one.
+func (r *Resolver) Ids() []string {
+	if !r.cfg.Enabled {
+		return []string{Default}
+	}
+
+	ids := []string{}
+	if r.cfg.Default != "" {
+		ids = append(ids, r.cfg.Default)
+	}
+
+	for id := range r.cfg.Tenants {
+		if id != r.cfg.Default {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
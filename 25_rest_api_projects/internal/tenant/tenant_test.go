@@ -0,0 +1,127 @@
+package tenant
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type source struct {
+	headers map[string]string
+	host    string
+}
+
+func (s source) Header(name string) string { return s.headers[name] }
+func (s source) Host() string              { return s.host }
+
+func sign(t *testing.T, secret string, claims map[string]any) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	unsigned := encode(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encode(claims)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newResolver(sources ...string) *Resolver {
+	return NewResolver(config.Tenancy{
+		Enabled: true,
+		Sources: sources,
+		Header:  "X-Tenant-ID",
+		Domain:  "schools.test",
+		JWT:     config.JWT{Secret: "secret", Claim: "tenant"},
+		Tenants: map[string]config.Tenant{"north": {}, "south": {RateLimit: 5}},
+	})
+}
+
+func TestResolve(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		sources []string
+		src     source
+		want    string
+		err     error
+	}{
+		{"subdomain", []string{"jwt", "subdomain"}, source{host: "north.schools.test"}, "north", nil},
+		{"jwt", []string{"jwt", "subdomain"}, source{headers: map[string]string{"Authorization": "Bearer " + sign(t, "secret", map[string]any{"tenant": "south"})}}, "south", nil},
+		{"jwt wins over subdomain", []string{"jwt", "subdomain"}, source{
+			headers: map[string]string{"Authorization": "Bearer " + sign(t, "secret", map[string]any{"tenant": "south"})},
+			host:    "north.schools.test",
+		}, "south", nil},
+		{"forged jwt", []string{"jwt", "subdomain"}, source{headers: map[string]string{"Authorization": "Bearer " + sign(t, "guess", map[string]any{"tenant": "south"})}, host: "north.schools.test"}, "", ErrInvalid},
+		{"expired jwt", []string{"jwt"}, source{headers: map[string]string{"Authorization": "Bearer " + sign(t, "secret", map[string]any{"tenant": "south", "exp": expired})}}, "", ErrInvalid},
+		{"unknown tenant", []string{"subdomain"}, source{host: "east.schools.test"}, "", ErrUnknown},
+		{"nothing named", []string{"jwt", "subdomain"}, source{host: "schools.test"}, "", ErrMissing},
+		{"header when trusted", []string{"header"}, source{headers: map[string]string{"X-Tenant-ID": "South"}}, "south", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := newResolver(tt.sources...).Resolve(tt.src)
+		if !errors.Is(err, tt.err) || got.Id != tt.want {
+			t.Errorf("%s: Resolve = %q, %v, want %q, %v", tt.name, got.Id, err, tt.want, tt.err)
+		}
+	}
+}
+
+// TestDefaultSourcesIgnoreTheHeader makes sure a client cannot pick another
+// school by sending the tenant header unless a gateway is trusted to set it.
+func TestDefaultSourcesIgnoreTheHeader(t *testing.T) {
+	var cfg config.Tenancy
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Enabled = true
+	cfg.Domain = "schools.test"
+	cfg.JWT.Secret = "secret"
+	cfg.Tenants = map[string]config.Tenant{"north": {}, "south": {}}
+
+	r := NewResolver(cfg)
+
+	got, err := r.Resolve(source{headers: map[string]string{"X-Tenant-ID": "south"}, host: "api.example.com"})
+	if !errors.Is(err, ErrMissing) {
+		t.Errorf("Resolve = %q, %v, want ErrMissing", got.Id, err)
+	}
+
+	got, err = r.Resolve(source{headers: map[string]string{"X-Tenant-ID": "south"}, host: "north.schools.test"})
+	if err != nil || got.Id != "north" {
+		t.Errorf("Resolve = %q, %v, want north from the subdomain", got.Id, err)
+	}
+}
+
+func TestTenantOverridesMergeOverDefaults(t *testing.T) {
+	r := NewResolver(config.Tenancy{
+		Enabled:   true,
+		RateLimit: 100,
+		Burst:     20,
+		Tenants:   map[string]config.Tenant{"south": {RateLimit: 5}},
+	})
+
+	south, err := r.Lookup("south")
+	if err != nil || south.Config.RateLimit != 5 || south.Config.Burst != 20 {
+		t.Errorf("Lookup(south) = %+v, %v, want rate limit 5 and the shared burst", south, err)
+	}
+
+	if _, err := NewResolver(config.Tenancy{}).Lookup("south"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Lookup with tenancy disabled = %v, want ErrUnknown", err)
+	}
+}
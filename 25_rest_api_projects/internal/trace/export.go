@@ -0,0 +1,226 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Exporter sends a batch of ended spans somewhere.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+type otlpExporter struct {
+	endpoint string
+	service  string
+	client   *http.Client
+}
+
+// OTLP posts spans as OTLP/HTTP JSON to endpoint, usually a collector's
+// http://host:4318/v1/traces.
+func OTLP(endpoint string, service string, timeout time.Duration) Exporter {
+	return &otlpExporter{
+		endpoint: endpoint,
+		service:  service,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (e *otlpExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(otlpRequest(e.service, spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("otlp: unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// The OTLP JSON encoding: ids in hex, 64-bit integers as strings and
+// attributes as typed key/value pairs.
+
+type otlpValue map[string]any
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceId           string         `json:"traceId"`
+	SpanId            string         `json:"spanId"`
+	ParentSpanId      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              Kind           `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+func otlpRequest(service string, spans []SpanData) any {
+	encoded := make([]otlpSpan, 0, len(spans))
+
+	for _, span := range spans {
+		out := otlpSpan{
+			TraceId:           span.TraceID.String(),
+			SpanId:            span.SpanID.String(),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Status:            otlpStatus{Code: 1},
+		}
+
+		if span.ParentID.IsValid() {
+			out.ParentSpanId = span.ParentID.String()
+		}
+
+		if span.Error != "" {
+			out.Status = otlpStatus{Code: 2, Message: span.Error}
+		}
+
+		for key, value := range span.Attributes {
+			out.Attributes = append(out.Attributes, otlpKeyValue{Key: key, Value: otlpAttr(value)})
+		}
+
+		encoded = append(encoded, out)
+	}
+
+	return map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{
+				"attributes": []otlpKeyValue{{Key: "service.name", Value: otlpValue{"stringValue": service}}},
+			},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]any{"name": "github.com/faysal0x1/Go-Learn/internal/trace"},
+				"spans": encoded,
+			}},
+		}},
+	}
+}
+
+func otlpAttr(value any) otlpValue {
+	switch v := value.(type) {
+	case bool:
+		return otlpValue{"boolValue": v}
+	case int:
+		return otlpValue{"intValue": strconv.Itoa(v)}
+	case int64:
+		return otlpValue{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		return otlpValue{"doubleValue": v}
+	case string:
+		return otlpValue{"stringValue": v}
+	}
+	return otlpValue{"stringValue": fmt.Sprint(value)}
+}
+
+// fileExporter appends one JSON object per span, which is easy to grep
+// and to load into a test without a collector.
+type fileExporter struct {
+	mu      sync.Mutex
+	file    *os.File
+	service string
+}
+
+func File(path string, service string) (Exporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileExporter{file: file, service: service}, nil
+}
+
+type fileSpan struct {
+	Service    string         `json:"service"`
+	TraceId    string         `json:"trace_id"`
+	SpanId     string         `json:"span_id"`
+	ParentId   string         `json:"parent_id,omitempty"`
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	Start      time.Time      `json:"start"`
+	DurationMs float64        `json:"duration_ms"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+func (e *fileExporter) Export(ctx context.Context, spans []SpanData) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+
+	for _, span := range spans {
+		out := fileSpan{
+			Service:    e.service,
+			TraceId:    span.TraceID.String(),
+			SpanId:     span.SpanID.String(),
+			Name:       span.Name,
+			Kind:       span.Kind.String(),
+			Start:      span.Start.UTC(),
+			DurationMs: float64(span.End.Sub(span.Start).Microseconds()) / 1000,
+			Attributes: span.Attributes,
+			Error:      span.Error,
+		}
+
+		if span.ParentID.IsValid() {
+			out.ParentId = span.ParentID.String()
+		}
+
+		if err := encoder.Encode(out); err != nil {
+			return err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_, err := e.file.Write(buf.Bytes())
+	return err
+}
+
+func (e *fileExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
+
+func (k Kind) String() string {
+	switch k {
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	}
+	return "internal"
+}
@@ -0,0 +1,41 @@
+package trace
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogHandler adds the request id and the current trace and span ids to
+// records logged with a context, so log lines can be matched to traces.
+// Do not wrap slog.Default().Handler() before replacing the default: that
+// handler writes through the log package, which then loops back into it.
+type LogHandler struct {
+	slog.Handler
+}
+
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{Handler: next}
+}
+
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestId(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+
+	if span := FromContext(ctx); span != nil {
+		record.AddAttrs(
+			slog.String("trace_id", span.sc.TraceID.String()),
+			slog.String("span_id", span.sc.SpanID.String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithGroup(name)}
+}
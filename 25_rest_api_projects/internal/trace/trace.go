@@ -0,0 +1,256 @@
+// Package trace records spans for requests and the storage calls they make
+// and propagates them across services with W3C traceparent headers. Spans
+// are batched and exported over OTLP/HTTP or to a local JSON lines file.
+//
+// A nil *Tracer and a nil *Span are valid and do nothing, so code can be
+// instrumented unconditionally and tracing switched off in configuration.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+type SpanID [8]byte
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+func (id TraceID) IsValid() bool { return id != TraceID{} }
+
+func (id SpanID) IsValid() bool { return id != SpanID{} }
+
+// SpanContext is the part of a span that crosses process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats sc as a version 00 traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent reads a traceparent header value. Unknown future
+// versions are accepted as long as they start with the version 00 fields,
+// as the specification asks.
+func ParseTraceparent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("traceparent: expected 4 fields, got %d", len(parts))
+	}
+
+	version, traceId, spanId, flags := parts[0], parts[1], parts[2], parts[3]
+
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("traceparent: bad version %q", version)
+	}
+
+	var sc SpanContext
+
+	if err := decodeHex(sc.TraceID[:], traceId); err != nil {
+		return SpanContext{}, fmt.Errorf("traceparent: trace id: %w", err)
+	}
+
+	if err := decodeHex(sc.SpanID[:], spanId); err != nil {
+		return SpanContext{}, fmt.Errorf("traceparent: parent id: %w", err)
+	}
+
+	var flagBits [1]byte
+	if err := decodeHex(flagBits[:], flags); err != nil {
+		return SpanContext{}, fmt.Errorf("traceparent: flags: %w", err)
+	}
+
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("traceparent: all-zero id")
+	}
+
+	sc.Sampled = flagBits[0]&1 == 1
+	return sc, nil
+}
+
+// decodeHex only accepts lowercase hex of exactly the destination size.
+func decodeHex(dst []byte, s string) error {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return fmt.Errorf("bad value %q", s)
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// Extract reads the traceparent header, returning false when it is absent
+// or malformed; a malformed header starts a new trace rather than failing
+// the request.
+func Extract(header http.Header) (SpanContext, bool) {
+	sc, err := ParseTraceparent(header.Get("traceparent"))
+	return sc, err == nil
+}
+
+// Inject sets the traceparent header for the span in ctx, if any.
+func Inject(ctx context.Context, header http.Header) {
+	if span := FromContext(ctx); span != nil {
+		header.Set("traceparent", span.SpanContext().Traceparent())
+	}
+}
+
+type Kind int
+
+// Kinds use the OTLP numbering.
+const (
+	KindInternal Kind = 1
+	KindServer   Kind = 2
+	KindClient   Kind = 3
+)
+
+// Span is one timed operation. Its methods are safe for concurrent use and
+// do nothing on a nil span.
+type Span struct {
+	tracer *Tracer
+	sc     SpanContext
+	parent SpanID
+	kind   Kind
+	start  time.Time
+
+	mu    sync.Mutex
+	name  string
+	attrs map[string]any
+	err   string
+	ended bool
+}
+
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+// SetAttr records a string, bool or numeric attribute.
+func (s *Span) SetAttr(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs[key] = value
+	s.mu.Unlock()
+}
+
+// SetError marks the span as failed. A nil err is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.err = err.Error()
+	s.mu.Unlock()
+}
+
+// End finishes the span and queues it for export. Only the first call
+// counts.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+
+	data := SpanData{
+		Name:       s.name,
+		Kind:       s.kind,
+		TraceID:    s.sc.TraceID,
+		SpanID:     s.sc.SpanID,
+		ParentID:   s.parent,
+		Start:      s.start,
+		End:        time.Now(),
+		Attributes: s.attrs,
+		Error:      s.err,
+	}
+	s.mu.Unlock()
+
+	if s.sc.Sampled {
+		s.tracer.enqueue(data)
+	}
+}
+
+// SpanData is an ended span as handed to exporters.
+type SpanData struct {
+	Name       string
+	Kind       Kind
+	TraceID    TraceID
+	SpanID     SpanID
+	ParentID   SpanID
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]any
+	Error      string
+}
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+type requestIdKey struct{}
+
+func WithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// FromContext returns the current span, or nil.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// WithRemote records a span context received from another service as the
+// parent of the next span started from ctx.
+func WithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+func RequestId(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// NewRequestId returns a random id for requests that arrive without one.
+func NewRequestId() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,161 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const traceId, spanId = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+
+	tests := []struct {
+		value   string
+		sampled bool
+		ok      bool
+	}{
+		{"00-" + traceId + "-" + spanId + "-01", true, true},
+		{"00-" + traceId + "-" + spanId + "-00", false, true},
+		{" 00-" + traceId + "-" + spanId + "-01 ", true, true},
+		// A future version may append fields.
+		{"01-" + traceId + "-" + spanId + "-01-extra", true, true},
+		{"00-" + traceId + "-" + spanId + "-01-extra", false, false},
+		{"ff-" + traceId + "-" + spanId + "-01", false, false},
+		{"00-" + strings.ToUpper(traceId) + "-" + spanId + "-01", false, false},
+		{"00-" + strings.Repeat("0", 32) + "-" + spanId + "-01", false, false},
+		{"00-" + traceId + "-" + strings.Repeat("0", 16) + "-01", false, false},
+		{"00-" + traceId[:30] + "-" + spanId + "-01", false, false},
+		{"00-" + traceId + "-" + spanId, false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		sc, err := ParseTraceparent(tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseTraceparent(%q) error = %v, want ok %v", tt.value, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+
+		if sc.TraceID.String() != traceId || sc.SpanID.String() != spanId || sc.Sampled != tt.sampled {
+			t.Errorf("ParseTraceparent(%q) = %+v", tt.value, sc)
+		}
+	}
+}
+
+func TestTraceparentRoundTrips(t *testing.T) {
+	for _, sampled := range []bool{true, false} {
+		want := SpanContext{Sampled: sampled}
+		copy(want.TraceID[:], "0123456789abcdef")
+		copy(want.SpanID[:], "01234567")
+
+		got, err := ParseTraceparent(want.Traceparent())
+		if err != nil || got != want {
+			t.Errorf("round trip of %s = %+v, %v", want.Traceparent(), got, err)
+		}
+	}
+}
+
+// recorder is an Exporter that keeps what it is given.
+type recorder struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (r *recorder) Export(ctx context.Context, spans []SpanData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func newTestTracer(ratio float64) (*Tracer, *recorder) {
+	rec := &recorder{}
+	return &Tracer{
+		exporter:  rec,
+		ratio:     ratio,
+		batchSize: 10,
+		interval:  time.Hour,
+		timeout:   time.Second,
+		queue:     make(chan SpanData, 80),
+	}, rec
+}
+
+func TestSpansNestAndAreExportedOnStop(t *testing.T) {
+	tracer, rec := newTestTracer(1)
+	tracer.Start()
+
+	remote := SpanContext{Sampled: true}
+	copy(remote.TraceID[:], "0123456789abcdef")
+	copy(remote.SpanID[:], "01234567")
+
+	ctx, root := tracer.StartSpan(WithRemote(context.Background(), remote), "GET", KindServer)
+	_, child := tracer.StartSpan(ctx, "storage.GetStudentById", KindInternal)
+	child.SetAttr("student_id", 7)
+	child.End()
+	child.End()
+	root.End()
+
+	tracer.Stop()
+
+	if len(rec.spans) != 2 {
+		t.Fatalf("exported %d spans, want 2 (End twice counts once)", len(rec.spans))
+	}
+
+	exportedChild, exportedRoot := rec.spans[0], rec.spans[1]
+
+	if exportedRoot.TraceID != remote.TraceID || exportedRoot.ParentID != remote.SpanID {
+		t.Errorf("root = %+v, want it to continue the remote trace", exportedRoot)
+	}
+	if exportedChild.TraceID != remote.TraceID || exportedChild.ParentID != exportedRoot.SpanID {
+		t.Errorf("child = %+v, want it parented to the root", exportedChild)
+	}
+	if exportedChild.Attributes["student_id"] != 7 {
+		t.Errorf("child attributes = %v", exportedChild.Attributes)
+	}
+}
+
+func TestUnsampledTracesAreNotExported(t *testing.T) {
+	tracer, rec := newTestTracer(0)
+	tracer.Start()
+
+	ctx, root := tracer.StartSpan(context.Background(), "GET", KindServer)
+	_, child := tracer.StartSpan(ctx, "child", KindInternal)
+	child.End()
+	root.End()
+
+	tracer.Stop()
+
+	if len(rec.spans) != 0 {
+		t.Errorf("exported %d spans of an unsampled trace", len(rec.spans))
+	}
+
+	// A nil tracer, as used when tracing is disabled, starts nil spans
+	// whose methods do nothing.
+	var disabled *Tracer
+	_, span := disabled.StartSpan(context.Background(), "GET", KindServer)
+	span.SetAttr("key", "value")
+	span.End()
+}
+
+func TestLogHandlerAddsIds(t *testing.T) {
+	tracer, _ := newTestTracer(1)
+
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil)))
+
+	ctx, span := tracer.StartSpan(WithRequestId(context.Background(), "req-1"), "GET", KindServer)
+	logger.InfoContext(ctx, "hello")
+
+	for _, want := range []string{"request_id=req-1", "trace_id=" + span.sc.TraceID.String(), "span_id=" + span.sc.SpanID.String()} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log line %q is missing %s", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+)
+
+// Tracer starts spans and exports the sampled ones in batches from a
+// background loop. Spans that arrive while the queue is full are dropped
+// rather than slowing requests down.
+type Tracer struct {
+	exporter  Exporter
+	ratio     float64
+	batchSize int
+	interval  time.Duration
+	timeout   time.Duration
+
+	queue   chan SpanData
+	dropped uint64
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns nil when tracing is disabled; a nil Tracer starts no spans.
+func New(cfg config.Tracing) (*Tracer, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if cfg.BatchSize <= 0 || cfg.FlushInterval <= 0 {
+		return nil, fmt.Errorf("trace: batch size and flush interval must be positive")
+	}
+
+	var exporter Exporter
+
+	switch cfg.Exporter {
+	case "otlp":
+		exporter = OTLP(cfg.Endpoint, cfg.ServiceName, cfg.Timeout)
+	case "file":
+		file, err := File(cfg.File, cfg.ServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("trace: %w", err)
+		}
+		exporter = file
+	default:
+		return nil, fmt.Errorf("trace: unknown exporter %q", cfg.Exporter)
+	}
+
+	return &Tracer{
+		exporter:  exporter,
+		ratio:     cfg.SampleRatio,
+		batchSize: cfg.BatchSize,
+		interval:  cfg.FlushInterval,
+		timeout:   cfg.Timeout,
+		queue:     make(chan SpanData, cfg.BatchSize*8),
+	}, nil
+}
+
+// StartSpan begins a span as a child of the span in ctx, or of a remote parent
+// recorded with WithRemote, or as the root of a new trace. The returned
+// context carries the new span.
+func (t *Tracer) StartSpan(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		kind:   kind,
+		start:  time.Now(),
+		name:   name,
+		attrs:  map[string]any{},
+	}
+
+	if parent := FromContext(ctx); parent != nil {
+		span.sc.TraceID = parent.sc.TraceID
+		span.sc.Sampled = parent.sc.Sampled
+		span.parent = parent.sc.SpanID
+	} else if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok {
+		span.sc.TraceID = remote.TraceID
+		span.sc.Sampled = remote.Sampled
+		span.parent = remote.SpanID
+	} else {
+		rand.Read(span.sc.TraceID[:])
+		span.sc.Sampled = t.sample(span.sc.TraceID)
+	}
+
+	rand.Read(span.sc.SpanID[:])
+
+	return WithSpan(ctx, span), span
+}
+
+// sample keeps a fixed share of new traces, decided from the trace id so
+// every service using the same ratio agrees.
+func (t *Tracer) sample(id TraceID) bool {
+	if t.ratio >= 1 {
+		return true
+	}
+	return float64(binary.BigEndian.Uint64(id[8:])) < t.ratio*math.MaxUint64
+}
+
+func (t *Tracer) enqueue(span SpanData) {
+	select {
+	case t.queue <- span:
+	default:
+		t.mu.Lock()
+		t.dropped++
+		t.mu.Unlock()
+	}
+}
+
+// Start runs the export loop until Stop.
+func (t *Tracer) Start() {
+	if t == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.run(ctx)
+	}()
+}
+
+// Stop exports whatever is still queued and closes the exporter.
+func (t *Tracer) Stop() {
+	if t == nil || t.cancel == nil {
+		return
+	}
+
+	t.cancel()
+	t.wg.Wait()
+
+	if closer, ok := t.exporter.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (t *Tracer) run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		exportCtx, cancel := context.WithTimeout(context.Background(), t.timeout)
+		defer cancel()
+
+		if err := t.exporter.Export(exportCtx, batch); err != nil {
+			slog.Warn("trace: export failed", slog.Int("spans", len(batch)), slog.String("error", err.Error()))
+		}
+
+		t.mu.Lock()
+		if t.dropped > 0 {
+			slog.Warn("trace: queue full, spans dropped", slog.Uint64("spans", t.dropped))
+			t.dropped = 0
+		}
+		t.mu.Unlock()
+
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case span := <-t.queue:
+					batch = append(batch, span)
+					if len(batch) >= t.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		case <-ticker.C:
+			flush()
+		case span := <-t.queue:
+			batch = append(batch, span)
+			if len(batch) >= t.batchSize {
+				flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package types
+
+import (
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/lifecycle"
+)
+
+// Student.Status is read-only over the API; it only changes through a
+// lifecycle transition.
+type Student struct {
+	Id              int64            `json:"id"`
+	Name            string           `json:"name" validate:"required"`
+	Email           string           `json:"email" validate:"required,email"`
+	Age             int              `json:"age" validate:"required,gt=0"`
+	Status          lifecycle.Status `json:"status"`
+	StatusChangedAt time.Time        `json:"status_changed_at"`
+}
+
+type StudentTransition struct {
+	Id        int64            `json:"id"`
+	StudentId int64            `json:"student_id"`
+	Event     string           `json:"event"`
+	From      lifecycle.Status `json:"from"`
+	To        lifecycle.Status `json:"to"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type Course struct {
+	Id      int64  `json:"id"`
+	Code    string `json:"code" validate:"required"`
+	Title   string `json:"title" validate:"required"`
+	Credits int    `json:"credits" validate:"required,gt=0"`
+}
+
+// Enrollment links a student to a course. Grade stays nil until one is
+// recorded and is expressed in grade points on a 0-4 scale.
+type Enrollment struct {
+	Id         int64     `json:"id"`
+	StudentId  int64     `json:"student_id"`
+	CourseId   int64     `json:"course_id"`
+	Grade      *float64  `json:"grade,omitempty"`
+	EnrolledAt time.Time `json:"enrolled_at"`
+}
+
+type EnrollRequest struct {
+	StudentId int64 `json:"student_id" validate:"required,gt=0"`
+}
+
+type GradeRequest struct {
+	Grade *float64 `json:"grade" validate:"required,gte=0,lte=4"`
+}
+
+type StudentGPA struct {
+	StudentId int64   `json:"student_id"`
+	Courses   int     `json:"courses"`
+	Credits   int     `json:"credits"`
+	GPA       float64 `json:"gpa"`
+}
+
+type CourseStats struct {
+	CourseId int64   `json:"course_id"`
+	Enrolled int     `json:"enrolled"`
+	Graded   int     `json:"graded"`
+	Average  float64 `json:"average"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+}
+
+// Webhook is a downstream subscription to student events. An empty Events
+// list subscribes to every event type.
+type Webhook struct {
+	Id                  int64     `json:"id"`
+	URL                 string    `json:"url" validate:"required,url"`
+	Secret              string    `json:"secret,omitempty"`
+	Events              []string  `json:"events"`
+	Enabled             bool      `json:"enabled"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookDelivery struct {
+	Id            int64      `json:"id"`
+	WebhookId     int64      `json:"webhook_id"`
+	EventId       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Payload       []byte     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+// APIKey describes an issued key. The key itself is only shown once, when
+// it is created; the store keeps its SHA-256 hash. A key is valid for the
+// requests of one tenant.
+type APIKey struct {
+	Id         int64      `json:"id"`
+	TenantId   string     `json:"tenant_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Dump is the export/import file format. Ids are kept so enrollments keep
+// pointing at the right students and courses.
+type Dump struct {
+	ExportedAt  time.Time    `json:"exported_at"`
+	Students    []Student    `json:"students"`
+	Courses     []Course     `json:"courses"`
+	Enrollments []Enrollment `json:"enrollments"`
+}
+
+// OutboxMessage is an event stored in the same transaction as the change it
+// describes, waiting to be relayed.
+type OutboxMessage struct {
+	Id        int64     `json:"id"`
+	EventId   string    `json:"event_id"`
+	EventType string    `json:"event_type"`
+	Payload   []byte    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StudentFile is an upload attached to a student. The content lives in the
+// blob store under SHA256; images also get a thumbnail there.
+type StudentFile struct {
+	Id              int64     `json:"id"`
+	StudentId       int64     `json:"student_id"`
+	Kind            string    `json:"kind"`
+	Filename        string    `json:"filename"`
+	ContentType     string    `json:"content_type"`
+	Size            int64     `json:"size"`
+	SHA256          string    `json:"sha256"`
+	ThumbnailSHA256 string    `json:"thumbnail_sha256,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+const (
+	MailPending = "pending"
+	MailSent    = "sent"
+	MailFailed  = "failed"
+)
+
+// Mail is a rendered notification waiting in, or sent from, the mail
+// queue. EventId and Recipient are unique together, so an event relayed
+// twice does not mail anyone twice.
+type Mail struct {
+	Id            int64      `json:"id"`
+	Tenant        string     `json:"tenant"`
+	EventId       string     `json:"event_id"`
+	Template      string     `json:"template"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Text          string     `json:"-"`
+	HTML          string     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
@@ -0,0 +1,19 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// PathId parses the named path wildcard as a positive integer id.
+func PathId(r *http.Request, name string) (int64, error) {
+	raw := r.PathValue(name)
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q", name, raw)
+	}
+
+	return id, nil
+}
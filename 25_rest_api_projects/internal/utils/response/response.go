@@ -0,0 +1,100 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/faysal0x1/Go-Learn/internal/storage"
+	"github.com/go-playground/validator/v10"
+)
+
+type Response struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	StatusOK    = "OK"
+	StatusError = "Error"
+)
+
+func WriteJson(w http.ResponseWriter, status int, data interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(data)
+}
+
+// WritePDF sends a generated document for the browser to show inline.
+// Reports hold personal data, so they are never cached.
+func WritePDF(w http.ResponseWriter, filename string, body []byte) error {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	_, err := w.Write(body)
+	return err
+}
+
+func GeneralError(err error) Response {
+	return Response{
+		Status: StatusError,
+		Error:  err.Error(),
+	}
+}
+
+func ValidationError(errs validator.ValidationErrors) Response {
+	var errMsgs []string
+
+	for _, err := range errs {
+		switch err.ActualTag() {
+		case "required":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s is required", err.Field()))
+		default:
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s is invalid", err.Field()))
+		}
+	}
+
+	return Response{
+		Status: StatusError,
+		Error:  strings.Join(errMsgs, ", "),
+	}
+}
+
+// DecodeStatus is the HTTP status for a failure to read or decode a
+// request body: 413 when the body limit was hit, 400 otherwise.
+func DecodeStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
+// StorageStatus is the HTTP status matching the storage sentinel err wraps,
+// falling back to 500 for anything unexpected.
+func StorageStatus(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, storage.ErrInvalidRef):
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
+
+// StorageError writes err with the status code from StorageStatus.
+func StorageError(w http.ResponseWriter, err error) error {
+	return WriteJson(w, StorageStatus(err), GeneralError(err))
+}
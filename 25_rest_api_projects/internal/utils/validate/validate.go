@@ -0,0 +1,28 @@
+// Package validate checks values against their validate struct tags with one
+// validator shared by the REST, WebSocket, gRPC and GraphQL APIs, so every
+// transport rejects the same input with the same message.
+package validate
+
+import (
+	"errors"
+
+	"github.com/faysal0x1/Go-Learn/internal/utils/response"
+	"github.com/go-playground/validator/v10"
+)
+
+// structs caches the tag parsing of every type it has seen; a validator is
+// safe for concurrent use.
+var structs = validator.New()
+
+// Struct returns nil when v passes its tags, and otherwise an error carrying
+// the message response.ValidationError gives REST clients.
+func Struct(v any) error {
+	err := structs.Struct(v)
+
+	var validateErrs validator.ValidationErrors
+	if errors.As(err, &validateErrs) {
+		return errors.New(response.ValidationError(validateErrs).Error)
+	}
+
+	return err
+}
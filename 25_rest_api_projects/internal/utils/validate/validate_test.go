@@ -0,0 +1,33 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+func TestStructReportsFieldsLikeTheRESTResponses(t *testing.T) {
+	err := Struct(types.Student{Name: "Ada", Email: "not-an-email"})
+	if err == nil {
+		t.Fatal("invalid student passed validation")
+	}
+
+	if want := "field Email is invalid, field Age is required"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+
+	if err := Struct(types.Student{Name: "Ada", Email: "ada@example.com", Age: 36}); err != nil {
+		t.Errorf("valid student rejected: %v", err)
+	}
+}
+
+func TestStructChecksPointerFields(t *testing.T) {
+	grade := 4.5
+	if err := Struct(types.GradeRequest{Grade: &grade}); err == nil {
+		t.Error("grade above 4 passed validation")
+	}
+
+	if err := Struct(types.GradeRequest{}); err == nil || err.Error() != "field Grade is required" {
+		t.Errorf("missing grade: %v", err)
+	}
+}
@@ -0,0 +1,334 @@
+package webhook
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/tenant"
+	"github.com/faysal0x1/Go-Learn/internal/trace"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	IdHeader        = "X-Webhook-Id"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+const (
+	pollInterval = time.Second
+	batchSize    = 50
+	concurrency  = 4
+)
+
+// Store is the persistence the dispatcher and the webhook handlers need.
+// The webhook CRUD methods are scoped to one tenant; GetTenantWebhooks,
+// LookupWebhook and the delivery methods span all of them.
+type Store interface {
+	CreateWebhook(webhook types.Webhook) (int64, error)
+	GetWebhookById(id int64) (types.Webhook, error)
+	GetWebhooks() ([]types.Webhook, error)
+	GetTenantWebhooks(tenant string) ([]types.Webhook, error)
+	LookupWebhook(id int64) (types.Webhook, error)
+	DeleteWebhook(id int64) error
+	SetWebhookEnabled(id int64, enabled bool) error
+	RecordWebhookResult(id int64, ok bool, disableAfter int) (bool, error)
+
+	// CreateDelivery ignores a delivery already queued for the same
+	// webhook and event, reporting whether it inserted one.
+	CreateDelivery(delivery types.WebhookDelivery) (bool, error)
+	DueDeliveries(now time.Time, limit int) ([]types.WebhookDelivery, error)
+	GetDeliveries(webhookId int64) ([]types.WebhookDelivery, error)
+	UpdateDelivery(delivery types.WebhookDelivery) error
+}
+
+// Sign returns the hex HMAC-SHA256 of "timestamp.body" under secret, sent
+// as "sha256=<hex>" in the SignatureHeader.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature produced by Sign; receivers can use it as is.
+func Verify(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+func NewSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Dispatcher persists a delivery per matching webhook when an event is
+// published and a background loop POSTs them, retrying failures with
+// exponential backoff. Pending deliveries live in the store, so they survive
+// restarts and are picked up again by the next loop.
+type Dispatcher struct {
+	store  Store
+	cfg    config.Webhooks
+	client *http.Client
+	tracer *trace.Tracer
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(store Store, cfg config.Webhooks) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// SetTracer records a client span per delivery attempt and sends its
+// traceparent to the receiver. Call it before Start.
+func (d *Dispatcher) SetTracer(tracer *trace.Tracer) {
+	d.tracer = tracer
+}
+
+// Enqueue implements events.Queue. Only webhooks of the tenant the event
+// belongs to receive it. A delivery already queued for a webhook is left
+// alone, so an event offered again after an error is not sent twice.
+func (d *Dispatcher) Enqueue(event events.Event) error {
+	webhooks, err := d.store.GetTenantWebhooks(cmp.Or(event.Tenant, tenant.Default))
+	if err != nil {
+		return fmt.Errorf("webhook: listing subscriptions: %w", err)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		// An event that cannot be encoded never will be; retrying it
+		// would only hold back the events behind it.
+		slog.Error("webhook: encoding event", slog.String("event_id", event.Id), slog.String("error", err.Error()))
+		return nil
+	}
+
+	var errs []error
+	queued := false
+
+	for _, webhook := range webhooks {
+		if !webhook.Enabled || !subscribed(webhook, event.Type) {
+			continue
+		}
+
+		created, err := d.store.CreateDelivery(types.WebhookDelivery{
+			WebhookId:     webhook.Id,
+			EventId:       event.Id,
+			EventType:     string(event.Type),
+			Payload:       payload,
+			NextAttemptAt: time.Now().UTC(),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook %d: queueing delivery: %w", webhook.Id, err))
+			continue
+		}
+
+		queued = queued || created
+	}
+
+	if queued {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func subscribed(webhook types.Webhook, eventType events.Type) bool {
+	return len(webhook.Events) == 0 || slices.Contains(webhook.Events, string(eventType))
+}
+
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.run(ctx)
+	}()
+}
+
+// Stop ends the loop and waits for in-flight deliveries to finish.
+func (d *Dispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	deliveries, err := d.store.DueDeliveries(time.Now().UTC(), batchSize)
+	if err != nil {
+		slog.Error("webhook: loading due deliveries", slog.String("error", err.Error()))
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(delivery types.WebhookDelivery) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			d.attempt(ctx, delivery)
+		}(delivery)
+	}
+
+	wg.Wait()
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery types.WebhookDelivery) {
+	webhook, err := d.store.LookupWebhook(delivery.WebhookId)
+	if err != nil {
+		slog.Error("webhook: loading subscription", slog.Int64("webhook_id", delivery.WebhookId), slog.String("error", err.Error()))
+		return
+	}
+
+	ctx, span := d.tracer.StartSpan(ctx, "webhook.deliver", trace.KindClient)
+	span.SetAttr("webhook.id", webhook.Id)
+	span.SetAttr("event.id", delivery.EventId)
+	span.SetAttr("event.type", delivery.EventType)
+
+	code, err := d.send(ctx, webhook, delivery)
+
+	span.SetAttr("http.response.status_code", code)
+	span.SetError(err)
+	span.End()
+
+	delivery.Attempts++
+	delivery.ResponseCode = code
+	ok := err == nil
+
+	if ok {
+		now := time.Now().UTC()
+		delivery.Status = types.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = err.Error()
+
+		if delivery.Attempts >= d.cfg.MaxAttempts {
+			delivery.Status = types.DeliveryFailed
+		} else {
+			delivery.NextAttemptAt = time.Now().UTC().Add(Backoff(delivery.Attempts, d.cfg.BaseBackoff, d.cfg.MaxBackoff))
+		}
+	}
+
+	if err := d.store.UpdateDelivery(delivery); err != nil {
+		slog.Error("webhook: saving delivery", slog.Int64("delivery_id", delivery.Id), slog.String("error", err.Error()))
+	}
+
+	disabled, err := d.store.RecordWebhookResult(webhook.Id, ok, d.cfg.DisableAfter)
+	if err != nil {
+		slog.Error("webhook: recording result", slog.Int64("webhook_id", webhook.Id), slog.String("error", err.Error()))
+	}
+
+	if disabled {
+		slog.Warn("webhook disabled after repeated failures", slog.Int64("webhook_id", webhook.Id), slog.String("url", webhook.URL))
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook types.Webhook, delivery types.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(IdHeader, delivery.EventId)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, delivery.Payload))
+	trace.Inject(ctx, req.Header)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the wait before the next attempt: base doubled for every
+// previous attempt, capped at max, with jitter drawn from the upper half so
+// retries from many deliveries spread out.
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+
+	if d > max {
+		d = max
+	}
+
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+
+	return half + time.Duration(mathrand.Int64N(int64(half)+1))
+}
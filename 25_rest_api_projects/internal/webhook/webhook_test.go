@@ -0,0 +1,180 @@
+package webhook_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/faysal0x1/Go-Learn/internal/config"
+	"github.com/faysal0x1/Go-Learn/internal/events"
+	"github.com/faysal0x1/Go-Learn/internal/storage/sqlite"
+	"github.com/faysal0x1/Go-Learn/internal/types"
+	"github.com/faysal0x1/Go-Learn/internal/webhook"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"type":"student.created"}`)
+
+	signature := webhook.Sign("secret", "1700000000", body)
+
+	if !webhook.Verify("secret", "1700000000", body, signature) {
+		t.Fatal("Verify rejected a signature made by Sign")
+	}
+
+	// Known answer, computed independently, so receivers in other
+	// languages can check their implementation against ours.
+	if want := "sha256=927f5af9e1d579d198e9d8c5761ffc4cbc14e8558c1338657c382048ab434769"; signature != want {
+		t.Errorf("Sign = %s, want %s", signature, want)
+	}
+
+	tampered := []struct {
+		name, secret, timestamp string
+		body                    []byte
+	}{
+		{"secret", "other", "1700000000", body},
+		{"timestamp", "secret", "1700000001", body},
+		{"body", "secret", "1700000000", []byte(`{"type":"student.deleted"}`)},
+	}
+
+	for _, tt := range tampered {
+		if webhook.Verify(tt.secret, tt.timestamp, tt.body, signature) {
+			t.Errorf("Verify accepted a signature with a different %s", tt.name)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := time.Second, time.Minute
+
+	for attempt := 1; attempt <= 12; attempt++ {
+		want := base << (attempt - 1)
+		if want > max {
+			want = max
+		}
+
+		for range 20 {
+			got := webhook.Backoff(attempt, base, max)
+			if got < want/2 || got > want {
+				t.Fatalf("Backoff(%d) = %v, want between %v and %v", attempt, got, want/2, want)
+			}
+		}
+	}
+}
+
+func newStore(t *testing.T) *sqlite.Sqlite {
+	t.Helper()
+
+	s, err := sqlite.New(&config.Config{StoragePath: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("opening storage: %v", err)
+	}
+	t.Cleanup(func() { s.Db.Close() })
+
+	return s
+}
+
+// TestDispatcherSignsAndRetries has the receiver fail the first attempt,
+// then checks the retry carries a valid signature.
+func TestDispatcherSignsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	store := newStore(t)
+
+	webhookId, err := store.CreateWebhook(types.Webhook{URL: server.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	d := webhook.NewDispatcher(store, config.Webhooks{
+		MaxAttempts:  3,
+		DisableAfter: 10,
+		Timeout:      time.Second,
+		BaseBackoff:  time.Millisecond,
+		MaxBackoff:   time.Millisecond,
+	})
+
+	event := events.New(events.StudentCreated, 1, nil)
+	if err := d.Enqueue(event); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// The outbox offers an event again after a failure; it must not queue
+	// a second delivery.
+	if err := d.Enqueue(event); err != nil {
+		t.Fatalf("Enqueue again: %v", err)
+	}
+
+	d.Start()
+	defer d.Stop()
+
+	var r *http.Request
+	var body []byte
+	select {
+	case r = <-received:
+		body = <-bodies
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no successful delivery after %d attempts", calls.Load())
+	}
+
+	if r.Header.Get(webhook.IdHeader) != event.Id || r.Header.Get(webhook.EventHeader) != string(events.StudentCreated) {
+		t.Errorf("delivery headers = %v", r.Header)
+	}
+
+	if !webhook.Verify("secret", r.Header.Get(webhook.TimestampHeader), body, r.Header.Get(webhook.SignatureHeader)) {
+		t.Error("delivery signature does not verify")
+	}
+
+	// The receiver has answered; wait for the dispatcher to record it.
+	var delivery types.WebhookDelivery
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		deliveries, err := store.GetDeliveries(webhookId)
+		if err != nil || len(deliveries) != 1 {
+			t.Fatalf("deliveries = %+v, %v, want one", deliveries, err)
+		}
+
+		if delivery = deliveries[0]; delivery.Status != types.DeliveryPending {
+			break
+		}
+	}
+
+	if delivery.Status != types.DeliveryDelivered || delivery.Attempts != 2 {
+		t.Errorf("delivery = %+v, want delivered on the second attempt", delivery)
+	}
+}
+
+func TestDispatcherSkipsUnsubscribedEvents(t *testing.T) {
+	store := newStore(t)
+
+	webhookId, err := store.CreateWebhook(types.Webhook{URL: "http://127.0.0.1:1", Secret: "secret", Events: []string{string(events.StudentDeleted)}})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	d := webhook.NewDispatcher(store, config.Webhooks{MaxAttempts: 1})
+	if err := d.Enqueue(events.New(events.StudentCreated, 1, nil)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	deliveries, err := store.GetDeliveries(webhookId)
+	if err != nil || len(deliveries) != 0 {
+		t.Errorf("deliveries = %+v, %v, want none", deliveries, err)
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, options ...Option) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	options = append([]Option{WithBaseURL(server.URL + "/"), WithBackoff(time.Millisecond, 5*time.Millisecond)}, options...)
+	return New(options...)
+}
+
+func TestCreateStudentSendsHeadersAndFetchesTheResult(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("User-Agent") != "tests" || r.Header.Get("X-Tenant-ID") != "north" {
+			t.Errorf("%s %s headers = %v", r.Method, r.URL.Path, r.Header)
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/students":
+			var input StudentInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Name != "Ada" {
+				t.Errorf("create body = %+v, %v", input, err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]int64{"id": 7})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/students/7":
+			json.NewEncoder(w).Encode(Student{Id: 7, Name: "Ada", Status: StatusApplicant})
+		default:
+			http.NotFound(w, r)
+		}
+	}, WithToken("token"), WithUserAgent("tests"), WithHeader("X-Tenant-ID", "north"))
+
+	student, err := c.CreateStudent(context.Background(), StudentInput{Name: "Ada", Email: "ada@example.edu", Age: 20})
+	if err != nil {
+		t.Fatalf("CreateStudent: %v", err)
+	}
+
+	if student.Id != 7 || student.Status != StatusApplicant {
+		t.Errorf("student = %+v", student)
+	}
+}
+
+func TestErrorsUnwrapToSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusUnprocessableEntity, ErrInvalidRef},
+		{http.StatusInternalServerError, ErrServer},
+	}
+
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(`{"status":"Error","error":"something went wrong"}`))
+		}, WithRetries(0))
+
+		err := c.DeleteStudent(context.Background(), 1)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: error = %v, want %v", tt.status, err, tt.want)
+		}
+
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Message != "something went wrong" {
+			t.Errorf("status %d: error = %#v, want the server's message", tt.status, err)
+		}
+	}
+}
+
+func TestIdempotentRequestsAreRetried(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode([]Student{{Id: 1}})
+	}, WithRetries(3))
+
+	students, err := c.ListStudents(context.Background())
+	if err != nil || len(students) != 1 {
+		t.Fatalf("ListStudents = %+v, %v", students, err)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestCreatesAreOnlyRetriedWhenNotProcessed(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}, WithRetries(3))
+
+	if _, err := c.CreateStudent(context.Background(), StudentInput{Name: "Ada"}); !errors.Is(err, ErrServer) {
+		t.Fatalf("CreateStudent error = %v, want ErrServer", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("a create failing with 500 was sent %d times, want once", calls.Load())
+	}
+
+	calls.Store(0)
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, WithRetries(2))
+
+	if _, err := c.CreateStudent(context.Background(), StudentInput{Name: "Ada"}); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("CreateStudent error = %v, want ErrRateLimited", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("a rate-limited create was sent %d times, want 3", calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("2"); got != 2*time.Second {
+		t.Errorf("seconds form = %v, want 2s", got)
+	}
+
+	at := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(at); got <= 0 || got > time.Minute {
+		t.Errorf("date form = %v, want up to a minute", got)
+	}
+
+	if got := parseRetryAfter("soon"); got != 0 {
+		t.Errorf("invalid value = %v, want 0", got)
+	}
+}
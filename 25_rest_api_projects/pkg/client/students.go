@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Student lifecycle statuses as reported in Student.Status.
+const (
+	StatusApplicant = "applicant"
+	StatusEnrolled  = "enrolled"
+	StatusSuspended = "suspended"
+	StatusGraduated = "graduated"
+	StatusWithdrawn = "withdrawn"
+)
+
+type Student struct {
+	Id              int64     `json:"id"`
+	Name            string    `json:"name"`
+	Email           string    `json:"email"`
+	Age             int       `json:"age"`
+	Status          string    `json:"status"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
+}
+
+// StudentInput holds the writable fields of a student.
+type StudentInput struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+type StudentTransition struct {
+	Id        int64     `json:"id"`
+	StudentId int64     `json:"student_id"`
+	Event     string    `json:"event"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Enrollment struct {
+	Id         int64     `json:"id"`
+	StudentId  int64     `json:"student_id"`
+	CourseId   int64     `json:"course_id"`
+	Grade      *float64  `json:"grade,omitempty"`
+	EnrolledAt time.Time `json:"enrolled_at"`
+}
+
+type StudentGPA struct {
+	StudentId int64   `json:"student_id"`
+	Courses   int     `json:"courses"`
+	Credits   int     `json:"credits"`
+	GPA       float64 `json:"gpa"`
+}
+
+// CreateStudent creates a student and returns it as stored, including its
+// initial status.
+func (c *Client) CreateStudent(ctx context.Context, input StudentInput) (Student, error) {
+	var created struct {
+		Id int64 `json:"id"`
+	}
+
+	if err := c.do(ctx, http.MethodPost, "/api/students", input, &created); err != nil {
+		return Student{}, err
+	}
+
+	return c.GetStudent(ctx, created.Id)
+}
+
+func (c *Client) GetStudent(ctx context.Context, id int64) (Student, error) {
+	var student Student
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/students/%d", id), nil, &student)
+	return student, err
+}
+
+func (c *Client) ListStudents(ctx context.Context) ([]Student, error) {
+	var students []Student
+	err := c.do(ctx, http.MethodGet, "/api/students", nil, &students)
+	return students, err
+}
+
+func (c *Client) UpdateStudent(ctx context.Context, id int64, input StudentInput) (Student, error) {
+	var student Student
+	err := c.do(ctx, http.MethodPut, fmt.Sprintf("/api/students/%d", id), input, &student)
+	return student, err
+}
+
+func (c *Client) DeleteStudent(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/students/%d", id), nil, nil)
+}
+
+// TransitionStudent applies a lifecycle event such as "enroll" or
+// "graduate". An event not allowed from the current status fails with
+// ErrConflict; an unknown event with ErrBadRequest.
+func (c *Client) TransitionStudent(ctx context.Context, id int64, event string) (StudentTransition, error) {
+	var transition StudentTransition
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/students/%d/transitions", id), map[string]string{"event": event}, &transition)
+	return transition, err
+}
+
+func (c *Client) StudentTransitions(ctx context.Context, id int64) ([]StudentTransition, error) {
+	var transitions []StudentTransition
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/students/%d/transitions", id), nil, &transitions)
+	return transitions, err
+}
+
+func (c *Client) StudentEnrollments(ctx context.Context, id int64) ([]Enrollment, error) {
+	var enrollments []Enrollment
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/students/%d/enrollments", id), nil, &enrollments)
+	return enrollments, err
+}
+
+func (c *Client) StudentGPA(ctx context.Context, id int64) (StudentGPA, error) {
+	var gpa StudentGPA
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/students/%d/gpa", id), nil, &gpa)
+	return gpa, err
+}